@@ -0,0 +1,57 @@
+package alsa_test
+
+import (
+	"strings"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	alsa "github.com/djthorpe/gopi-media/alsa"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST DEVICES
+
+func Test_alsa_000(t *testing.T) {
+	t.Log("Test_alsa_000")
+}
+
+func Test_alsa_001(t *testing.T) {
+	// Device list from a Raspberry Pi with a USB audio adapter
+	devices, err := alsa.ReadDevices(strings.NewReader(`00-00: bcm2835 Headphones : bcm2835 Headphones : playback 8
+01-00: MAI PCM i2s-hifi-0 : MAI PCM i2s-hifi-0 : playback 1
+02-00: USB Audio : USB Audio : playback 1 : capture 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(devices) != 3 {
+		t.Fatal("Unexpected devices", devices)
+	} else if devices[0].Card != 0 || devices[0].Name != "bcm2835 Headphones" || devices[0].Playback != 8 {
+		t.Error("Unexpected device", devices[0])
+	} else if devices[2].Card != 2 || devices[2].Device != 0 || devices[2].Playback != 1 || devices[2].Capture != 1 {
+		t.Error("Unexpected device", devices[2])
+	}
+
+	if device, err := alsa.DeviceForName(devices, "usb"); err != nil {
+		t.Error(err)
+	} else if device.Card != 2 {
+		t.Error("Unexpected device", device)
+	}
+	if _, err := alsa.DeviceForName(devices, "spdif"); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if _, err := alsa.ReadDevices(strings.NewReader("invalid\n")); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
+
+func Test_alsa_002(t *testing.T) {
+	format := media.AudioFormat{Format: media.SAMPLE_FORMAT_S24, Rate: 48000, Channels: 2}
+	if size := format.BytesPerFrame(); size != 6 {
+		t.Error("Unexpected frame size", size)
+	}
+	if size := media.SAMPLE_FORMAT_NONE.BytesPerSample(); size != 0 {
+		t.Error("Unexpected sample size", size)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package alsa
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Device is a PCM device on a sound card
+type Device struct {
+	Card, Device uint
+
+	// Identifier and name of the device, for example "bcm2835 Headphones"
+	Id, Name string
+
+	// Number of playback and capture substreams
+	Playback, Capture uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	PROC_PCM = "/proc/asound/pcm"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DEVICES
+
+// Devices returns the PCM devices on the system
+func Devices() ([]Device, error) {
+	if fh, err := os.Open(PROC_PCM); os.IsNotExist(err) {
+		// There are no sound cards
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return ReadDevices(fh)
+	}
+}
+
+// ReadDevices parses a device list in the format of /proc/asound/pcm,
+// where each line is in the form "00-00: id : name : playback 1"
+func ReadDevices(r io.Reader) ([]Device, error) {
+	devices := make([]Device, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) < 3 {
+			return nil, gopi.ErrBadParameter
+		}
+		device := Device{
+			Id:   strings.TrimSpace(fields[1]),
+			Name: strings.TrimSpace(fields[2]),
+		}
+		if _, err := fmt.Sscanf(fields[0], "%d-%d", &device.Card, &device.Device); err != nil {
+			return nil, gopi.ErrBadParameter
+		}
+		for _, field := range fields[3:] {
+			if value := strings.Fields(field); len(value) != 2 {
+				continue
+			} else if n, err := strconv.ParseUint(value[1], 10, 32); err != nil {
+				continue
+			} else if value[0] == "playback" {
+				device.Playback = uint(n)
+			} else if value[0] == "capture" {
+				device.Capture = uint(n)
+			}
+		}
+		devices = append(devices, device)
+	}
+	return devices, scanner.Err()
+}
+
+// DeviceForName returns the first playback device where the identifier
+// or name contains a string, ignoring case, for example "HDMI" or "USB"
+func DeviceForName(devices []Device, name string) (Device, error) {
+	name = strings.ToLower(name)
+	for _, device := range devices {
+		if device.Playback == 0 {
+			continue
+		}
+		if strings.Contains(strings.ToLower(device.Id), name) || strings.Contains(strings.ToLower(device.Name), name) {
+			return device, nil
+		}
+	}
+	return Device{}, gopi.ErrNotFound
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Device) String() string {
+	return fmt.Sprintf("<alsa.Device>{ card=%v device=%v id=%v name=%v playback=%v capture=%v }", this.Card, this.Device, strconv.Quote(this.Id), strconv.Quote(this.Name), this.Playback, this.Capture)
+}
@@ -0,0 +1,361 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package alsa
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// PCM is a playback device, which is used through the kernel interface
+// so the ALSA library is not required
+type PCM struct {
+	fh     *os.File
+	format media.AudioFormat
+	buffer uint
+	period uint
+}
+
+// snd_mask mirrors struct snd_mask
+type sndMask struct {
+	bits [8]uint32
+}
+
+// snd_interval mirrors struct snd_interval, where the flags are the
+// openmin, openmax, integer and empty bit fields
+type sndInterval struct {
+	min, max uint32
+	flags    uint32
+}
+
+// sndHwParams mirrors struct snd_pcm_hw_params
+type sndHwParams struct {
+	flags     uint32
+	masks     [3]sndMask
+	mres      [5]sndMask
+	intervals [12]sndInterval
+	ires      [9]sndInterval
+	rmask     uint32
+	cmask     uint32
+	info      uint32
+	msbits    uint32
+	rate_num  uint32
+	rate_den  uint32
+	fifo_size uintptr
+	reserved  [64]byte
+}
+
+// sndXferi mirrors struct snd_xferi
+type sndXferi struct {
+	result int
+	buf    uintptr
+	frames uintptr
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Default size of the device buffer
+	DEFAULT_BUFFER = 500 * time.Millisecond
+)
+
+const (
+	// Parameters from sound/asound.h
+	paramAccess     = 0
+	paramFormat     = 1
+	paramChannels   = 10
+	paramRate       = 11
+	paramPeriodTime = 12
+	paramPeriodSize = 13
+	paramBufferTime = 16
+	paramBufferSize = 17
+	paramFirstInt   = 8
+
+	accessRWInterleaved = 3
+	intervalEmpty       = 0x08
+)
+
+var (
+	pcmHwRefine    = ioctlReadWrite('A', 0x10, unsafe.Sizeof(sndHwParams{}))
+	pcmHwParams    = ioctlReadWrite('A', 0x11, unsafe.Sizeof(sndHwParams{}))
+	pcmPrepare     = ioctl('A', 0x40)
+	pcmDrop        = ioctl('A', 0x43)
+	pcmDrain       = ioctl('A', 0x44)
+	pcmWriteFrames = ioctlWrite('A', 0x50, unsafe.Sizeof(sndXferi{}))
+
+	// Formats from sound/asound.h
+	pcmFormats = map[media.SampleFormat]uint{
+		media.SAMPLE_FORMAT_U8:      1,
+		media.SAMPLE_FORMAT_S16:     2,
+		media.SAMPLE_FORMAT_S32:     10,
+		media.SAMPLE_FORMAT_FLOAT32: 14,
+		media.SAMPLE_FORMAT_S24:     32,
+	}
+
+	// Formats to try, in order, when a format is not supported
+	pcmPreferred = []media.SampleFormat{
+		media.SAMPLE_FORMAT_S16,
+		media.SAMPLE_FORMAT_S32,
+		media.SAMPLE_FORMAT_S24,
+		media.SAMPLE_FORMAT_FLOAT32,
+		media.SAMPLE_FORMAT_U8,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+// OpenPCM opens a playback device. SetFormat must be called before
+// audio is written.
+func OpenPCM(card, device uint) (*PCM, error) {
+	if fh, err := os.OpenFile(fmt.Sprintf("/dev/snd/pcmC%vD%vp", card, device), os.O_RDWR, 0); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		return &PCM{fh: fh}, nil
+	}
+}
+
+// Close releases the device, discarding audio which has not been played
+func (this *PCM) Close() error {
+	return this.fh.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FORMAT
+
+// SetFormat sets the format and the size of the device buffer,
+// returning the nearest format the device supports. A format or sample
+// rate which is not supported is replaced and the number of channels
+// is limited to the range the device supports.
+func (this *PCM) SetFormat(format media.AudioFormat, buffer time.Duration) (media.AudioFormat, error) {
+	if format.Rate == 0 || format.Channels == 0 {
+		return media.AudioFormat{}, gopi.ErrBadParameter
+	}
+	if buffer <= 0 {
+		buffer = DEFAULT_BUFFER
+	}
+
+	// Return the formats, channels and rates the device supports
+	params := newHwParams()
+	params.setMask(paramAccess, accessRWInterleaved)
+	if err := this.ioctl(pcmHwRefine, unsafe.Pointer(params)); err != nil {
+		return media.AudioFormat{}, err
+	}
+
+	// Choose the format and number of channels
+	if value, exists := pcmFormats[format.Format]; exists == false || params.hasMask(paramFormat, value) == false {
+		format.Format = media.SAMPLE_FORMAT_NONE
+		for _, f := range pcmPreferred {
+			if params.hasMask(paramFormat, pcmFormats[f]) {
+				format.Format = f
+				break
+			}
+		}
+		if format.Format == media.SAMPLE_FORMAT_NONE {
+			return media.AudioFormat{}, gopi.ErrNotFound
+		}
+	}
+	channels := params.interval(paramChannels)
+	if format.Channels < uint(channels.min) {
+		format.Channels = uint(channels.min)
+	} else if format.Channels > uint(channels.max) {
+		format.Channels = uint(channels.max)
+	}
+	params.setMask(paramFormat, pcmFormats[format.Format])
+	params.setInterval(paramChannels, uint32(format.Channels), uint32(format.Channels))
+
+	// Limit the buffer, with between four and eight periods in it,
+	// since the device otherwise chooses the smallest period
+	us := uint32(buffer / time.Microsecond)
+	params.setInterval(paramBufferTime, 0, us)
+	params.setInterval(paramPeriodTime, us/8, us/4)
+
+	// Use the rate if it is supported, otherwise the next highest or
+	// next lowest rate
+	rates := params.interval(paramRate)
+	for _, rate := range [][2]uint32{{uint32(format.Rate), uint32(format.Rate)}, {uint32(format.Rate), rates.max}, {rates.min, uint32(format.Rate)}} {
+		try := *params
+		try.setInterval(paramRate, rate[0], rate[1])
+		if err := this.ioctl(pcmHwRefine, unsafe.Pointer(&try)); err == nil {
+			*params = try
+			break
+		}
+	}
+
+	// Set the parameters, where the device chooses any values which
+	// are not fixed
+	if err := this.ioctl(pcmHwParams, unsafe.Pointer(params)); err != nil {
+		return media.AudioFormat{}, err
+	}
+	format.Channels = uint(params.interval(paramChannels).min)
+	format.Rate = uint(params.interval(paramRate).min)
+	this.format = format
+	this.buffer = uint(params.interval(paramBufferSize).min)
+	this.period = uint(params.interval(paramPeriodSize).min)
+
+	// Prepare for playback
+	if err := this.Prepare(); err != nil {
+		return media.AudioFormat{}, err
+	}
+
+	// Success
+	return format, nil
+}
+
+// Format returns the format set with SetFormat
+func (this *PCM) Format() media.AudioFormat {
+	return this.format
+}
+
+// BufferSize returns the size of the device buffer and of each period
+// in frames
+func (this *PCM) BufferSize() (uint, uint) {
+	return this.buffer, this.period
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PLAYBACK
+
+// Write writes whole frames of audio, blocking while the device buffer
+// is full, and returns the number of bytes written. Returns an error for
+// which IsUnderrun returns true when the device ran out of audio, after
+// which Prepare must be called to restart playback.
+func (this *PCM) Write(data []byte) (int, error) {
+	size := this.format.BytesPerFrame()
+	if size == 0 {
+		return 0, gopi.ErrOutOfOrder
+	}
+	frames := uint(len(data)) / size
+	if frames == 0 {
+		return 0, nil
+	}
+	xfer := sndXferi{
+		buf:    uintptr(unsafe.Pointer(&data[0])),
+		frames: uintptr(frames),
+	}
+	err := this.ioctl(pcmWriteFrames, unsafe.Pointer(&xfer))
+	runtime.KeepAlive(data)
+	if err != nil {
+		return 0, err
+	}
+	return xfer.result * int(size), nil
+}
+
+// Prepare readies the device for playback after SetFormat, Stop or
+// an underrun
+func (this *PCM) Prepare() error {
+	return this.ioctl(pcmPrepare, nil)
+}
+
+// Drain waits until the audio which has been written has been played
+func (this *PCM) Drain() error {
+	return this.ioctl(pcmDrain, nil)
+}
+
+// Stop stops playback immediately, discarding audio which has been
+// written. Prepare must be called before more audio is written.
+func (this *PCM) Stop() error {
+	return this.ioctl(pcmDrop, nil)
+}
+
+// IsUnderrun returns true if an error from Write is because the device
+// ran out of audio
+func IsUnderrun(err error) bool {
+	if err, ok := err.(*os.SyscallError); ok {
+		return err.Err == syscall.EPIPE
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *PCM) String() string {
+	return fmt.Sprintf("<alsa.PCM>{ device=%v format=%v buffer=%v period=%v }", this.fh.Name(), this.format, this.buffer, this.period)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *PCM) ioctl(request uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), request, uintptr(arg)); errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	return nil
+}
+
+// newHwParams returns parameters which allow any configuration
+func newHwParams() *sndHwParams {
+	params := new(sndHwParams)
+	for i := range params.masks {
+		for j := range params.masks[i].bits {
+			params.masks[i].bits[j] = ^uint32(0)
+		}
+	}
+	for i := range params.intervals {
+		params.intervals[i].max = ^uint32(0)
+	}
+	params.rmask = ^uint32(0)
+	return params
+}
+
+// setMask restricts a mask to a single value
+func (this *sndHwParams) setMask(param int, value uint) {
+	this.masks[param] = sndMask{}
+	this.masks[param].bits[value>>5] = 1 << (value & 31)
+}
+
+func (this *sndHwParams) hasMask(param int, value uint) bool {
+	return this.masks[param].bits[value>>5]&(1<<(value&31)) != 0
+}
+
+func (this *sndHwParams) interval(param int) sndInterval {
+	return this.intervals[param-paramFirstInt]
+}
+
+// setInterval restricts an interval to a closed range, where a
+// maximum of zero leaves the maximum unchanged
+func (this *sndHwParams) setInterval(param int, min, max uint32) {
+	interval := &this.intervals[param-paramFirstInt]
+	interval.min = min
+	if max != 0 {
+		interval.max = max
+	}
+	interval.flags &^= intervalEmpty | 0x03
+}
+
+func ioctl(t, nr uintptr) uintptr {
+	return (t << 8) | nr
+}
+
+func ioctlWrite(t, nr, size uintptr) uintptr {
+	return (1 << 30) | (size << 16) | ioctl(t, nr)
+}
+
+func ioctlReadWrite(t, nr, size uintptr) uintptr {
+	return (3 << 30) | (size << 16) | ioctl(t, nr)
+}
@@ -0,0 +1,374 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package anime
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Show is an anime series returned by AniList or AniDB
+type Show struct {
+	AniListId string
+	AniDBId   string
+	Title     string
+	English   string
+	Episodes  uint
+	Started   time.Time
+
+	// Episode titles and air dates, which only AniDB returns
+	EpisodeList []Episode
+}
+
+// Episode is a regular episode of a show, numbered from one
+type Episode struct {
+	Number uint
+	Title  string
+	Aired  time.Time
+}
+
+// AniList searches the AniList GraphQL service, which needs no key
+type AniList struct {
+	// Endpoint overrides the default service URL
+	Endpoint string
+}
+
+// AniDB looks up shows by AniDB identifier using the AniDB HTTP API.
+// Clients must be registered with AniDB, and are banned for making
+// more than one request every two seconds, so a util.Transport with
+// an interval should be used.
+type AniDB struct {
+	// Client and Version are the registered client name and version
+	Client  string
+	Version uint
+
+	// Endpoint overrides the default service URL
+	Endpoint string
+}
+
+type anilistResponse struct {
+	Data struct {
+		Page struct {
+			Media []struct {
+				Id    uint `json:"id"`
+				Title struct {
+					Romaji  string `json:"romaji"`
+					English string `json:"english"`
+				} `json:"title"`
+				Episodes  uint `json:"episodes"`
+				StartDate struct {
+					Year  int `json:"year"`
+					Month int `json:"month"`
+					Day   int `json:"day"`
+				} `json:"startDate"`
+			} `json:"media"`
+		} `json:"Page"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type anidbTitle struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type anidbResponse struct {
+	XMLName      xml.Name
+	Id           string       `xml:"id,attr"`
+	Error        string       `xml:",chardata"`
+	EpisodeCount uint         `xml:"episodecount"`
+	StartDate    string       `xml:"startdate"`
+	Titles       []anidbTitle `xml:"titles>title"`
+	Episodes     []struct {
+		Number struct {
+			Type  uint   `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"epno"`
+		Aired  string       `xml:"airdate"`
+		Titles []anidbTitle `xml:"title"`
+	} `xml:"episodes>episode"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	ANILIST_ENDPOINT = "https://graphql.anilist.co"
+	ANIDB_ENDPOINT   = "http://api.anidb.net:9001/httpapi"
+	ANILIST_PERPAGE  = 10
+)
+
+const (
+	anilistQuery = `query ($search: String, $perPage: Int) {
+  Page(perPage: $perPage) {
+    media(search: $search, type: ANIME) {
+      id title { romaji english } episodes startDate { year month day }
+    }
+  }
+}`
+	anidbDate = "2006-01-02"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// ANILIST
+
+// Search returns the shows which match a title, best match first, or
+// gopi.ErrNotFound if there are none
+func (this AniList) Search(client *http.Client, title string) ([]Show, error) {
+	endpoint := this.Endpoint
+	if endpoint == "" {
+		endpoint = ANILIST_ENDPOINT
+	}
+	if client == nil {
+		client = util.BackgroundClient
+	}
+	if title = strings.TrimSpace(title); title == "" {
+		return nil, gopi.ErrBadParameter
+	}
+
+	// Make the request
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     anilistQuery,
+		"variables": map[string]interface{}{"search": title, "perPage": ANILIST_PERPAGE},
+	})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// Errors are returned in the body with the status, but a search with
+	// no results is not an error
+	var data anilistResponse
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil && response.StatusCode == http.StatusOK {
+		return nil, err
+	} else if len(data.Errors) > 0 {
+		return nil, fmt.Errorf("%v: %v", response.Status, data.Errors[0].Message)
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v", response.Status)
+	}
+
+	shows := make([]Show, 0, len(data.Data.Page.Media))
+	for _, result := range data.Data.Page.Media {
+		show := Show{
+			AniListId: fmt.Sprint(result.Id),
+			Title:     result.Title.Romaji,
+			English:   result.Title.English,
+			Episodes:  result.Episodes,
+		}
+		if date := result.StartDate; date.Year > 0 {
+			if date.Month == 0 {
+				date.Month, date.Day = 1, 1
+			} else if date.Day == 0 {
+				date.Day = 1
+			}
+			show.Started = time.Date(date.Year, time.Month(date.Month), date.Day, 0, 0, 0, 0, time.UTC)
+		}
+		shows = append(shows, show)
+	}
+	if len(shows) == 0 {
+		return nil, gopi.ErrNotFound
+	} else {
+		return shows, nil
+	}
+}
+
+func (this AniList) String() string {
+	return fmt.Sprintf("<anime.AniList>{ endpoint=%v }", strconv.Quote(this.Endpoint))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ANIDB
+
+// Lookup returns the show with an AniDB identifier, or gopi.ErrNotFound
+// if there is no such show
+func (this AniDB) Lookup(client *http.Client, id string) (Show, error) {
+	var show Show
+
+	endpoint := this.Endpoint
+	if endpoint == "" {
+		endpoint = ANIDB_ENDPOINT
+	}
+	if client == nil {
+		client = util.BackgroundClient
+	}
+	if this.Client == "" {
+		return show, gopi.ErrBadParameter
+	} else if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+		return show, gopi.ErrBadParameter
+	}
+
+	// Make the request
+	query := url.Values{}
+	query.Set("request", "anime")
+	query.Set("client", this.Client)
+	query.Set("clientver", fmt.Sprint(this.Version))
+	query.Set("protover", "1")
+	query.Set("aid", id)
+	response, err := client.Get(endpoint + "?" + query.Encode())
+	if err != nil {
+		return show, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return show, fmt.Errorf("%v", response.Status)
+	}
+
+	// Errors are returned with an OK status
+	var data anidbResponse
+	if err := xml.NewDecoder(response.Body).Decode(&data); err != nil {
+		return show, err
+	} else if data.XMLName.Local == "error" {
+		if strings.Contains(strings.ToLower(data.Error), "not found") {
+			return show, gopi.ErrNotFound
+		} else {
+			return show, fmt.Errorf("AniDB: %v", strings.TrimSpace(data.Error))
+		}
+	} else if data.XMLName.Local != "anime" {
+		return show, gopi.ErrUnexpectedResponse
+	}
+
+	show.AniDBId = data.Id
+	show.Title = title(data.Titles, "x-jat")
+	show.English = title(data.Titles, "en")
+	show.Episodes = data.EpisodeCount
+	if started, err := time.Parse(anidbDate, data.StartDate); err == nil {
+		show.Started = started
+	}
+
+	// Return the regular episodes in order, ignoring specials, credits
+	// and trailers which have other episode types
+	for _, ep := range data.Episodes {
+		if ep.Number.Type != 1 {
+			continue
+		}
+		number, err := strconv.ParseUint(strings.TrimSpace(ep.Number.Value), 10, 32)
+		if err != nil {
+			continue
+		}
+		episode := Episode{Number: uint(number), Title: title(ep.Titles, "en")}
+		if aired, err := time.Parse(anidbDate, ep.Aired); err == nil {
+			episode.Aired = aired
+		}
+		show.EpisodeList = append(show.EpisodeList, episode)
+	}
+	sort.Slice(show.EpisodeList, func(i, j int) bool {
+		return show.EpisodeList[i].Number < show.EpisodeList[j].Number
+	})
+
+	// Success
+	return show, nil
+}
+
+func (this AniDB) String() string {
+	return fmt.Sprintf("<anime.AniDB>{ client=%v version=%v endpoint=%v }", strconv.Quote(this.Client), this.Version, strconv.Quote(this.Endpoint))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SHOW
+
+// Metadata returns the metadata for a show. Anime is numbered by absolute
+// episode, so the show uses absolute episode order.
+func (this Show) Metadata() map[media.MetadataKey]string {
+	keys := map[media.MetadataKey]string{
+		media.METADATA_KEY_SHOW:          this.Title,
+		media.METADATA_KEY_TITLE:         this.Title,
+		media.METADATA_KEY_EPISODE_ORDER: fmt.Sprint(uint(media.EPISODE_ORDER_ABSOLUTE)),
+	}
+	if this.AniListId != "" {
+		keys[media.METADATA_KEY_ANILIST_ID] = this.AniListId
+	}
+	if this.AniDBId != "" {
+		keys[media.METADATA_KEY_ANIDB_ID] = this.AniDBId
+	}
+	if this.Started.IsZero() == false {
+		keys[media.METADATA_KEY_YEAR] = this.Started.Format(anidbDate)
+	}
+	return keys
+}
+
+// Episode returns the episode with an absolute number, or false if the
+// show has no such episode
+func (this Show) Episode(number uint) (Episode, bool) {
+	for _, episode := range this.EpisodeList {
+		if episode.Number == number {
+			return episode, true
+		}
+	}
+	return Episode{}, false
+}
+
+func (this Show) String() string {
+	return fmt.Sprintf("<anime.Show>{ anilist=%v anidb=%v title=%v english=%v episodes=%v }",
+		strconv.Quote(this.AniListId), strconv.Quote(this.AniDBId), strconv.Quote(this.Title), strconv.Quote(this.English), this.Episodes)
+}
+
+// Metadata returns the metadata for an episode
+func (this Episode) Metadata() map[media.MetadataKey]string {
+	keys := map[media.MetadataKey]string{
+		media.METADATA_KEY_ABSOLUTE_EPISODE: fmt.Sprint(this.Number),
+	}
+	if this.Title != "" {
+		keys[media.METADATA_KEY_TITLE] = this.Title
+	}
+	if this.Aired.IsZero() == false {
+		keys[media.METADATA_KEY_AIRED] = this.Aired.Format(anidbDate)
+	}
+	return keys
+}
+
+func (this Episode) String() string {
+	return fmt.Sprintf("<anime.Episode>{ number=%v title=%v }", this.Number, strconv.Quote(this.Title))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// title returns the main title, or the official title in a language,
+// or the first title
+func title(titles []anidbTitle, lang string) string {
+	for _, t := range titles {
+		if t.Lang == lang && (t.Type == "main" || t.Type == "official" || t.Type == "") {
+			return strings.TrimSpace(t.Value)
+		}
+	}
+	if len(titles) > 0 && lang == "x-jat" {
+		return strings.TrimSpace(titles[0].Value)
+	}
+	return ""
+}
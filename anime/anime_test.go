@@ -0,0 +1,132 @@
+package anime_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	anime "github.com/djthorpe/gopi-media/anime"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST FILENAMES AND SCRAPERS
+
+func Test_anime_000(t *testing.T) {
+	t.Log("Test_anime_000")
+}
+
+func Test_anime_001(t *testing.T) {
+	tests := []struct {
+		filename string
+		name     anime.Name
+	}{
+		{"[HorribleSubs] Shingeki no Kyojin - 01 [720p].mkv", anime.Name{Group: "HorribleSubs", Title: "Shingeki no Kyojin", Episode: 1, Resolution: "720p"}},
+		{"/anime/[Group] Some Show - 112v2 (1920x1080 x264 AAC) [ABCD1234].mkv", anime.Name{Group: "Group", Title: "Some Show", Episode: 112, Version: 2, Resolution: "1080p", Checksum: "ABCD1234"}},
+		{"[Group]_Some_Show_-_07_[1080p][deadbeef].mp4", anime.Name{Group: "Group", Title: "Some Show", Episode: 7, Resolution: "1080p", Checksum: "DEADBEEF"}},
+		{"[Group] Some Show 24 [BD].mkv", anime.Name{Group: "Group", Title: "Some Show", Episode: 24}},
+		{"Some Show - 03 END.mkv", anime.Name{Title: "Some Show", Episode: 3}},
+	}
+	for _, test := range tests {
+		if name, err := anime.Parse(test.filename); err != nil {
+			t.Error(test.filename, err)
+		} else if name != test.name {
+			t.Error("Unexpected name for", test.filename, name)
+		}
+	}
+	for _, filename := range []string{"Some Show S01E03.mkv", "[Group] Some Show 1x03.mkv", "Movie 2012.mkv", "[Group] Title.mkv"} {
+		if _, err := anime.Parse(filename); err != gopi.ErrBadParameter {
+			t.Error("Expected ErrBadParameter for", filename, err)
+		}
+	}
+
+	name, _ := anime.Parse("[Group] Some Show - 12 [720p].mkv")
+	if keys := name.Metadata(); keys[media.METADATA_KEY_SHOW] != "Some Show" || keys[media.METADATA_KEY_ABSOLUTE_EPISODE] != "12" || keys[media.METADATA_KEY_ENCODED_BY] != "Group" {
+		t.Error("Unexpected metadata", keys)
+	}
+}
+
+func Test_anime_002(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Variables struct {
+				Search string `json:"search"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || r.Method != "POST" {
+			http.Error(w, `{"errors":[{"message":"Bad request"}]}`, http.StatusBadRequest)
+		} else if request.Variables.Search != "Some Show" {
+			w.Write([]byte(`{"data":{"Page":{"media":[]}}}`))
+		} else {
+			w.Write([]byte(`{"data":{"Page":{"media":[{"id":42,"title":{"romaji":"Some Show","english":"A Show"},"episodes":24,"startDate":{"year":2013,"month":4,"day":null}}]}}}`))
+		}
+	}))
+	defer server.Close()
+
+	anilist := anime.AniList{Endpoint: server.URL}
+	if shows, err := anilist.Search(nil, "Some Show"); err != nil {
+		t.Error(err)
+	} else if len(shows) != 1 || shows[0].AniListId != "42" || shows[0].English != "A Show" || shows[0].Episodes != 24 {
+		t.Error("Unexpected shows", shows)
+	} else if keys := shows[0].Metadata(); keys[media.METADATA_KEY_YEAR] != "2013-04-01" || keys[media.METADATA_KEY_EPISODE_ORDER] != "2" || keys[media.METADATA_KEY_ANILIST_ID] != "42" {
+		t.Error("Unexpected metadata", keys)
+	}
+	if _, err := anilist.Search(nil, "Other Show"); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if _, err := anilist.Search(nil, " "); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
+
+func Test_anime_003(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("client") != "gopimedia" || r.URL.Query().Get("request") != "anime" {
+			w.Write([]byte(`<error>Client values missing or invalid</error>`))
+		} else if r.URL.Query().Get("aid") != "1" {
+			w.Write([]byte(`<error>Anime not found</error>`))
+		} else {
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<anime id="1" restricted="false">
+	<episodecount>2</episodecount>
+	<startdate>1999-01-03</startdate>
+	<titles>
+		<title xml:lang="en" type="official">A Show</title>
+		<title xml:lang="x-jat" type="main">Some Show</title>
+	</titles>
+	<episodes>
+		<episode id="12"><epno type="1">2</epno><airdate>1999-01-10</airdate><title xml:lang="en">Second</title></episode>
+		<episode id="13"><epno type="2">S1</epno><title xml:lang="en">Special</title></episode>
+		<episode id="11"><epno type="1">1</epno><airdate>1999-01-03</airdate><title xml:lang="ja">Ichi</title><title xml:lang="en">First</title></episode>
+	</episodes>
+</anime>`))
+		}
+	}))
+	defer server.Close()
+
+	anidb := anime.AniDB{Client: "gopimedia", Version: 1, Endpoint: server.URL}
+	if show, err := anidb.Lookup(nil, "1"); err != nil {
+		t.Error(err)
+	} else if show.AniDBId != "1" || show.Title != "Some Show" || show.English != "A Show" || len(show.EpisodeList) != 2 {
+		t.Error("Unexpected show", show)
+	} else if episode, ok := show.Episode(1); ok == false || episode.Title != "First" {
+		t.Error("Unexpected episode", episode)
+	} else if keys := episode.Metadata(); keys[media.METADATA_KEY_AIRED] != "1999-01-03" || keys[media.METADATA_KEY_ABSOLUTE_EPISODE] != "1" {
+		t.Error("Unexpected metadata", keys)
+	} else if _, ok := show.Episode(3); ok {
+		t.Error("Unexpected episode 3")
+	}
+	if _, err := anidb.Lookup(nil, "2"); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if _, err := anidb.Lookup(nil, "abc"); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+	anidb.Client = "other"
+	if _, err := anidb.Lookup(nil, "1"); err == nil || err == gopi.ErrNotFound {
+		t.Error("Expected error, got", err)
+	}
+}
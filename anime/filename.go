@@ -0,0 +1,151 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package anime
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Name is a fansub release parsed from a filename such as
+// "[Group] Title - 01v2 [720p][ABCD1234].mkv"
+type Name struct {
+	// Group is the fansub group which made the release
+	Group string
+
+	// Title of the show
+	Title string
+
+	// Episode is the absolute episode number
+	Episode uint
+
+	// Version is the release version, or zero when not given
+	Version uint
+
+	// Resolution is the vertical resolution, for example "720p"
+	Resolution string
+
+	// Checksum is the CRC32 of the file, in upper case
+	Checksum string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	reGroup      = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*`)
+	reTag        = regexp.MustCompile(`[\[\(]([^\]\)]*)[\]\)]`)
+	reResolution = regexp.MustCompile(`(?i)^(?:\d{3,4}x)?(\d{3,4})p?$`)
+	reChecksum   = regexp.MustCompile(`^[0-9A-Fa-f]{8}$`)
+	reSeason     = regexp.MustCompile(`(?i)\bS\d{1,2}E\d{1,3}\b|\b\d{1,2}x\d{2,3}\b`)
+	reDash       = regexp.MustCompile(`^(.+?)\s+-\s+(\d{1,4})(?:v(\d))?(?:\s.*)?$`)
+	reNumber     = regexp.MustCompile(`^(.+?)\s+(\d{1,4})(?:v(\d))?$`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PARSE
+
+// Parse returns the release parsed from a filename. It returns
+// gopi.ErrBadParameter when the filename does not follow fansub
+// conventions, including filenames with SxxEyy numbering which are
+// left to the usual parsing.
+func Parse(filename string) (Name, error) {
+	var name Name
+
+	// Remove the path and extension, using spaces for underscores when
+	// the filename has no spaces
+	base := filepath.Base(filename)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if strings.Contains(base, " ") == false {
+		base = strings.Replace(base, "_", " ", -1)
+	}
+	if reSeason.MatchString(base) {
+		return name, gopi.ErrBadParameter
+	}
+
+	// The group is the first bracketed tag
+	if match := reGroup.FindStringSubmatch(base); match != nil {
+		name.Group = strings.TrimSpace(match[1])
+		base = base[len(match[0]):]
+	}
+
+	// Remaining tags hold the resolution and checksum
+	for _, match := range reTag.FindAllStringSubmatch(base, -1) {
+		for _, tag := range strings.FieldsFunc(match[1], isTagSeparator) {
+			if m := reResolution.FindStringSubmatch(tag); m != nil && name.Resolution == "" && strings.ContainsAny(tag, "pPxX") {
+				name.Resolution = m[1] + "p"
+			} else if reChecksum.MatchString(tag) && name.Checksum == "" {
+				name.Checksum = strings.ToUpper(tag)
+			}
+		}
+	}
+	base = strings.Join(strings.Fields(reTag.ReplaceAllString(base, " ")), " ")
+
+	// Match "Title - 01" and, when there is a group, "Title 01"
+	match := reDash.FindStringSubmatch(base)
+	if match == nil && name.Group != "" {
+		match = reNumber.FindStringSubmatch(base)
+	}
+	if match == nil {
+		return name, gopi.ErrBadParameter
+	}
+	name.Title = strings.TrimSpace(match[1])
+	if episode, err := strconv.ParseUint(match[2], 10, 32); err != nil {
+		return name, gopi.ErrBadParameter
+	} else {
+		name.Episode = uint(episode)
+	}
+	if match[3] != "" {
+		if version, err := strconv.ParseUint(match[3], 10, 32); err == nil {
+			name.Version = uint(version)
+		}
+	}
+
+	// Success
+	return name, nil
+}
+
+// Metadata returns the show, absolute episode number and group of the
+// release as metadata for an episode
+func (this Name) Metadata() map[media.MetadataKey]string {
+	keys := map[media.MetadataKey]string{
+		media.METADATA_KEY_SHOW:             this.Title,
+		media.METADATA_KEY_ABSOLUTE_EPISODE: fmt.Sprint(this.Episode),
+	}
+	if this.Group != "" {
+		keys[media.METADATA_KEY_ENCODED_BY] = this.Group
+	}
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Name) String() string {
+	return fmt.Sprintf("<anime.Name>{ group=%v title=%v episode=%v version=%v resolution=%v checksum=%v }",
+		strconv.Quote(this.Group), strconv.Quote(this.Title), this.Episode, this.Version, this.Resolution, this.Checksum)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func isTagSeparator(r rune) bool {
+	return r == ' ' || r == ',' || r == '_'
+}
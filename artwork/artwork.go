@@ -0,0 +1,230 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+
+	// Anonymous
+	_ "golang.org/x/image/webp"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Format uint
+
+// Options determine how artwork is stored or served. Zero values
+// keep the original format, use the default quality and do not
+// constrain the dimensions of the image.
+type Options struct {
+	Format    Format
+	Quality   uint // JPEG quality between 1 and 100
+	MaxWidth  uint // Maximum width in pixels
+	MaxHeight uint // Maximum height in pixels
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	FORMAT_NONE Format = iota
+	FORMAT_JPEG
+	FORMAT_PNG
+	FORMAT_GIF
+	FORMAT_WEBP
+)
+
+const (
+	DEFAULT_QUALITY = 85
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONVERT
+
+// Convert reads artwork from r and writes it to w with the options
+// applied, returning the format written. Artwork which is already in
+// the right format and within the size constraints is copied unchanged.
+// WebP artwork can be read but not written, so it is converted to PNG
+// when it is resized and no format is set.
+func Convert(w io.Writer, r io.Reader, opts Options) (Format, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return FORMAT_NONE, err
+	}
+	config, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return FORMAT_NONE, err
+	}
+
+	// Determine the output format
+	format := FormatForName(name)
+	if opts.Format != FORMAT_NONE {
+		format = opts.Format
+	}
+	resample := opts.Quality != 0 || fits(config.Width, config.Height, opts) == false
+	if format == FORMAT_WEBP && opts.Format == FORMAT_NONE && resample {
+		format = FORMAT_PNG
+	}
+
+	// Pass through the data when no conversion is required
+	if format == FormatForName(name) && resample == false {
+		if _, err := w.Write(data); err != nil {
+			return FORMAT_NONE, err
+		} else {
+			return format, nil
+		}
+	}
+
+	// Decode, resize and encode
+	if img, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+		return FORMAT_NONE, err
+	} else if err := Encode(w, Resize(img, opts.MaxWidth, opts.MaxHeight), format, opts.Quality); err != nil {
+		return FORMAT_NONE, err
+	}
+
+	// Success
+	return format, nil
+}
+
+// Encode writes an image in the specified format. It returns
+// gopi.ErrNotImplemented for WebP, which can only be read.
+func Encode(w io.Writer, img image.Image, format Format, quality uint) error {
+	switch format {
+	case FORMAT_JPEG:
+		if quality == 0 || quality > 100 {
+			quality = DEFAULT_QUALITY
+		}
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: int(quality)})
+	case FORMAT_PNG:
+		return png.Encode(w, img)
+	case FORMAT_GIF:
+		return gif.Encode(w, img, nil)
+	case FORMAT_WEBP:
+		return gopi.ErrNotImplemented
+	default:
+		return gopi.ErrBadParameter
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FORMATS
+
+// FormatForName returns a format from a name returned by the image package
+func FormatForName(name string) Format {
+	switch strings.ToLower(name) {
+	case "jpeg", "jpg":
+		return FORMAT_JPEG
+	case "png":
+		return FORMAT_PNG
+	case "gif":
+		return FORMAT_GIF
+	case "webp":
+		return FORMAT_WEBP
+	default:
+		return FORMAT_NONE
+	}
+}
+
+// FormatForMimeType returns a format from a mimetype
+func FormatForMimeType(mimetype string) Format {
+	switch strings.ToLower(mimetype) {
+	case "image/jpeg", "image/jpg":
+		return FORMAT_JPEG
+	case "image/png":
+		return FORMAT_PNG
+	case "image/gif":
+		return FORMAT_GIF
+	case "image/webp":
+		return FORMAT_WEBP
+	default:
+		return FORMAT_NONE
+	}
+}
+
+// MimeType returns the mimetype for the format
+func (f Format) MimeType() string {
+	switch f {
+	case FORMAT_JPEG:
+		return "image/jpeg"
+	case FORMAT_PNG:
+		return "image/png"
+	case FORMAT_GIF:
+		return "image/gif"
+	case FORMAT_WEBP:
+		return "image/webp"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Ext returns the file extension for the format
+func (f Format) Ext() string {
+	switch f {
+	case FORMAT_JPEG:
+		return ".jpg"
+	case FORMAT_PNG:
+		return ".png"
+	case FORMAT_GIF:
+		return ".gif"
+	case FORMAT_WEBP:
+		return ".webp"
+	default:
+		return ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (f Format) String() string {
+	switch f {
+	case FORMAT_NONE:
+		return "FORMAT_NONE"
+	case FORMAT_JPEG:
+		return "FORMAT_JPEG"
+	case FORMAT_PNG:
+		return "FORMAT_PNG"
+	case FORMAT_GIF:
+		return "FORMAT_GIF"
+	case FORMAT_WEBP:
+		return "FORMAT_WEBP"
+	default:
+		return "[?? Invalid Format value]"
+	}
+}
+
+func (o Options) String() string {
+	return fmt.Sprintf("<artwork.Options>{ format=%v quality=%v max_width=%v max_height=%v }", o.Format, o.Quality, o.MaxWidth, o.MaxHeight)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func fits(width, height int, opts Options) bool {
+	if opts.MaxWidth > 0 && uint(width) > opts.MaxWidth {
+		return false
+	}
+	if opts.MaxHeight > 0 && uint(height) > opts.MaxHeight {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package artwork_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	// Frameworks
+	artwork "github.com/djthorpe/gopi-media/artwork"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CONVERT
+
+func Test_artwork_000(t *testing.T) {
+	t.Log("Test_artwork_000")
+}
+
+func Test_artwork_001(t *testing.T) {
+	src := new(bytes.Buffer)
+	if err := png.Encode(src, testImage(400, 200)); err != nil {
+		t.Fatal(err)
+	}
+	dst := new(bytes.Buffer)
+	if format, err := artwork.Convert(dst, src, artwork.Options{Format: artwork.FORMAT_JPEG, MaxWidth: 100}); err != nil {
+		t.Fatal(err)
+	} else if format != artwork.FORMAT_JPEG {
+		t.Error("Unexpected format", format)
+	} else if config, name, err := image.DecodeConfig(dst); err != nil {
+		t.Error(err)
+	} else if name != "jpeg" {
+		t.Error("Unexpected format", name)
+	} else if config.Width != 100 || config.Height != 50 {
+		t.Error("Unexpected size", config.Width, config.Height)
+	}
+}
+
+func Test_artwork_002(t *testing.T) {
+	src := new(bytes.Buffer)
+	if err := png.Encode(src, testImage(40, 20)); err != nil {
+		t.Fatal(err)
+	}
+	data := src.Bytes()
+	dst := new(bytes.Buffer)
+	if format, err := artwork.Convert(dst, bytes.NewReader(data), artwork.Options{MaxWidth: 100, MaxHeight: 100}); err != nil {
+		t.Fatal(err)
+	} else if format != artwork.FORMAT_PNG {
+		t.Error("Unexpected format", format)
+	} else if bytes.Equal(data, dst.Bytes()) == false {
+		t.Error("Expected data to be passed through unchanged")
+	}
+}
+
+func Test_artwork_003(t *testing.T) {
+	img := artwork.Resize(testImage(100, 300), 0, 30)
+	if bounds := img.Bounds(); bounds.Dx() != 10 || bounds.Dy() != 30 {
+		t.Error("Unexpected size", bounds)
+	}
+	img = artwork.Resize(testImage(10, 10), 100, 100)
+	if bounds := img.Bounds(); bounds.Dx() != 10 || bounds.Dy() != 10 {
+		t.Error("Unexpected size", bounds)
+	}
+}
+
+func Test_artwork_004(t *testing.T) {
+	if artwork.FormatForMimeType("image/png") != artwork.FORMAT_PNG {
+		t.Error("Unexpected format for image/png")
+	}
+	if artwork.FORMAT_JPEG.MimeType() != "image/jpeg" {
+		t.Error("Unexpected mimetype", artwork.FORMAT_JPEG.MimeType())
+	}
+	if err := artwork.Encode(new(bytes.Buffer), testImage(1, 1), artwork.FORMAT_WEBP, 0); err == nil {
+		t.Error("Expected error encoding webp")
+	}
+}
+
+func Test_artwork_005(t *testing.T) {
+	// A lossless 1x1 webp image
+	src, err := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := new(bytes.Buffer)
+	if format, err := artwork.Convert(dst, bytes.NewReader(src), artwork.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	} else if format != artwork.FORMAT_PNG {
+		t.Error("Unexpected format", format)
+	} else if config, name, err := image.DecodeConfig(dst); err != nil {
+		t.Error(err)
+	} else if name != "png" || config.Width != 1 || config.Height != 1 {
+		t.Error("Unexpected image", name, config.Width, config.Height)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0x80, 0xFF})
+		}
+	}
+	return img
+}
@@ -0,0 +1,149 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Cache stores artwork on disk, keyed by the identifier of the media
+// item and the name of the artwork (for example, "cover" or "fanart").
+// Artwork is converted with the cache options when it is stored.
+type Cache struct {
+	Path    string
+	Options Options
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CACHE
+
+// Put stores artwork for a media item, replacing any existing artwork with
+// the same name, and returns the path to the stored file
+func (this *Cache) Put(id, name string, r io.Reader) (string, error) {
+	if err := this.check(id, name); err != nil {
+		return "", err
+	}
+
+	// Convert the artwork
+	buf := new(bytes.Buffer)
+	format, err := Convert(buf, r, this.Options)
+	if err != nil {
+		return "", err
+	}
+
+	// Remove existing artwork with the same name
+	if err := this.remove(id, name); err != nil {
+		return "", err
+	}
+
+	// Write the artwork
+	dir := filepath.Join(this.Path, id)
+	path := filepath.Join(dir, name+format.Ext())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	} else if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	// Success
+	return path, nil
+}
+
+// Get returns the path to stored artwork for a media item, or
+// gopi.ErrNotFound if there is no artwork with that name
+func (this *Cache) Get(id, name string) (string, error) {
+	if err := this.check(id, name); err != nil {
+		return "", err
+	} else if paths, err := filepath.Glob(filepath.Join(this.Path, id, name+".*")); err != nil {
+		return "", err
+	} else if len(paths) == 0 {
+		return "", gopi.ErrNotFound
+	} else {
+		return paths[0], nil
+	}
+}
+
+// Names returns the names of all artwork stored for a media item
+func (this *Cache) Names(id string) ([]string, error) {
+	if err := this.check(id, "_"); err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(filepath.Join(this.Path, id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Mode().IsRegular() {
+			names = append(names, strings.TrimSuffix(file.Name(), filepath.Ext(file.Name())))
+		}
+	}
+	return names, nil
+}
+
+// Remove deletes all artwork stored for a media item
+func (this *Cache) Remove(id string) error {
+	if err := this.check(id, "_"); err != nil {
+		return err
+	} else {
+		return os.RemoveAll(filepath.Join(this.Path, id))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Cache) String() string {
+	return fmt.Sprintf("<artwork.Cache>{ path=%v options=%v }", strconv.Quote(this.Path), this.Options)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// check ensures the identifier and name can be used as filenames
+func (this *Cache) check(id, name string) error {
+	if this.Path == "" {
+		return gopi.ErrBadParameter
+	}
+	for _, value := range []string{id, name} {
+		if value == "" || strings.HasPrefix(value, ".") || strings.ContainsAny(value, "/\\*?[") {
+			return gopi.ErrBadParameter
+		}
+	}
+	return nil
+}
+
+func (this *Cache) remove(id, name string) error {
+	if paths, err := filepath.Glob(filepath.Join(this.Path, id, name+".*")); err != nil {
+		return err
+	} else {
+		for _, path := range paths {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Handler serves artwork from a cache, converted with the options so
+// clients are sent artwork in a format and size they can display. The
+// request path is the identifier and name, such as "/<id>/cover", so
+// the handler is usually wrapped with http.StripPrefix. The "width"
+// and "height" query parameters reduce the maximum size further.
+type Handler struct {
+	Cache   *Cache
+	Options Options
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HTTP HANDLER
+
+func (this *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Find the artwork
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) != 2 || this.Cache == nil {
+		http.NotFound(w, req)
+		return
+	}
+	path, err := this.Cache.Get(parts[0], parts[1])
+	if err == gopi.ErrNotFound || err == gopi.ErrBadParameter {
+		http.NotFound(w, req)
+		return
+	} else if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// Reduce the size from the query
+	opts := this.Options
+	for key, value := range map[string]*uint{"width": &opts.MaxWidth, "height": &opts.MaxHeight} {
+		if param := req.URL.Query().Get(key); param == "" {
+			continue
+		} else if size, err := strconv.ParseUint(param, 10, 32); err != nil || size == 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		} else if *value == 0 || uint(size) < *value {
+			*value = uint(size)
+		}
+	}
+
+	// Convert the artwork
+	fh, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, req)
+		return
+	}
+	defer fh.Close()
+	stat, err := fh.Stat()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	buf := new(bytes.Buffer)
+	format, err := Convert(buf, fh, opts)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The ETag changes with the file and the options
+	w.Header().Set("Content-Type", format.MimeType())
+	w.Header().Set("ETag", fmt.Sprintf("\"%x-%x-%v-%v-%v-%v\"", stat.ModTime().UnixNano(), stat.Size(), uint(opts.Format), opts.Quality, opts.MaxWidth, opts.MaxHeight))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, req, "", stat.ModTime(), bytes.NewReader(buf.Bytes()))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Handler) String() string {
+	return fmt.Sprintf("<artwork.Handler>{ cache=%v options=%v }", this.Cache, this.Options)
+}
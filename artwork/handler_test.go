@@ -0,0 +1,82 @@
+package artwork_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	// Frameworks
+	artwork "github.com/djthorpe/gopi-media/artwork"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST HANDLER
+
+func Test_handler_000(t *testing.T) {
+	t.Log("Test_handler_000")
+}
+
+func Test_handler_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artwork")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := &artwork.Cache{Path: dir}
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, testImage(200, 100)); err != nil {
+		t.Fatal(err)
+	} else if _, err := cache.Put("abc", artwork.ARTWORK_COVER, buf); err != nil {
+		t.Fatal(err)
+	}
+	handler := &artwork.Handler{Cache: cache, Options: artwork.Options{Format: artwork.FORMAT_JPEG, MaxWidth: 100}}
+
+	// Converted with the handler options
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/abc/cover", nil))
+	if w.Code != http.StatusOK {
+		t.Fatal("Unexpected status", w.Code)
+	} else if w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Error("Unexpected content type", w.Header().Get("Content-Type"))
+	} else if config, _, err := image.DecodeConfig(w.Body); err != nil {
+		t.Error(err)
+	} else if config.Width != 100 || config.Height != 50 {
+		t.Error("Unexpected size", config.Width, config.Height)
+	}
+
+	// Query reduces the size further
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/abc/cover?height=10", nil))
+	if w.Code != http.StatusOK {
+		t.Fatal("Unexpected status", w.Code)
+	} else if config, _, err := image.DecodeConfig(w.Body); err != nil {
+		t.Error(err)
+	} else if config.Width != 20 || config.Height != 10 {
+		t.Error("Unexpected size", config.Width, config.Height)
+	}
+
+	// Errors
+	for path, code := range map[string]int{
+		"/abc/fanart":         http.StatusNotFound,
+		"/abc":                http.StatusNotFound,
+		"/abc/cover?width=x":  http.StatusBadRequest,
+		"/abc/cover?height=0": http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != code {
+			t.Error("Unexpected status for", path, w.Code)
+		}
+	}
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/abc/cover", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Error("Unexpected status", w.Code)
+	}
+}
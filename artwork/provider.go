@@ -0,0 +1,289 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package artwork
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+	errors "github.com/djthorpe/gopi/util/errors"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Provider looks up artwork for media items from an external source
+type Provider interface {
+	// Return the name of the provider
+	Name() string
+
+	// Return the URLs of artwork for an item keyed by artwork name, or
+	// gopi.ErrNotFound if the provider cannot identify the item
+	Lookup(client *http.Client, item media.MediaItem) (map[string]string, error)
+}
+
+// CoverArtArchive provides front covers for music, using the
+// MusicBrainz album identifier
+type CoverArtArchive struct {
+	// Endpoint overrides the default service URL
+	Endpoint string
+}
+
+// FanartTV provides posters and backgrounds for movies and tv shows,
+// using TMDB, IMDB or TVDB identifiers
+type FanartTV struct {
+	// Key is the personal or project API key
+	Key string
+
+	// Endpoint overrides the default service URL
+	Endpoint string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	providers     = make([]Provider, 0)
+	providersLock sync.Mutex
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	ARTWORK_COVER  = "cover"
+	ARTWORK_POSTER = "poster"
+	ARTWORK_FANART = "fanart"
+)
+
+const (
+	COVERARTARCHIVE_ENDPOINT = "https://coverartarchive.org"
+	FANARTTV_ENDPOINT        = "https://webservice.fanart.tv/v3"
+	ARTWORK_MAXSIZE          = 20 * 1024 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// REGISTRATION
+
+// RegisterProvider adds a provider which is used by Fetch when no
+// providers are passed to it. Providers are usually registered from
+// the init function of the package which implements them.
+func RegisterProvider(provider Provider) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	providers = append(providers, provider)
+}
+
+// Providers returns the registered providers in order of registration
+func Providers() []Provider {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	return append([]Provider{}, providers...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FETCH
+
+// Fetch downloads artwork for an item into the cache, trying each provider
+// in turn and skipping artwork which is already stored. Nothing is fetched
+// for media files with an embedded front cover. The registered providers
+// are used when none are passed. It returns the names of the artwork which
+// was stored, or util.ErrOffline without making any requests when
+// util.DefaultNetwork is offline.
+func (this *Cache) Fetch(client *http.Client, item media.MediaItem, providers ...Provider) ([]string, error) {
+	var errs errors.CompoundError
+
+	if len(providers) == 0 {
+		providers = Providers()
+	}
+
+	if client == nil {
+		client = util.BackgroundClient
+	}
+	id := item.Id()
+	if id == "" {
+		return nil, gopi.ErrBadParameter
+	} else if embedded(item, media.ARTWORK_TYPE_FRONT_COVER) {
+		return []string{}, nil
+	} else if util.DefaultNetwork.Skip("artwork fetch") {
+		return nil, util.ErrOffline
+	}
+	stored := make([]string, 0)
+	for _, provider := range providers {
+		urls, err := provider.Lookup(client, item)
+		if err == gopi.ErrNotFound {
+			continue
+		} else if err != nil {
+			errs.Add(fmt.Errorf("%v: %v", provider.Name(), err))
+			continue
+		}
+		for name, url := range urls {
+			if _, err := this.Get(id, name); err == nil {
+				continue
+			} else if err != gopi.ErrNotFound {
+				errs.Add(err)
+			} else if err := this.download(client, id, name, url); err != nil {
+				errs.Add(fmt.Errorf("%v: %v", provider.Name(), err))
+			} else {
+				stored = append(stored, name)
+			}
+		}
+	}
+
+	return stored, errs.ErrorOrSelf()
+}
+
+// embedded returns true if an item is a media file with embedded
+// artwork of a type
+func embedded(item media.MediaItem, t media.ArtworkType) bool {
+	if file, ok := item.(media.MediaFile); ok {
+		for _, artwork := range file.Artwork() {
+			if artwork.Type() == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (this *Cache) download(client *http.Client, id, name, url string) error {
+	if response, err := client.Get(url); err != nil {
+		return err
+	} else {
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("%v: %v", url, response.Status)
+		} else if _, err := this.Put(id, name, io.LimitReader(response.Body, ARTWORK_MAXSIZE)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// COVER ART ARCHIVE
+
+func (this CoverArtArchive) Name() string {
+	return "coverartarchive"
+}
+
+func (this CoverArtArchive) Lookup(client *http.Client, item media.MediaItem) (map[string]string, error) {
+	endpoint := this.Endpoint
+	if endpoint == "" {
+		endpoint = COVERARTARCHIVE_ENDPOINT
+	}
+	if mbid := item.StringForKey(media.METADATA_KEY_MUSICBRAINZ_ALBUM_ID); mbid == "" {
+		return nil, gopi.ErrNotFound
+	} else {
+		// The service redirects to the image, so no request is made here
+		return map[string]string{
+			ARTWORK_COVER: endpoint + "/release/" + url.PathEscape(mbid) + "/front",
+		}, nil
+	}
+}
+
+func (this CoverArtArchive) String() string {
+	return fmt.Sprintf("<artwork.CoverArtArchive>{ endpoint=%v }", strconv.Quote(this.Endpoint))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FANART.TV
+
+type fanartImage struct {
+	Url   string `json:"url"`
+	Likes string `json:"likes"`
+}
+
+type fanartResponse map[string]json.RawMessage
+
+func (this FanartTV) Name() string {
+	return "fanart.tv"
+}
+
+func (this FanartTV) Lookup(client *http.Client, item media.MediaItem) (map[string]string, error) {
+	endpoint := this.Endpoint
+	if endpoint == "" {
+		endpoint = FANARTTV_ENDPOINT
+	}
+	if this.Key == "" {
+		return nil, gopi.ErrNotFound
+	}
+
+	// Determine the path and the names of the images in the response
+	var path, poster, background string
+	if id := item.StringForKey(media.METADATA_KEY_TVDB_ID); id != "" {
+		path, poster, background = "/tv/"+url.PathEscape(id), "tvposter", "showbackground"
+	} else if id := item.StringForKey(media.METADATA_KEY_TMDB_ID); id != "" {
+		path, poster, background = "/movies/"+url.PathEscape(id), "movieposter", "moviebackground"
+	} else if id := item.StringForKey(media.METADATA_KEY_IMDB_ID); id != "" {
+		path, poster, background = "/movies/"+url.PathEscape(id), "movieposter", "moviebackground"
+	} else {
+		return nil, gopi.ErrNotFound
+	}
+
+	// Make the request
+	response, err := client.Get(endpoint + path + "?api_key=" + url.QueryEscape(this.Key))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, gopi.ErrNotFound
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v", response.Status)
+	}
+	data := make(fanartResponse)
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	// Return the most liked image of each type
+	urls := make(map[string]string)
+	if url := data.best(poster); url != "" {
+		urls[ARTWORK_POSTER] = url
+	}
+	if url := data.best(background); url != "" {
+		urls[ARTWORK_FANART] = url
+	}
+	if len(urls) == 0 {
+		return nil, gopi.ErrNotFound
+	} else {
+		return urls, nil
+	}
+}
+
+func (this FanartTV) String() string {
+	return fmt.Sprintf("<artwork.FanartTV>{ endpoint=%v }", strconv.Quote(this.Endpoint))
+}
+
+func (this fanartResponse) best(name string) string {
+	var images []fanartImage
+	if data, exists := this[name]; exists == false {
+		return ""
+	} else if err := json.Unmarshal(data, &images); err != nil {
+		return ""
+	}
+	url, likes := "", int64(-1)
+	for _, image := range images {
+		if value, _ := strconv.ParseInt(image.Likes, 10, 64); value > likes && image.Url != "" {
+			url, likes = image.Url, value
+		}
+	}
+	return url
+}
@@ -0,0 +1,175 @@
+package artwork_test
+
+import (
+	"bytes"
+	"image/png"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	artwork "github.com/djthorpe/gopi-media/artwork"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA ITEM
+
+type item struct {
+	id   string
+	keys map[media.MetadataKey]string
+}
+
+func (this *item) Id() string                                { return this.id }
+func (this *item) Title() string                             { return "" }
+func (this *item) Type() media.MediaType                     { return media.MEDIA_TYPE_NONE }
+func (this *item) Keys() []media.MetadataKey                 { return nil }
+func (this *item) StringForKey(key media.MetadataKey) string { return this.keys[key] }
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CACHE AND PROVIDERS
+
+func Test_provider_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artwork")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := &artwork.Cache{Path: dir, Options: artwork.Options{Format: artwork.FORMAT_JPEG}}
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, testImage(10, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := cache.Put("abc", artwork.ARTWORK_COVER, buf); err != nil {
+		t.Fatal(err)
+	} else if path2, err := cache.Get("abc", artwork.ARTWORK_COVER); err != nil {
+		t.Error(err)
+	} else if path != path2 {
+		t.Error("Unexpected path", path2)
+	}
+	if names, err := cache.Names("abc"); err != nil {
+		t.Error(err)
+	} else if len(names) != 1 || names[0] != artwork.ARTWORK_COVER {
+		t.Error("Unexpected names", names)
+	}
+	if _, err := cache.Get("abc", artwork.ARTWORK_FANART); err == nil {
+		t.Error("Expected error for missing artwork")
+	}
+	if _, err := cache.Get("../abc", artwork.ARTWORK_COVER); err == nil {
+		t.Error("Expected error for bad identifier")
+	}
+	if err := cache.Remove("abc"); err != nil {
+		t.Error(err)
+	} else if names, err := cache.Names("abc"); err != nil {
+		t.Error(err)
+	} else if len(names) != 0 {
+		t.Error("Unexpected names", names)
+	}
+}
+
+func Test_provider_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artwork")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Serve a cover and a fanart.tv response
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release/mbid/front", "/poster.png":
+			png.Encode(w, testImage(10, 10))
+		case "/movies/603":
+			if r.URL.Query().Get("api_key") != "key" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			} else {
+				w.Write([]byte(`{"movieposter":[{"url":"` + "http://" + r.Host + `/poster.png","likes":"3"},{"url":"http://invalid/","likes":"1"}]}`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cache := &artwork.Cache{Path: dir}
+	providers := []artwork.Provider{
+		artwork.CoverArtArchive{Endpoint: server.URL},
+		artwork.FanartTV{Key: "key", Endpoint: server.URL},
+	}
+	music := &item{"music", map[media.MetadataKey]string{
+		media.METADATA_KEY_MUSICBRAINZ_ALBUM_ID: "mbid",
+	}}
+	movie := &item{"movie", map[media.MetadataKey]string{
+		media.METADATA_KEY_TMDB_ID: "603",
+	}}
+	if names, err := cache.Fetch(nil, music, providers...); err != nil {
+		t.Error(err)
+	} else if len(names) != 1 || names[0] != artwork.ARTWORK_COVER {
+		t.Error("Unexpected names", names)
+	}
+	if names, err := cache.Fetch(nil, movie, providers...); err != nil {
+		t.Error(err)
+	} else if len(names) != 1 || names[0] != artwork.ARTWORK_POSTER {
+		t.Error("Unexpected names", names)
+	}
+
+	// Artwork which is already cached is not fetched again
+	if names, err := cache.Fetch(nil, movie, providers...); err != nil {
+		t.Error(err)
+	} else if len(names) != 0 {
+		t.Error("Unexpected names", names)
+	}
+
+	// Nothing is fetched for files with an embedded cover
+	file := &mock.File{Path: "/tmp/song.mp3", Identifier: "song", Metadata: music.keys, Images: []*mock.Artwork{
+		{ArtworkType: media.ARTWORK_TYPE_FRONT_COVER, Mimetype: "image/png"},
+	}}
+	if names, err := cache.Fetch(nil, file, providers...); err != nil {
+		t.Error(err)
+	} else if len(names) != 0 {
+		t.Error("Unexpected names", names)
+	} else if _, err := cache.Get(file.Id(), artwork.ARTWORK_COVER); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound", err)
+	}
+
+	// Nothing is fetched when the network is offline
+	util.DefaultNetwork.SetOffline(true)
+	defer util.DefaultNetwork.SetOffline(false)
+	if _, err := cache.Fetch(nil, movie, providers...); err != util.ErrOffline {
+		t.Error("Expected ErrOffline", err)
+	}
+}
+
+func Test_provider_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "artwork")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		png.Encode(w, testImage(10, 10))
+	}))
+	defer server.Close()
+
+	// Registered providers are used when none are passed
+	artwork.RegisterProvider(artwork.CoverArtArchive{Endpoint: server.URL})
+	if providers := artwork.Providers(); len(providers) != 1 {
+		t.Error("Unexpected providers", providers)
+	}
+	cache := &artwork.Cache{Path: dir}
+	music := &item{"music", map[media.MetadataKey]string{
+		media.METADATA_KEY_MUSICBRAINZ_ALBUM_ID: "mbid",
+	}}
+	if names, err := cache.Fetch(nil, music); err != nil {
+		t.Error(err)
+	} else if len(names) != 1 || names[0] != artwork.ARTWORK_COVER {
+		t.Error("Unexpected names", names)
+	}
+}
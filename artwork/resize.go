@@ -0,0 +1,79 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package artwork
+
+import (
+	"image"
+	"image/color"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RESIZE
+
+// Resize scales an image down to fit within the maximum width and height,
+// keeping the aspect ratio. A zero maximum is unconstrained, and images
+// are never scaled up.
+func Resize(img image.Image, max_width, max_height uint) image.Image {
+	bounds := img.Bounds()
+	width, height := uint(bounds.Dx()), uint(bounds.Dy())
+	if width == 0 || height == 0 {
+		return img
+	}
+
+	// Determine the scaled size
+	w, h := width, height
+	if max_width > 0 && w > max_width {
+		h, w = h*max_width/w, max_width
+	}
+	if max_height > 0 && h > max_height {
+		w, h = w*max_height/h, max_height
+	}
+	if w == width && h == height {
+		return img
+	}
+	if w == 0 {
+		w = 1
+	}
+	if h == 0 {
+		h = 1
+	}
+
+	// Average the source pixels which fall within each destination pixel
+	dst := image.NewRGBA(image.Rect(0, 0, int(w), int(h)))
+	for y := uint(0); y < h; y++ {
+		y0, y1 := span(y, h, height)
+		for x := uint(0); x < w; x++ {
+			x0, x1 := span(x, w, width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					r_, g_, b_, a_ := img.At(bounds.Min.X+int(sx), bounds.Min.Y+int(sy)).RGBA()
+					r, g, b, a, n = r+uint64(r_), g+uint64(g_), b+uint64(b_), a+uint64(a_), n+1
+				}
+			}
+			dst.SetRGBA64(int(x), int(y), color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// span returns the range of source pixels for a destination pixel
+func span(i, dst, src uint) (uint, uint) {
+	start, end := i*src/dst, (i+1)*src/dst
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
@@ -0,0 +1,128 @@
+/*
+	Go Language Raspberry Pi Interface
+	(c) Copyright David Thorpe 2019
+	All Rights Reserved
+	For Licensing and Usage information, please see LICENSE.md
+*/
+
+package media
+
+import (
+	"fmt"
+
+	// Frameworks
+	"github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type SampleFormat uint
+
+// AudioFormat describes interleaved PCM audio
+type AudioFormat struct {
+	Format   SampleFormat
+	Rate     uint
+	Channels uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// AudioSink plays interleaved PCM audio on an output device, and emits
+// an AudioEvent when the device runs out of audio
+type AudioSink interface {
+	gopi.Driver
+	gopi.Publisher
+
+	// Set the format of the audio, returning the nearest format the
+	// device supports, which the audio should be converted to before
+	// it is written
+	SetFormat(AudioFormat) (AudioFormat, error)
+
+	// Return the current format
+	Format() AudioFormat
+
+	// Write audio in the current format, blocking while the device
+	// buffer is full. Data is written in whole frames.
+	Write(data []byte) (int, error)
+
+	// Wait until the audio which has been written has been played
+	Drain() error
+
+	// Stop playing immediately, discarding audio which has been written
+	Stop() error
+}
+
+// AudioEvent is emitted by an AudioSink when audio was not written in
+// time and playback restarted
+type AudioEvent interface {
+	gopi.Event
+
+	// Return the format of the audio being played
+	Format() AudioFormat
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	SAMPLE_FORMAT_NONE    SampleFormat = iota
+	SAMPLE_FORMAT_U8                   // Unsigned 8-bit
+	SAMPLE_FORMAT_S16                  // Signed 16-bit little-endian
+	SAMPLE_FORMAT_S24                  // Signed 24-bit little-endian, packed in three bytes
+	SAMPLE_FORMAT_S32                  // Signed 32-bit little-endian
+	SAMPLE_FORMAT_FLOAT32              // 32-bit float little-endian
+	SAMPLE_FORMAT_MAX     = SAMPLE_FORMAT_FLOAT32
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIO FORMAT
+
+// BytesPerSample returns the size of a sample for one channel, or zero
+// for an unknown format
+func (f SampleFormat) BytesPerSample() uint {
+	switch f {
+	case SAMPLE_FORMAT_U8:
+		return 1
+	case SAMPLE_FORMAT_S16:
+		return 2
+	case SAMPLE_FORMAT_S24:
+		return 3
+	case SAMPLE_FORMAT_S32, SAMPLE_FORMAT_FLOAT32:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// BytesPerFrame returns the size of a sample for all channels
+func (f AudioFormat) BytesPerFrame() uint {
+	return f.Format.BytesPerSample() * f.Channels
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (f SampleFormat) String() string {
+	switch f {
+	case SAMPLE_FORMAT_NONE:
+		return "SAMPLE_FORMAT_NONE"
+	case SAMPLE_FORMAT_U8:
+		return "SAMPLE_FORMAT_U8"
+	case SAMPLE_FORMAT_S16:
+		return "SAMPLE_FORMAT_S16"
+	case SAMPLE_FORMAT_S24:
+		return "SAMPLE_FORMAT_S24"
+	case SAMPLE_FORMAT_S32:
+		return "SAMPLE_FORMAT_S32"
+	case SAMPLE_FORMAT_FLOAT32:
+		return "SAMPLE_FORMAT_FLOAT32"
+	default:
+		return "[?? Invalid SampleFormat value]"
+	}
+}
+
+func (f AudioFormat) String() string {
+	return fmt.Sprintf("<media.AudioFormat>{ format=%v rate=%v channels=%v }", f.Format, f.Rate, f.Channels)
+}
@@ -0,0 +1,175 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package book
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Format is the file format of a book
+type Format uint
+
+// Book contains the metadata read from an EPUB or PDF file
+type Book struct {
+	Format      Format
+	Title       string
+	Authors     []string
+	Publisher   string
+	Language    string
+	Date        string
+	Description string
+	ISBN        string
+
+	// Series and SeriesIndex are read from calibre metadata in EPUB files
+	Series      string
+	SeriesIndex string
+
+	// Pages is the number of pages in PDF files
+	Pages uint
+
+	// Cover image for EPUB files, when CoverType is not empty
+	CoverType string
+	Cover     []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	FORMAT_NONE Format = iota
+	FORMAT_EPUB
+	FORMAT_PDF
+)
+
+var (
+	pdfHeader = []byte("%PDF-")
+	zipHeader = []byte("PK\x03\x04")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// ReadFile reads metadata from an EPUB or PDF file
+func ReadFile(filename string) (*Book, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		if stat, err := fh.Stat(); err != nil {
+			return nil, err
+		} else {
+			return Read(fh, stat.Size())
+		}
+	}
+}
+
+// Read reads metadata from an EPUB or PDF file of a known size,
+// determining the format from the file contents. It returns
+// gopi.ErrBadParameter for other formats.
+func Read(r io.ReaderAt, size int64) (*Book, error) {
+	header := make([]byte, len(pdfHeader))
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return nil, err
+	} else if bytes.Equal(header, pdfHeader) {
+		return readPDF(r, size)
+	} else if bytes.HasPrefix(header, zipHeader) {
+		return readEPUB(r, size)
+	} else {
+		return nil, gopi.ErrBadParameter
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Keys returns the metadata for the book
+func (this *Book) Keys() map[media.MetadataKey]string {
+	keys := make(map[media.MetadataKey]string)
+	for key, value := range map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE:       this.Title,
+		media.METADATA_KEY_AUTHOR:      strings.Join(this.Authors, ", "),
+		media.METADATA_KEY_PUBLISHER:   this.Publisher,
+		media.METADATA_KEY_LANGUAGE:    this.Language,
+		media.METADATA_KEY_YEAR:        this.Date,
+		media.METADATA_KEY_DESCRIPTION: this.Description,
+		media.METADATA_KEY_ISBN:        this.ISBN,
+		media.METADATA_KEY_SERIES:      this.Series,
+	} {
+		if value != "" {
+			keys[key] = value
+		}
+	}
+	if index, err := strconv.ParseFloat(this.SeriesIndex, 64); err == nil {
+		keys[media.METADATA_KEY_SERIES_INDEX] = strconv.FormatFloat(index, 'f', -1, 64)
+	}
+	if this.Pages > 0 {
+		keys[media.METADATA_KEY_PAGE_COUNT] = fmt.Sprint(this.Pages)
+	}
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (f Format) String() string {
+	switch f {
+	case FORMAT_NONE:
+		return "FORMAT_NONE"
+	case FORMAT_EPUB:
+		return "FORMAT_EPUB"
+	case FORMAT_PDF:
+		return "FORMAT_PDF"
+	default:
+		return "[?? Invalid Format value]"
+	}
+}
+
+func (this *Book) String() string {
+	parts := fmt.Sprintf(" format=%v", this.Format)
+	if this.Title != "" {
+		parts += fmt.Sprintf(" title=%v", strconv.Quote(this.Title))
+	}
+	if len(this.Authors) > 0 {
+		parts += fmt.Sprintf(" authors=%v", this.Authors)
+	}
+	if this.Pages > 0 {
+		parts += fmt.Sprintf(" pages=%v", this.Pages)
+	}
+	if this.CoverType != "" {
+		parts += fmt.Sprintf(" cover=%v", this.CoverType)
+	}
+	return fmt.Sprintf("<book.Book>{%v }", parts)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// date returns the date part of an ISO date and time
+func date(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, "T"); i >= 0 {
+		value = value[:i]
+	}
+	return value
+}
@@ -0,0 +1,214 @@
+package book_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	book "github.com/djthorpe/gopi-media/book"
+)
+
+const (
+	CONTAINER = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`
+	OPF = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="id">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>The Example Book</dc:title>
+    <dc:creator opf:role="aut">Jane Author</dc:creator>
+    <dc:creator opf:role="aut">John Author</dc:creator>
+    <dc:publisher>Example Press</dc:publisher>
+    <dc:language>en</dc:language>
+    <dc:date>2019-03-01T00:00:00+00:00</dc:date>
+    <dc:identifier opf:scheme="uuid">1234</dc:identifier>
+    <dc:identifier opf:scheme="ISBN">978-0-306-40615-7</dc:identifier>
+    <meta name="calibre:series" content="Examples"/>
+    <meta name="calibre:series_index" content="2.0"/>
+    <meta name="cover" content="cover-id"/>
+  </metadata>
+  <manifest>
+    <item id="cover-id" href="images/cover%20image.jpg" media-type="image/jpeg"/>
+  </manifest>
+</package>`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST BOOK
+
+func Test_book_000(t *testing.T) {
+	t.Log("Test_book_000")
+}
+
+func Test_book_001(t *testing.T) {
+	data := archive(t, map[string]string{
+		"mimetype":                     "application/epub+zip",
+		"META-INF/container.xml":       CONTAINER,
+		"OEBPS/content.opf":            OPF,
+		"OEBPS/images/cover image.jpg": "cover",
+	})
+	b, err := book.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Format != book.FORMAT_EPUB || b.CoverType != "image/jpeg" || string(b.Cover) != "cover" {
+		t.Error("Unexpected book", b)
+	}
+	keys := b.Keys()
+	for key, value := range map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE:        "The Example Book",
+		media.METADATA_KEY_AUTHOR:       "Jane Author, John Author",
+		media.METADATA_KEY_PUBLISHER:    "Example Press",
+		media.METADATA_KEY_LANGUAGE:     "en",
+		media.METADATA_KEY_YEAR:         "2019-03-01",
+		media.METADATA_KEY_ISBN:         "9780306406157",
+		media.METADATA_KEY_SERIES:       "Examples",
+		media.METADATA_KEY_SERIES_INDEX: "2",
+	} {
+		if keys[key] != value {
+			t.Error("Unexpected value for", key, keys[key])
+		}
+	}
+
+	// Missing container
+	data = archive(t, map[string]string{"OEBPS/content.opf": OPF})
+	if _, err := book.Read(bytes.NewReader(data), int64(len(data))); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	}
+	// Not a book
+	data = []byte("GIF89a")
+	if _, err := book.Read(bytes.NewReader(data), int64(len(data))); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	}
+}
+
+func Test_book_002(t *testing.T) {
+	data := []byte(strings.Join([]string{
+		"%PDF-1.4",
+		"1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj",
+		"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 12 >> endobj",
+		"3 0 obj << /Type /Pages /Parent 2 0 R /Count 5 >> endobj",
+		"5 0 obj << /Title (Liner Notes \\(Deluxe\\)) /Author <FEFF004A00F6007200F0> /CreationDate (D:20190301120000Z) >> endobj",
+		"trailer << /Root 1 0 R /Info 5 0 R >>",
+		"%%EOF",
+	}, "\n"))
+	b, err := book.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Format != book.FORMAT_PDF || b.Title != "Liner Notes (Deluxe)" || strings.Join(b.Authors, ",") != "Jörð" || b.Date != "2019-03-01" || b.Pages != 12 {
+		t.Error("Unexpected book", b, b.Authors, b.Date)
+	}
+}
+
+func Test_book_003(t *testing.T) {
+	// Information dictionary in a compressed object stream
+	objects := "<< /Type /Pages /Count 3 >> << /Title (Streamed) /Author (A; B) >>"
+	data := objectStream("2 0 7 28 ", objects)
+	if b, err := book.Read(bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Error(err)
+	} else if b.Title != "Streamed" || strings.Join(b.Authors, ",") != "A,B" || b.Pages != 3 {
+		t.Error("Unexpected book", b)
+	}
+
+	// Negative offsets in an object stream are ignored
+	data = objectStream("1 -50 2 3 ", objects)
+	if _, err := book.Read(bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Error(err)
+	}
+
+	// XMP metadata
+	data = []byte(`%PDF-1.7
+1 0 obj << /Type /Metadata /Subtype /XML >> stream
+<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF><rdf:Description>
+<dc:title><rdf:Alt><rdf:li xml:lang="x-default">Tom &amp; Jerry</rdf:li></rdf:Alt></dc:title>
+<dc:creator><rdf:Seq><rdf:li>One</rdf:li><rdf:li>Two</rdf:li></rdf:Seq></dc:creator>
+</rdf:Description></rdf:RDF></x:xmpmeta>
+endstream endobj`)
+	if b, err := book.Read(bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Error(err)
+	} else if b.Title != "Tom & Jerry" || strings.Join(b.Authors, ",") != "One,Two" {
+		t.Error("Unexpected book", b)
+	}
+}
+
+func Test_book_004(t *testing.T) {
+	dir, err := ioutil.TempDir("", "book")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "Digital Booklet.pdf")
+	if err := ioutil.WriteFile(filename, []byte("%PDF-1.4\n1 0 obj << /Type /Pages /Count 8 >> endobj\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := book.Open(filename); err != nil {
+		t.Error(err)
+	} else if file.Type() != media.MEDIA_TYPE_BOOKLET || file.Title() != "Digital Booklet" || file.StringForKey(media.METADATA_KEY_PAGE_COUNT) != "8" || file.Artwork() != nil {
+		t.Error("Unexpected file", file)
+	}
+
+	filename = filepath.Join(dir, "book.epub")
+	if err := ioutil.WriteFile(filename, archive(t, map[string]string{
+		"META-INF/container.xml": CONTAINER,
+		"OEBPS/content.opf":      OPF,
+	}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := book.Open(filename); err != nil {
+		t.Error(err)
+	} else if file.Type() != media.MEDIA_TYPE_EBOOK || file.Title() != "The Example Book" || file.Artwork() != nil {
+		t.Error("Unexpected file", file)
+	}
+	if _, err := book.Open(filepath.Join(dir, "missing.epub")); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound", err)
+	}
+
+	// Books can be read from a source
+	data := []byte("%PDF-1.4\n1 0 obj << /Type /Pages /Count 2 >> endobj\n")
+	if file, err := book.OpenReader(bytes.NewReader(data), int64(len(data)), "remote/Booklet.pdf"); err != nil {
+		t.Error(err)
+	} else if file.Type() != media.MEDIA_TYPE_BOOKLET || file.Title() != "Booklet" || file.StringForKey(media.METADATA_KEY_PAGE_COUNT) != "2" {
+		t.Error("Unexpected file", file)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func archive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, data := range files {
+		if fh, err := w.Create(name); err != nil {
+			t.Fatal(err)
+		} else if _, err := fh.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// objectStream returns a PDF with a compressed object stream
+func objectStream(header, objects string) []byte {
+	buf := new(bytes.Buffer)
+	z := zlib.NewWriter(buf)
+	z.Write([]byte(header + objects))
+	z.Close()
+	return []byte(fmt.Sprintf("%%PDF-1.5\n9 0 obj << /Type /ObjStm /N 2 /First %v /Filter /FlateDecode /Length %v >>\nstream\n%s\nendstream\nendobj\n10 0 obj << /Type /XRef /Root 1 0 R /Info 7 0 R >> endobj\n", len(header), buf.Len(), buf.Bytes()))
+}
@@ -0,0 +1,188 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package book
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type container struct {
+	Rootfiles []struct {
+		Path      string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type opf struct {
+	Metadata struct {
+		Titles      []string `xml:"http://purl.org/dc/elements/1.1/ title"`
+		Creators    []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+		Publisher   string   `xml:"http://purl.org/dc/elements/1.1/ publisher"`
+		Language    string   `xml:"http://purl.org/dc/elements/1.1/ language"`
+		Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+		Description string   `xml:"http://purl.org/dc/elements/1.1/ description"`
+		Identifiers []struct {
+			Scheme string `xml:"scheme,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+		Meta []struct {
+			Name     string `xml:"name,attr"`
+			Content  string `xml:"content,attr"`
+			Property string `xml:"property,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"meta"`
+	} `xml:"metadata"`
+	Manifest []struct {
+		Id         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	EPUB_CONTAINER = "META-INF/container.xml"
+	EPUB_MAXSIZE   = 1024 * 1024
+	COVER_MAXSIZE  = 16 * 1024 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readEPUB reads the package document referenced by the container
+func readEPUB(r io.ReaderAt, size int64) (*Book, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var c container
+	if data, err := readFile(files[EPUB_CONTAINER], EPUB_MAXSIZE); err != nil {
+		return nil, err
+	} else if err := xml.Unmarshal(data, &c); err != nil {
+		return nil, err
+	} else if len(c.Rootfiles) == 0 {
+		return nil, gopi.ErrBadParameter
+	}
+
+	var pkg opf
+	root := c.Rootfiles[0].Path
+	if data, err := readFile(files[root], EPUB_MAXSIZE); err != nil {
+		return nil, err
+	} else if err := xml.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+
+	this := &Book{Format: FORMAT_EPUB}
+	m := pkg.Metadata
+	if len(m.Titles) > 0 {
+		this.Title = strings.TrimSpace(m.Titles[0])
+	}
+	for _, creator := range m.Creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			this.Authors = append(this.Authors, creator)
+		}
+	}
+	this.Publisher = strings.TrimSpace(m.Publisher)
+	this.Language = strings.TrimSpace(m.Language)
+	this.Date = date(m.Date)
+	this.Description = strings.TrimSpace(m.Description)
+	for _, identifier := range m.Identifiers {
+		if isbn := isbnFor(identifier.Scheme, identifier.Value); isbn != "" {
+			this.ISBN = isbn
+			break
+		}
+	}
+
+	// Cover is an EPUB 3 manifest property, or an EPUB 2 meta element
+	cover := ""
+	for _, meta := range m.Meta {
+		switch {
+		case meta.Name == "calibre:series":
+			this.Series = strings.TrimSpace(meta.Content)
+		case meta.Name == "calibre:series_index":
+			this.SeriesIndex = strings.TrimSpace(meta.Content)
+		case meta.Property == "belongs-to-collection" && this.Series == "":
+			this.Series = strings.TrimSpace(meta.Value)
+		case meta.Name == "cover":
+			cover = meta.Content
+		}
+	}
+	for _, item := range pkg.Manifest {
+		if strings.Contains(" "+item.Properties+" ", " cover-image ") || (item.Id == cover && cover != "") {
+			href := path.Join(path.Dir(root), item.Href)
+			if unescaped, err := url.PathUnescape(href); err == nil {
+				href = unescaped
+			}
+			if data, err := readFile(files[href], COVER_MAXSIZE); err == nil {
+				this.CoverType, this.Cover = item.MediaType, data
+			}
+			break
+		}
+	}
+
+	// Success
+	return this, nil
+}
+
+// readFile returns the contents of a file in an archive, or
+// gopi.ErrBadParameter if the file is missing or too large
+func readFile(file *zip.File, max uint64) ([]byte, error) {
+	if file == nil || file.UncompressedSize64 > max {
+		return nil, gopi.ErrBadParameter
+	}
+	fh, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return ioutil.ReadAll(fh)
+}
+
+// isbnFor returns an ISBN from an identifier, which are marked with a
+// scheme or a "urn:isbn:" prefix
+func isbnFor(scheme, value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(strings.ToLower(value), "urn:isbn:") {
+		value = value[len("urn:isbn:"):]
+	} else if strings.EqualFold(scheme, "isbn") == false {
+		return ""
+	}
+	isbn := strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == 'X' || r == 'x' {
+			return r
+		}
+		return -1
+	}, value)
+	if len(isbn) == 10 || len(isbn) == 13 {
+		return strings.ToUpper(isbn)
+	} else {
+		return ""
+	}
+}
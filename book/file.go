@@ -0,0 +1,85 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package book
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// File implements media.MediaFile for a book. EPUB files are ebooks,
+// and PDF files are booklets, which are often included with albums.
+type File struct {
+	*util.DocumentFile
+	book *Book
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN
+
+// Open reads an EPUB or PDF file as a media file
+func Open(filename string) (*File, error) {
+	if book, err := ReadFile(filename); err != nil {
+		return nil, err
+	} else if file, err := util.NewDocumentFile(filename, book.document()); err != nil {
+		return nil, err
+	} else {
+		return &File{file, book}, nil
+	}
+}
+
+// OpenReader reads an EPUB or PDF file of a known size as a media file, such
+// as a file opened from a source. The filename is used for the title
+// and type.
+func OpenReader(r io.ReaderAt, size int64, filename string) (*File, error) {
+	if book, err := Read(r, size); err != nil {
+		return nil, err
+	} else if file, err := util.NewDocumentFileForReader(r, size, filename, book.document()); err != nil {
+		return nil, err
+	} else {
+		return &File{file, book}, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Book returns the metadata read from the file
+func (this *File) Book() *Book {
+	return this.book
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *File) String() string {
+	return fmt.Sprintf("<book.File>{ filename=%v %v }", strconv.Quote(this.Filename()), this.book)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// document returns the book as a document, which is a booklet for PDF
+// files and an ebook otherwise
+func (this *Book) document() util.Document {
+	doc := util.Document{Type: media.MEDIA_TYPE_EBOOK, Title: this.Title, Keys: this.Keys(), CoverType: this.CoverType, Cover: this.Cover}
+	if this.Format == FORMAT_PDF {
+		doc.Type = media.MEDIA_TYPE_BOOKLET
+	}
+	return doc
+}
@@ -0,0 +1,25 @@
+//go:build go1.18
+// +build go1.18
+
+package book_test
+
+import (
+	"bytes"
+	"testing"
+
+	// Frameworks
+	book "github.com/djthorpe/gopi-media/book"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FUZZ
+
+func Fuzz_book_Read(f *testing.F) {
+	f.Add(objectStream("2 0 7 28 ", "<< /Type /Pages /Count 3 >> << /Title (Streamed) >>"))
+	f.Add([]byte("%PDF-1.4\n1 0 obj << /Type /Pages /Count 8 >> endobj\n5 0 obj << /Title <FEFF004A> >> endobj\ntrailer << /Info 5 0 R >>\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if b, err := book.Read(bytes.NewReader(data), int64(len(data))); err == nil {
+			b.Keys()
+		}
+	})
+}
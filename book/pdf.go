@@ -0,0 +1,322 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package book
+
+import (
+	"bytes"
+	"compress/zlib"
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Files larger than this are only read at the start and end
+	PDF_MAXSIZE  = 64 * 1024 * 1024
+	PDF_PARTSIZE = 1024 * 1024
+)
+
+var (
+	reInfo     = regexp.MustCompile(`/Info\s+(\d+)\s+\d+\s+R`)
+	reObject   = regexp.MustCompile(`(?s)(\d+)\s+\d+\s+obj\b(.*?)\bendobj`)
+	rePages    = regexp.MustCompile(`/Type\s*/Pages\b`)
+	reCount    = regexp.MustCompile(`/Count\s+(\d+)`)
+	reObjStm   = regexp.MustCompile(`/Type\s*/ObjStm\b`)
+	reFirst    = regexp.MustCompile(`/First\s+(\d+)`)
+	reXMPTitle = regexp.MustCompile(`(?s)<dc:title>.*?<rdf:li[^>]*>(.*?)</rdf:li>`)
+	reXMPList  = regexp.MustCompile(`(?s)<dc:creator>(.*?)</dc:creator>`)
+	reXMPItem  = regexp.MustCompile(`(?s)<rdf:li[^>]*>(.*?)</rdf:li>`)
+	reDate     = regexp.MustCompile(`^D?:?(\d{4})(\d{2})?(\d{2})?`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// readPDF reads the document information dictionary and page count,
+// using XMP metadata when there is no information dictionary
+func readPDF(r io.ReaderAt, size int64) (*Book, error) {
+	data, err := readParts(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index objects, including those compressed in object streams
+	objects := make(map[int]string)
+	for _, match := range reObject.FindAllSubmatch(data, -1) {
+		num, _ := strconv.Atoi(string(match[1]))
+		objects[num] = string(match[2])
+		if reObjStm.Match(match[2]) {
+			for num, object := range objectStream(match[2]) {
+				objects[num] = object
+			}
+		}
+	}
+
+	this := &Book{Format: FORMAT_PDF}
+	for _, object := range objects {
+		if dict := dictFor(object, rePages); dict != "" {
+			if match := reCount.FindStringSubmatch(dict); match != nil {
+				if count, err := strconv.ParseUint(match[1], 10, 32); err == nil && uint(count) > this.Pages {
+					this.Pages = uint(count)
+				}
+			}
+		}
+	}
+
+	// The last trailer refers to the current information dictionary
+	if matches := reInfo.FindAllSubmatch(data, -1); len(matches) > 0 {
+		num, _ := strconv.Atoi(string(matches[len(matches)-1][1]))
+		if info, exists := objects[num]; exists {
+			this.Title = stringFor(info, "Title")
+			if author := stringFor(info, "Author"); author != "" {
+				for _, author := range strings.Split(author, ";") {
+					if author = strings.TrimSpace(author); author != "" {
+						this.Authors = append(this.Authors, author)
+					}
+				}
+			}
+			this.Description = stringFor(info, "Subject")
+			this.Date = pdfDate(stringFor(info, "CreationDate"))
+		}
+	}
+
+	// XMP metadata
+	if this.Title == "" {
+		if match := reXMPTitle.FindSubmatch(data); match != nil {
+			this.Title = strings.TrimSpace(html.UnescapeString(string(match[1])))
+		}
+	}
+	if len(this.Authors) == 0 {
+		if match := reXMPList.FindSubmatch(data); match != nil {
+			for _, item := range reXMPItem.FindAllSubmatch(match[1], -1) {
+				if author := strings.TrimSpace(html.UnescapeString(string(item[1]))); author != "" {
+					this.Authors = append(this.Authors, author)
+				}
+			}
+		}
+	}
+
+	// Success
+	return this, nil
+}
+
+// readParts returns the whole file, or the start and end of large files
+func readParts(r io.ReaderAt, size int64) ([]byte, error) {
+	if size <= PDF_MAXSIZE {
+		return ioutil.ReadAll(io.NewSectionReader(r, 0, size))
+	}
+	data := make([]byte, PDF_PARTSIZE*2)
+	if _, err := r.ReadAt(data[:PDF_PARTSIZE], 0); err != nil {
+		return nil, err
+	} else if _, err := r.ReadAt(data[PDF_PARTSIZE:], size-PDF_PARTSIZE); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// objectStream returns the objects compressed in an object stream
+func objectStream(object []byte) map[int]string {
+	start := bytes.Index(object, []byte("stream"))
+	end := bytes.LastIndex(object, []byte("endstream"))
+	first := reFirst.FindSubmatch(object)
+	if start < 0 || end < start || first == nil {
+		return nil
+	}
+	start += len("stream")
+	if bytes.HasPrefix(object[start:], []byte("\r\n")) {
+		start += 2
+	} else if bytes.HasPrefix(object[start:], []byte("\n")) {
+		start += 1
+	}
+	z, err := zlib.NewReader(bytes.NewReader(object[start:end]))
+	if err != nil {
+		return nil
+	}
+	defer z.Close()
+	// Use as much of the stream as can be decompressed
+	data, _ := ioutil.ReadAll(z)
+	if len(data) == 0 {
+		return nil
+	}
+
+	// The stream starts with pairs of object numbers and offsets
+	offset, _ := strconv.Atoi(string(first[1]))
+	if offset < 0 || offset > len(data) {
+		return nil
+	}
+	fields := strings.Fields(string(data[:offset]))
+	objects := make(map[int]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		num, err1 := strconv.Atoi(fields[i])
+		from, err2 := strconv.Atoi(fields[i+1])
+		to := len(data) - offset
+		if i+3 < len(fields) {
+			to, _ = strconv.Atoi(fields[i+3])
+		}
+		if err1 == nil && err2 == nil && from >= 0 && from <= to && offset+to <= len(data) {
+			objects[num] = string(data[offset+from : offset+to])
+		}
+	}
+	return objects
+}
+
+// dictFor returns the dictionary containing a match, or an empty string
+func dictFor(object string, re *regexp.Regexp) string {
+	loc := re.FindStringIndex(object)
+	if loc == nil {
+		return ""
+	}
+	start, depth := -1, 0
+	for i := loc[0]; i > 0; i-- {
+		if object[i-1:i+1] == ">>" {
+			depth++
+			i--
+		} else if object[i-1:i+1] == "<<" {
+			if depth == 0 {
+				start = i - 1
+				break
+			}
+			depth--
+			i--
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	depth = 0
+	for i := start; i+1 < len(object); i++ {
+		switch object[i : i+2] {
+		case "<<":
+			depth++
+			i++
+		case ">>":
+			if depth--; depth == 0 {
+				return object[start : i+2]
+			}
+			i++
+		}
+	}
+	return ""
+}
+
+// stringFor returns a text string value from a dictionary, which is a
+// literal string in brackets or a hexadecimal string
+func stringFor(dict, key string) string {
+	loc := regexp.MustCompile(`/` + key + `\s*([(<])`).FindStringSubmatchIndex(dict)
+	if loc == nil {
+		return ""
+	}
+	var data []byte
+	if dict[loc[2]] == '<' {
+		end := strings.Index(dict[loc[3]:], ">")
+		if end < 0 {
+			return ""
+		}
+		hex := strings.Join(strings.Fields(dict[loc[3]:loc[3]+end]), "")
+		if len(hex)%2 == 1 {
+			hex += "0"
+		}
+		for i := 0; i+1 < len(hex); i += 2 {
+			if b, err := strconv.ParseUint(hex[i:i+2], 16, 8); err == nil {
+				data = append(data, byte(b))
+			}
+		}
+	} else {
+		data = literal(dict[loc[3]:])
+	}
+	return strings.TrimSpace(text(data))
+}
+
+// literal returns the bytes in a literal string, up to the closing bracket
+func literal(value string) []byte {
+	data, depth := make([]byte, 0, len(value)), 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value):
+			i++
+			switch e := value[i]; e {
+			case 'n':
+				data = append(data, '\n')
+			case 'r':
+				data = append(data, '\r')
+			case 't':
+				data = append(data, '\t')
+			case 'b':
+				data = append(data, '\b')
+			case 'f':
+				data = append(data, '\f')
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					for j := 0; j < 3 && i < len(value) && value[i] >= '0' && value[i] <= '7'; j++ {
+						n = n*8 + int(value[i]-'0')
+						i++
+					}
+					i--
+					data = append(data, byte(n))
+				} else {
+					data = append(data, e)
+				}
+			}
+		case c == '(':
+			depth++
+			data = append(data, c)
+		case c == ')':
+			if depth == 0 {
+				return data
+			}
+			depth--
+			data = append(data, c)
+		default:
+			data = append(data, c)
+		}
+	}
+	return data
+}
+
+// text decodes UTF-16 strings with a byte order mark, and treats other
+// strings as Latin-1, which is close to PDFDocEncoding
+func text(data []byte) string {
+	if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
+		units := make([]uint16, 0, len(data)/2)
+		for i := 2; i+1 < len(data); i += 2 {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// pdfDate returns the ISO date for a date such as "D:20190301120000Z"
+func pdfDate(value string) string {
+	if match := reDate.FindStringSubmatch(value); match == nil {
+		return ""
+	} else if match[2] == "" {
+		return match[1]
+	} else if match[3] == "" {
+		return match[1] + "-" + match[2]
+	} else {
+		return match[1] + "-" + match[2] + "-" + match[3]
+	}
+}
@@ -0,0 +1,63 @@
+package cec_test
+
+import (
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	cec "github.com/djthorpe/gopi-media/cec"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST MESSAGES
+
+func Test_cec_000(t *testing.T) {
+	t.Log("Test_cec_000")
+}
+
+func Test_cec_001(t *testing.T) {
+	if msg, err := cec.NewMessage(cec.ADDR_PLAYBACK, cec.ADDR_TV, cec.OP_IMAGE_VIEW_ON); err != nil {
+		t.Error(err)
+	} else if len(msg) != 2 || msg[0] != 0x40 || msg[1] != 0x04 {
+		t.Error("Unexpected message", msg)
+	} else if msg.Initiator() != cec.ADDR_PLAYBACK || msg.Destination() != cec.ADDR_TV {
+		t.Error("Unexpected addresses", msg)
+	}
+	if _, err := cec.NewMessage(0x10, cec.ADDR_TV, cec.OP_STANDBY); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+
+	// Active source for HDMI 2 is broadcast with the physical address
+	msg := cec.ActiveSource(cec.ADDR_PLAYBACK, 0x2000)
+	if msg.Destination() != cec.ADDR_BROADCAST || len(msg) != 4 || msg[2] != 0x20 || msg[3] != 0x00 {
+		t.Error("Unexpected message", msg)
+	}
+
+	// Poll messages have no opcode
+	if _, ok := (cec.Message{0x40}).Opcode(); ok {
+		t.Error("Expected no opcode")
+	}
+}
+
+func Test_cec_002(t *testing.T) {
+	tests := []struct {
+		msg     cec.Message
+		key     media.RemoteKey
+		pressed bool
+	}{
+		{cec.Message{0x04, cec.OP_USER_CONTROL_PRESSED, 0x01}, media.REMOTE_KEY_UP, true},
+		{cec.Message{0x04, cec.OP_USER_CONTROL_PRESSED, 0x46}, media.REMOTE_KEY_PAUSE, true},
+		{cec.Message{0x04, cec.OP_USER_CONTROL_PRESSED, 0x7F}, media.REMOTE_KEY_NONE, true},
+		{cec.Message{0x04, cec.OP_USER_CONTROL_RELEASED}, media.REMOTE_KEY_NONE, false},
+		{cec.Message{0x04, cec.OP_PLAY, cec.PLAY_FORWARD}, media.REMOTE_KEY_PLAY, true},
+		{cec.Message{0x04, cec.OP_PLAY, cec.PLAY_STILL}, media.REMOTE_KEY_PAUSE, true},
+		{cec.Message{0x04, cec.OP_DECK_CONTROL, cec.DECK_CONTROL_STOP}, media.REMOTE_KEY_STOP, true},
+		{cec.Message{0x0F, cec.OP_STANDBY}, media.REMOTE_KEY_NONE, false},
+	}
+	for _, test := range tests {
+		if key, pressed := test.msg.Key(); key != test.key || pressed != test.pressed {
+			t.Error("Unexpected key for", test.msg, key, pressed)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package cec
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Device is a CEC adapter, which is used through the kernel interface
+// so the CEC library is not required
+type Device struct {
+	fh *os.File
+}
+
+// cecMsg mirrors struct cec_msg
+type cecMsg struct {
+	txTs          uint64
+	rxTs          uint64
+	len           uint32
+	timeout       uint32
+	sequence      uint32
+	flags         uint32
+	msg           [MAX_MESSAGE_SIZE]byte
+	reply         uint8
+	rxStatus      uint8
+	txStatus      uint8
+	txArbLostCnt  uint8
+	txNackCnt     uint8
+	txLowDriveCnt uint8
+	txErrorCnt    uint8
+}
+
+// cecLogAddrs mirrors struct cec_log_addrs
+type cecLogAddrs struct {
+	logAddr           [4]uint8
+	logAddrMask       uint16
+	cecVersion        uint8
+	numLogAddrs       uint8
+	vendorId          uint32
+	flags             uint32
+	osdName           [15]byte
+	primaryDeviceType [4]uint8
+	logAddrType       [4]uint8
+	allDeviceTypes    [4]uint8
+	features          [4][12]uint8
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Values from linux/cec.h
+	cecModeInitiator      = 0x01
+	cecModeFollower       = 0x10
+	cecVersion14          = 5
+	cecPrimDevtypePlay    = 4
+	cecLogAddrTypePlay    = 3
+	cecAllDevtypePlay     = 0x10
+	cecVendorIdNone       = 0xFFFFFFFF
+	cecLogAddrsFlFallback = 0x01
+	cecPhysAddrInvalid    = 0xFFFF
+)
+
+var (
+	cecGetPhysAddr = ioctlRead('a', 1, unsafe.Sizeof(uint16(0)))
+	cecGetLogAddrs = ioctlRead('a', 3, unsafe.Sizeof(cecLogAddrs{}))
+	cecSetLogAddrs = ioctlReadWrite('a', 4, unsafe.Sizeof(cecLogAddrs{}))
+	cecTransmit    = ioctlReadWrite('a', 5, unsafe.Sizeof(cecMsg{}))
+	cecReceive     = ioctlReadWrite('a', 6, unsafe.Sizeof(cecMsg{}))
+	cecSetMode     = ioctlWrite('a', 9, unsafe.Sizeof(uint32(0)))
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+// OpenDevice opens a CEC adapter and registers it as a playback device
+// with a name which the TV can show for the input
+func OpenDevice(device uint, name string) (*Device, error) {
+	fh, err := os.OpenFile(fmt.Sprintf("/dev/cec%v", device), os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	this := &Device{fh}
+
+	// Send messages and receive the messages the kernel does not
+	// answer itself
+	mode := uint32(cecModeInitiator | cecModeFollower)
+	if err := this.ioctl(cecSetMode, unsafe.Pointer(&mode)); err != nil {
+		fh.Close()
+		return nil, err
+	}
+
+	// Claim a playback logical address, unless one is already claimed
+	if addrs, err := this.logAddrs(); err != nil {
+		fh.Close()
+		return nil, err
+	} else if addrs.numLogAddrs == 0 {
+		addrs = cecLogAddrs{
+			cecVersion:  cecVersion14,
+			numLogAddrs: 1,
+			vendorId:    cecVendorIdNone,
+			flags:       cecLogAddrsFlFallback,
+		}
+		copy(addrs.osdName[:len(addrs.osdName)-1], name)
+		addrs.primaryDeviceType[0] = cecPrimDevtypePlay
+		addrs.logAddrType[0] = cecLogAddrTypePlay
+		addrs.allDeviceTypes[0] = cecAllDevtypePlay
+		if err := this.ioctl(cecSetLogAddrs, unsafe.Pointer(&addrs)); err != nil {
+			fh.Close()
+			return nil, err
+		}
+	}
+
+	// Success
+	return this, nil
+}
+
+// Close releases the adapter
+func (this *Device) Close() error {
+	return this.fh.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ADDRESSES
+
+// PhysicalAddress returns the address of the HDMI input the adapter is
+// connected to, or gopi.ErrNotFound when there is no TV connected
+func (this *Device) PhysicalAddress() (uint16, error) {
+	var addr uint16
+	if err := this.ioctl(cecGetPhysAddr, unsafe.Pointer(&addr)); err != nil {
+		return 0, err
+	} else if addr == cecPhysAddrInvalid {
+		return 0, gopi.ErrNotFound
+	}
+	return addr, nil
+}
+
+// LogicalAddress returns the logical address claimed by the adapter,
+// or ADDR_BROADCAST when the address has not been claimed yet
+func (this *Device) LogicalAddress() (uint8, error) {
+	if addrs, err := this.logAddrs(); err != nil {
+		return 0, err
+	} else if addrs.numLogAddrs == 0 || addrs.logAddr[0] > ADDR_BROADCAST {
+		return ADDR_BROADCAST, nil
+	} else {
+		return addrs.logAddr[0], nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MESSAGES
+
+// Transmit sends a message, waiting until it has been sent
+func (this *Device) Transmit(message Message) error {
+	if len(message) == 0 || len(message) > MAX_MESSAGE_SIZE {
+		return gopi.ErrBadParameter
+	}
+	msg := cecMsg{len: uint32(len(message))}
+	copy(msg.msg[:], message)
+	return this.ioctl(cecTransmit, unsafe.Pointer(&msg))
+}
+
+// Receive waits for a message, returning gopi.ErrDeadlineExceeded if
+// none is received before the timeout
+func (this *Device) Receive(timeout time.Duration) (Message, error) {
+	msg := cecMsg{timeout: uint32(timeout / time.Millisecond)}
+	if err := this.ioctl(cecReceive, unsafe.Pointer(&msg)); err != nil {
+		if err, ok := err.(*os.SyscallError); ok && err.Err == syscall.ETIMEDOUT {
+			return nil, gopi.ErrDeadlineExceeded
+		}
+		return nil, err
+	}
+	if msg.len > MAX_MESSAGE_SIZE {
+		return nil, gopi.ErrUnexpectedResponse
+	}
+	return Message(append([]byte{}, msg.msg[:msg.len]...)), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Device) String() string {
+	return fmt.Sprintf("<cec.Device>{ device=%v }", this.fh.Name())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Device) logAddrs() (cecLogAddrs, error) {
+	var addrs cecLogAddrs
+	err := this.ioctl(cecGetLogAddrs, unsafe.Pointer(&addrs))
+	return addrs, err
+}
+
+func (this *Device) ioctl(request uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), request, uintptr(arg)); errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	}
+	return nil
+}
+
+func ioctl(t, nr uintptr) uintptr {
+	return (t << 8) | nr
+}
+
+func ioctlWrite(t, nr, size uintptr) uintptr {
+	return (1 << 30) | (size << 16) | ioctl(t, nr)
+}
+
+func ioctlRead(t, nr, size uintptr) uintptr {
+	return (2 << 30) | (size << 16) | ioctl(t, nr)
+}
+
+func ioctlReadWrite(t, nr, size uintptr) uintptr {
+	return (3 << 30) | (size << 16) | ioctl(t, nr)
+}
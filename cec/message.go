@@ -0,0 +1,162 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package cec
+
+import (
+	"fmt"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Message is a CEC message, where the first byte has the initiator and
+// destination logical addresses, followed by the opcode and operands
+type Message []byte
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Logical addresses
+	ADDR_TV        = 0x0
+	ADDR_PLAYBACK  = 0x4
+	ADDR_BROADCAST = 0xF
+
+	// Opcodes
+	OP_IMAGE_VIEW_ON         = 0x04
+	OP_TEXT_VIEW_ON          = 0x0D
+	OP_STANDBY               = 0x36
+	OP_DECK_CONTROL          = 0x42
+	OP_PLAY                  = 0x41
+	OP_USER_CONTROL_PRESSED  = 0x44
+	OP_USER_CONTROL_RELEASED = 0x45
+	OP_ACTIVE_SOURCE         = 0x82
+
+	// Operands for OP_DECK_CONTROL and OP_PLAY
+	DECK_CONTROL_STOP = 0x03
+	PLAY_FORWARD      = 0x24
+	PLAY_STILL        = 0x25
+
+	// Maximum length of a message
+	MAX_MESSAGE_SIZE = 16
+)
+
+var (
+	// User control codes for remote keys
+	userControls = map[byte]media.RemoteKey{
+		0x00: media.REMOTE_KEY_SELECT,
+		0x01: media.REMOTE_KEY_UP,
+		0x02: media.REMOTE_KEY_DOWN,
+		0x03: media.REMOTE_KEY_LEFT,
+		0x04: media.REMOTE_KEY_RIGHT,
+		0x09: media.REMOTE_KEY_MENU,
+		0x0A: media.REMOTE_KEY_MENU,
+		0x0D: media.REMOTE_KEY_BACK,
+		0x30: media.REMOTE_KEY_CHANNELUP,
+		0x31: media.REMOTE_KEY_CHANNELDOWN,
+		0x35: media.REMOTE_KEY_INFO,
+		0x44: media.REMOTE_KEY_PLAY,
+		0x45: media.REMOTE_KEY_STOP,
+		0x46: media.REMOTE_KEY_PAUSE,
+		0x48: media.REMOTE_KEY_REWIND,
+		0x49: media.REMOTE_KEY_FASTFORWARD,
+		0x4B: media.REMOTE_KEY_NEXT,
+		0x4C: media.REMOTE_KEY_PREVIOUS,
+		0x60: media.REMOTE_KEY_PLAY,
+		0x61: media.REMOTE_KEY_PLAYPAUSE,
+		0x64: media.REMOTE_KEY_STOP,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewMessage returns a message from an initiator to a destination
+func NewMessage(initiator, destination uint8, opcode byte, operands ...byte) (Message, error) {
+	if initiator > 0xF || destination > 0xF || len(operands) > MAX_MESSAGE_SIZE-2 {
+		return nil, gopi.ErrBadParameter
+	}
+	return append(Message{initiator<<4 | destination, opcode}, operands...), nil
+}
+
+// ActiveSource returns the broadcast message which switches the TV to
+// the input with a physical address
+func ActiveSource(initiator uint8, physical uint16) Message {
+	msg, _ := NewMessage(initiator, ADDR_BROADCAST, OP_ACTIVE_SOURCE, byte(physical>>8), byte(physical))
+	return msg
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Initiator returns the logical address of the sender
+func (this Message) Initiator() uint8 {
+	if len(this) == 0 {
+		return ADDR_BROADCAST
+	}
+	return this[0] >> 4
+}
+
+// Destination returns the logical address of the receiver, which is
+// ADDR_BROADCAST for a broadcast message
+func (this Message) Destination() uint8 {
+	if len(this) == 0 {
+		return ADDR_BROADCAST
+	}
+	return this[0] & 0x0F
+}
+
+// Opcode returns the opcode, or false for a poll message which has none
+func (this Message) Opcode() (byte, bool) {
+	if len(this) < 2 {
+		return 0, false
+	}
+	return this[1], true
+}
+
+// Key returns the remote key for a message and true if the key was
+// pressed, or REMOTE_KEY_NONE if the message is not for a key. Play and
+// deck control messages from the TV are returned as key presses.
+func (this Message) Key() (media.RemoteKey, bool) {
+	opcode, ok := this.Opcode()
+	if ok == false {
+		return media.REMOTE_KEY_NONE, false
+	}
+	switch {
+	case opcode == OP_USER_CONTROL_PRESSED && len(this) >= 3:
+		return userControls[this[2]], true
+	case opcode == OP_USER_CONTROL_RELEASED:
+		return media.REMOTE_KEY_NONE, false
+	case opcode == OP_PLAY && len(this) >= 3:
+		if this[2] == PLAY_STILL {
+			return media.REMOTE_KEY_PAUSE, true
+		} else {
+			return media.REMOTE_KEY_PLAY, true
+		}
+	case opcode == OP_DECK_CONTROL && len(this) >= 3 && this[2] == DECK_CONTROL_STOP:
+		return media.REMOTE_KEY_STOP, true
+	}
+	return media.REMOTE_KEY_NONE, false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Message) String() string {
+	if opcode, ok := this.Opcode(); ok {
+		return fmt.Sprintf("<cec.Message>{ %X->%X opcode=0x%02X operands=%X }", this.Initiator(), this.Destination(), opcode, []byte(this[2:]))
+	} else {
+		return fmt.Sprintf("<cec.Message>{ %X->%X poll }", this.Initiator(), this.Destination())
+	}
+}
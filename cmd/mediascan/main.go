@@ -1,41 +1,72 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
+	"time"
 
 	// Frameworks
 	gopi "github.com/djthorpe/gopi"
 	media "github.com/djthorpe/gopi-media"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+	scan "github.com/djthorpe/gopi-media/scan"
+	source "github.com/djthorpe/gopi-media/source"
+	util "github.com/djthorpe/gopi-media/util"
 
 	// Modules
+	_ "github.com/djthorpe/gopi-media/s3"
 	_ "github.com/djthorpe/gopi-media/sys/ffmpeg"
 	_ "github.com/djthorpe/gopi-media/sys/sqlite"
+	_ "github.com/djthorpe/gopi-media/webdav"
 	_ "github.com/djthorpe/gopi/sys/logger"
 )
 
+// entry is a file or folder within a source, which is a local path or
+// a URL such as davs://host/media
+type entry struct {
+	src  media.Source
+	root string
+	name string
+}
+
 var (
-	paths = make(chan string)
-	files = make(chan string)
+	paths = make(chan entry)
+	files = make(chan entry)
 )
 
 func WalkFiles(app *gopi.AppInstance, start chan<- struct{}, stop <-chan struct{}) error {
 	// Get media object, start processing
 	ffmpeg := app.ModuleInstance("ffmpeg").(media.Media)
+	write_nfo, _ := app.AppFlags.GetBool("nfo.write")
+	timeout, _ := app.AppFlags.GetDuration("scan.timeout")
 	start <- gopi.DONE
 FOR_LOOP:
 	for {
 		select {
-		case filename := <-files:
+		case e := <-files:
 			// Ignore files by guessing their type
+			filename := e.String()
 			if ffmpeg.TypeFor(filename) == media.MEDIA_TYPE_NONE {
 				app.Logger.Warn("Ignoring %v", filename)
-			} else if file, err := ffmpeg.Open(filename); err != nil {
+			} else if file, err := open(ffmpeg, e, timeout); err == gopi.ErrNotImplemented {
+				// Files such as RAR comic book archives which can't be read yet
+				app.Logger.Warn("Ignoring %v: %v", filename, err)
+			} else if err != nil {
 				app.Logger.Error("%v: %v", filename, err)
 			} else {
 				fmt.Println(file)
+				if _, local := e.src.(source.Local); write_nfo && local == false {
+					app.Logger.Warn("%v: Not writing NFO for remote file", filename)
+				} else if write_nfo && nfo.Supported(file) {
+					if path, err := nfo.WriteSidecar(file, nil); err != nil {
+						app.Logger.Error("%v: %v", filename, err)
+					} else {
+						app.Logger.Info("Written %v", path)
+					}
+				}
 				ffmpeg.Destroy(file)
 			}
 		case <-stop:
@@ -45,45 +76,57 @@ FOR_LOOP:
 	return nil
 }
 
+// open returns an opened file, or an error if the file cannot be
+// probed within the timeout
+func open(driver media.Media, e entry, timeout time.Duration) (media.MediaFile, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return source.OpenMedia(ctx, driver, e.src, e.name)
+}
+
 func WalkPaths(app *gopi.AppInstance, start chan<- struct{}, stop <-chan struct{}) error {
-	recursive := true
+	exclude, _ := app.AppFlags.GetString("scan.exclude")
+	hidden, _ := app.AppFlags.GetBool("scan.hidden")
+	symlinks, _ := app.AppFlags.GetBool("scan.symlinks")
+	samedevice, _ := app.AppFlags.GetBool("scan.samedevice")
+	opts := scan.Options{Hidden: hidden, Symlinks: symlinks, SameDevice: samedevice}
+	for _, pattern := range strings.Split(exclude, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			opts.Exclude = append(opts.Exclude, pattern)
+		}
+	}
+
+	// Cancel the walk when stopping
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
 	start <- gopi.DONE
 FOR_LOOP:
 	for {
 		select {
-		case path := <-paths:
-			if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
-				// Always skip hidden files
-				if strings.HasPrefix(info.Name(), ".") {
-					return nil
-				}
-				// Return any errors
-				if err != nil {
-					return err
-				}
-				// Recurse into folders
-				if info.IsDir() && recursive == false {
-					return filepath.SkipDir
-				}
-				// Skip non-regular files
-				if info.Mode().IsRegular() == false {
-					return nil
-				}
-
-				// Ignore zero-sized files
-				if info.Size() == 0 {
+		case e := <-paths:
+			// Output paths for opening
+			if err := scan.WalkSource(ctx, e.src, e.name, opts, func(name string, info os.FileInfo) error {
+				select {
+				case files <- entry{e.src, e.root, name}:
 					return nil
+				case <-ctx.Done():
+					return ctx.Err()
 				}
-
-				// Output path for opening
-				files <- path
-
-				// Return success
-				return nil
-			}); err != nil {
-				app.Logger.Error("%v: %v", path, err)
+			}); err == context.Canceled {
+				break FOR_LOOP
+			} else if err != nil {
+				app.Logger.Error("%v: %v", e, err)
 			}
-		case <-stop:
+		case <-ctx.Done():
 			break FOR_LOOP
 		}
 	}
@@ -91,17 +134,27 @@ FOR_LOOP:
 }
 
 func Main(app *gopi.AppInstance, done chan<- struct{}) error {
+	// Load identifiers assigned in earlier scans
+	if ids, _ := app.AppFlags.GetString("scan.ids"); ids != "" {
+		if err := util.DefaultIds.Load(ids); err != nil {
+			return err
+		}
+	}
 
-	// Get paths
-	for _, path := range app.AppFlags.Args() {
-		if stat, err := os.Stat(path); os.IsNotExist(err) {
-			app.Logger.Error("%v: Not Found", path)
+	// Get paths and URLs
+	for _, arg := range app.AppFlags.Args() {
+		if src, err := source.Open(arg); err == gopi.ErrNotFound {
+			app.Logger.Error("%v: Unsupported URL scheme", arg)
+		} else if err != nil {
+			app.Logger.Error("%v: %v", arg, err)
+		} else if stat, err := src.Stat("."); os.IsNotExist(err) || err == gopi.ErrNotFound {
+			app.Logger.Error("%v: Not Found", arg)
 		} else if err != nil {
-			app.Logger.Error("%v: %v", path, err)
+			app.Logger.Error("%v: %v", arg, err)
 		} else if stat.IsDir() {
-			paths <- path
+			paths <- entry{src, arg, "."}
 		} else if stat.Mode().IsRegular() {
-			files <- path
+			files <- entry{src, arg, "."}
 		}
 	}
 
@@ -109,6 +162,11 @@ func Main(app *gopi.AppInstance, done chan<- struct{}) error {
 	app.Logger.Info("Waiting for CTRL+C")
 	app.WaitForSignal()
 
+	// Save identifiers
+	if err := util.DefaultIds.Save(); err != nil {
+		return err
+	}
+
 	// Success
 	done <- gopi.DONE
 	return nil
@@ -116,9 +174,29 @@ func Main(app *gopi.AppInstance, done chan<- struct{}) error {
 
 ////////////////////////////////////////////////////////////////////////////////
 
+// String returns the local path or URL for the entry
+func (e entry) String() string {
+	if local, ok := e.src.(source.Local); ok {
+		return local.Path(e.name)
+	} else if e.name == "." {
+		return e.root
+	} else {
+		return strings.TrimSuffix(e.root, "/") + path.Clean("/"+e.name)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
 func main() {
 	// Create the configuration
 	config := gopi.NewAppConfig("ffmpeg", "sqlite")
+	config.AppFlags.FlagBool("nfo.write", false, "Write NFO sidecar files from metadata")
+	config.AppFlags.FlagDuration("scan.timeout", 0, "Give up probing a file after this time")
+	config.AppFlags.FlagString("scan.exclude", strings.Join(scan.DefaultExclude, ","), "Names of files and folders to skip, as globs or /regexp/")
+	config.AppFlags.FlagBool("scan.hidden", false, "Scan hidden files and folders")
+	config.AppFlags.FlagBool("scan.symlinks", false, "Follow symbolic links")
+	config.AppFlags.FlagBool("scan.samedevice", false, "Do not scan folders on other filesystems")
+	config.AppFlags.FlagString("scan.ids", "", "File which keeps the identifiers assigned to media files")
 
 	// Run the command line tool
 	os.Exit(gopi.CommandLineTool2(config, Main, WalkPaths, WalkFiles))
@@ -0,0 +1,329 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package comic
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Comic contains the pages and metadata read from a comic book archive,
+// with metadata from a ComicInfo.xml file when present
+type Comic struct {
+	Title     string
+	Series    string
+	Number    string
+	Volume    uint
+	Summary   string
+	Writer    string
+	Penciller string
+	Publisher string
+	Genre     string
+	Language  string
+	Year      uint
+	Month     uint
+	Day       uint
+	Manga     bool
+
+	// Pages are the names of the images in the archive in reading
+	// order, and Cover is the name of the cover image
+	Pages []string
+	Cover string
+
+	cover []byte
+}
+
+// comicInfo is the ComicInfo.xml schema used by ComicRack and others
+type comicInfo struct {
+	XMLName     xml.Name `xml:"ComicInfo"`
+	Title       string   `xml:"Title"`
+	Series      string   `xml:"Series"`
+	Number      string   `xml:"Number"`
+	Volume      uint     `xml:"Volume"`
+	Summary     string   `xml:"Summary"`
+	Writer      string   `xml:"Writer"`
+	Penciller   string   `xml:"Penciller"`
+	Publisher   string   `xml:"Publisher"`
+	Genre       string   `xml:"Genre"`
+	LanguageISO string   `xml:"LanguageISO"`
+	Year        uint     `xml:"Year"`
+	Month       uint     `xml:"Month"`
+	Day         uint     `xml:"Day"`
+	Manga       string   `xml:"Manga"`
+	Pages       []struct {
+		Image uint   `xml:"Image,attr"`
+		Type  string `xml:"Type,attr"`
+	} `xml:"Pages>Page"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	COMICINFO_MAXSIZE = 1024 * 1024
+	COVER_MAXSIZE     = 32 * 1024 * 1024
+)
+
+var (
+	// RAR archives are not read yet, as there is no RAR decoder in the
+	// standard library and the module does not depend on one. They are
+	// recognised by this signature so they are reported rather than
+	// failing as invalid ZIP archives.
+	rarHeader = []byte("Rar!\x1a\x07")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// ReadFile reads a CBZ file, which may also have a .cbr extension. It
+// returns gopi.ErrNotImplemented for RAR archives, which are usually
+// named .cbr, so callers can skip them until RAR is supported.
+func ReadFile(filename string) (*Comic, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		if stat, err := fh.Stat(); err != nil {
+			return nil, err
+		} else {
+			return Read(fh, stat.Size())
+		}
+	}
+}
+
+// Read reads a comic book archive of a known size, including the cover
+// image. It returns gopi.ErrNotImplemented for RAR archives and
+// gopi.ErrBadParameter for archives without any pages or with a cover
+// larger than COVER_MAXSIZE.
+func Read(r io.ReaderAt, size int64) (*Comic, error) {
+	header := make([]byte, len(rarHeader))
+	if _, err := r.ReadAt(header, 0); err == nil && bytes.Equal(header, rarHeader) {
+		return nil, gopi.ErrNotImplemented
+	}
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	this := new(Comic)
+	var info *zip.File
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		name := file.Name
+		base := path.Base(name)
+		if strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, ".") || strings.HasSuffix(name, "/") {
+			continue
+		} else if strings.EqualFold(base, "ComicInfo.xml") {
+			info = file
+		} else if isImage(name) {
+			this.Pages = append(this.Pages, name)
+			files[name] = file
+		}
+	}
+	if len(this.Pages) == 0 {
+		return nil, gopi.ErrBadParameter
+	}
+	sort.Slice(this.Pages, func(i, j int) bool { return less(this.Pages[i], this.Pages[j]) })
+	this.Cover = this.Pages[0]
+
+	if info != nil {
+		if err := this.readInfo(info); err != nil {
+			return nil, err
+		}
+	}
+	if file := files[this.Cover]; file.UncompressedSize64 > COVER_MAXSIZE {
+		return nil, gopi.ErrBadParameter
+	} else if cover, err := readAll(file); err != nil {
+		return nil, err
+	} else {
+		this.cover = cover
+	}
+
+	// Success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// CoverImage returns the mimetype and data for the cover image
+func (this *Comic) CoverImage() (string, []byte) {
+	return mimeTypeFor(this.Cover), this.cover
+}
+
+// Keys returns the metadata for the comic
+func (this *Comic) Keys() map[media.MetadataKey]string {
+	keys := make(map[media.MetadataKey]string)
+	keys[media.METADATA_KEY_PAGE_COUNT] = fmt.Sprint(len(this.Pages))
+	for key, value := range map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE:     this.Title,
+		media.METADATA_KEY_SERIES:    this.Series,
+		media.METADATA_KEY_SYNOPSIS:  this.Summary,
+		media.METADATA_KEY_AUTHOR:    this.Writer,
+		media.METADATA_KEY_ARTIST:    this.Penciller,
+		media.METADATA_KEY_PUBLISHER: this.Publisher,
+		media.METADATA_KEY_GENRE:     this.Genre,
+		media.METADATA_KEY_LANGUAGE:  this.Language,
+	} {
+		if value != "" {
+			keys[key] = value
+		}
+	}
+	if number, err := strconv.ParseFloat(this.Number, 64); err == nil {
+		keys[media.METADATA_KEY_SERIES_INDEX] = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	if this.Volume > 0 {
+		keys[media.METADATA_KEY_DISC] = fmt.Sprint(this.Volume)
+	}
+	if this.Year > 0 {
+		date := fmt.Sprintf("%04d", this.Year)
+		if this.Month > 0 {
+			date += fmt.Sprintf("-%02d", this.Month)
+			if this.Day > 0 {
+				date += fmt.Sprintf("-%02d", this.Day)
+			}
+		}
+		keys[media.METADATA_KEY_YEAR] = date
+	}
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Comic) String() string {
+	parts := ""
+	if this.Title != "" {
+		parts += fmt.Sprintf(" title=%v", strconv.Quote(this.Title))
+	}
+	if this.Series != "" {
+		parts += fmt.Sprintf(" series=%v", strconv.Quote(this.Series))
+	}
+	if this.Number != "" {
+		parts += fmt.Sprintf(" number=%v", strconv.Quote(this.Number))
+	}
+	parts += fmt.Sprintf(" pages=%v cover=%v", len(this.Pages), strconv.Quote(this.Cover))
+	return fmt.Sprintf("<comic.Comic>{%v }", parts)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Comic) readInfo(file *zip.File) error {
+	if file.UncompressedSize64 > COMICINFO_MAXSIZE {
+		return gopi.ErrBadParameter
+	}
+	data, err := readAll(file)
+	if err != nil {
+		return err
+	}
+	var info comicInfo
+	if err := xml.Unmarshal(data, &info); err != nil {
+		return err
+	}
+	this.Title = strings.TrimSpace(info.Title)
+	this.Series = strings.TrimSpace(info.Series)
+	this.Number = strings.TrimSpace(info.Number)
+	this.Volume = info.Volume
+	this.Summary = strings.TrimSpace(info.Summary)
+	this.Writer = strings.TrimSpace(info.Writer)
+	this.Penciller = strings.TrimSpace(info.Penciller)
+	this.Publisher = strings.TrimSpace(info.Publisher)
+	this.Genre = strings.TrimSpace(info.Genre)
+	this.Language = strings.TrimSpace(info.LanguageISO)
+	this.Year, this.Month, this.Day = info.Year, info.Month, info.Day
+	this.Manga = strings.HasPrefix(info.Manga, "Yes")
+
+	// Pages are indexed from zero in reading order
+	for _, page := range info.Pages {
+		if page.Type == "FrontCover" && int(page.Image) < len(this.Pages) {
+			this.Cover = this.Pages[page.Image]
+			break
+		}
+	}
+	return nil
+}
+
+func readAll(file *zip.File) ([]byte, error) {
+	fh, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	return ioutil.ReadAll(fh)
+}
+
+func isImage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp":
+		return true
+	default:
+		return false
+	}
+}
+
+func mimeTypeFor(name string) string {
+	if mimetype := mime.TypeByExtension(strings.ToLower(path.Ext(name))); mimetype != "" {
+		return mimetype
+	} else {
+		return "application/octet-stream"
+	}
+}
+
+// less compares names case-insensitively with numbers in order, so
+// that "page2.jpg" sorts before "page10.jpg"
+func less(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	for a != "" && b != "" {
+		na, ra := prefixNumber(a)
+		nb, rb := prefixNumber(b)
+		if na != "" && nb != "" {
+			ia, _ := strconv.ParseUint(na, 10, 64)
+			ib, _ := strconv.ParseUint(nb, 10, 64)
+			if ia != ib {
+				return ia < ib
+			}
+			a, b = ra, rb
+		} else if a[0] != b[0] {
+			return a[0] < b[0]
+		} else {
+			a, b = a[1:], b[1:]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// prefixNumber returns the leading digits of a string and the remainder
+func prefixNumber(value string) (string, string) {
+	i := 0
+	for i < len(value) && value[i] >= '0' && value[i] <= '9' {
+		i++
+	}
+	return value[:i], value[i:]
+}
@@ -0,0 +1,165 @@
+package comic_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	comic "github.com/djthorpe/gopi-media/comic"
+)
+
+const (
+	COMICINFO = `<?xml version="1.0" encoding="utf-8"?>
+<ComicInfo xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <Series>The Example</Series>
+  <Number>12</Number>
+  <Volume>2</Volume>
+  <Summary>An example issue.</Summary>
+  <Writer>Jane Writer</Writer>
+  <Penciller>Joe Artist</Penciller>
+  <Publisher>Example Comics</Publisher>
+  <Year>2019</Year>
+  <Month>3</Month>
+  <LanguageISO>en</LanguageISO>
+  <Manga>No</Manga>
+  <Pages>
+    <Page Image="0" Type="InnerCover" />
+    <Page Image="1" Type="FrontCover" />
+  </Pages>
+</ComicInfo>`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST COMIC
+
+func Test_comic_000(t *testing.T) {
+	t.Log("Test_comic_000")
+}
+
+func Test_comic_001(t *testing.T) {
+	data := archive(t, map[string]string{
+		"Issue/page10.jpg":     "10",
+		"Issue/page2.jpg":      "2",
+		"Issue/Page1.jpg":      "1",
+		"__MACOSX/._page1.jpg": "x",
+		"Issue/.thumb.png":     "x",
+		"Issue/notes.txt":      "x",
+		"ComicInfo.xml":        COMICINFO,
+	})
+	c, err := comic.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(c.Pages, ",") != "Issue/Page1.jpg,Issue/page2.jpg,Issue/page10.jpg" {
+		t.Error("Unexpected pages", c.Pages)
+	}
+	if mimetype, data := c.CoverImage(); c.Cover != "Issue/page2.jpg" || mimetype != "image/jpeg" || string(data) != "2" {
+		t.Error("Unexpected cover", c.Cover, mimetype, string(data))
+	}
+	keys := c.Keys()
+	for key, value := range map[media.MetadataKey]string{
+		media.METADATA_KEY_SERIES:       "The Example",
+		media.METADATA_KEY_SERIES_INDEX: "12",
+		media.METADATA_KEY_DISC:         "2",
+		media.METADATA_KEY_AUTHOR:       "Jane Writer",
+		media.METADATA_KEY_ARTIST:       "Joe Artist",
+		media.METADATA_KEY_PUBLISHER:    "Example Comics",
+		media.METADATA_KEY_YEAR:         "2019-03",
+		media.METADATA_KEY_LANGUAGE:     "en",
+		media.METADATA_KEY_PAGE_COUNT:   "3",
+	} {
+		if keys[key] != value {
+			t.Error("Unexpected value for", key, keys[key])
+		}
+	}
+	if _, exists := keys[media.METADATA_KEY_TITLE]; exists {
+		t.Error("Unexpected title")
+	}
+}
+
+func Test_comic_002(t *testing.T) {
+	// No pages
+	data := archive(t, map[string]string{"ComicInfo.xml": COMICINFO})
+	if _, err := comic.Read(bytes.NewReader(data), int64(len(data))); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	}
+	// RAR archives
+	data = []byte("Rar!\x1a\x07\x00rest of archive")
+	if _, err := comic.Read(bytes.NewReader(data), int64(len(data))); err != gopi.ErrNotImplemented {
+		t.Error("Expected ErrNotImplemented", err)
+	}
+	// Cover which is too large
+	data = archive(t, map[string]string{"001.png": strings.Repeat("\x00", comic.COVER_MAXSIZE+1)})
+	if _, err := comic.Read(bytes.NewReader(data), int64(len(data))); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	}
+	// Not an archive
+	data = []byte("not an archive")
+	if _, err := comic.Read(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("Expected error")
+	}
+}
+
+func Test_comic_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "comic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "Example 012.cbz")
+	if err := ioutil.WriteFile(filename, archive(t, map[string]string{"001.png": "1", "002.png": "2"}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := comic.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Type() != media.MEDIA_TYPE_COMIC || file.Title() != "Example 012" || file.Id() == "" || file.Filename() != filename {
+		t.Error("Unexpected file", file)
+	}
+	if artwork := file.Artwork(); len(artwork) != 1 || artwork[0].Type() != media.ARTWORK_TYPE_FRONT_COVER || artwork[0].MimeType() != "image/png" || string(artwork[0].Data()) != "1" {
+		t.Error("Unexpected artwork", artwork)
+	}
+	if file.StringForKey(media.METADATA_KEY_PAGE_COUNT) != "2" {
+		t.Error("Unexpected page count", file.StringForKey(media.METADATA_KEY_PAGE_COUNT))
+	}
+	if _, err := comic.Open(filepath.Join(dir, "missing.cbz")); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound", err)
+	}
+
+	// Archives can be read from a source
+	data := archive(t, map[string]string{"001.png": "1"})
+	if file, err := comic.OpenReader(bytes.NewReader(data), int64(len(data)), "remote/Example 013.cbz"); err != nil {
+		t.Error(err)
+	} else if file.Title() != "Example 013" || file.Id() == "" || file.Id() == file.Filename() || len(file.Artwork()) != 1 {
+		t.Error("Unexpected file", file)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func archive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for name, data := range files {
+		if fh, err := w.Create(name); err != nil {
+			t.Fatal(err)
+		} else if _, err := fh.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
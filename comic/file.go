@@ -0,0 +1,90 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package comic
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// File implements media.MediaFile for a comic book archive, with the
+// cover image as artwork
+type File struct {
+	*util.DocumentFile
+	comic *Comic
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN
+
+// Open reads a comic book archive as a media file
+func Open(filename string) (*File, error) {
+	if comic, err := ReadFile(filename); err != nil {
+		return nil, err
+	} else if file, err := util.NewDocumentFile(filename, comic.document()); err != nil {
+		return nil, err
+	} else {
+		return &File{file, comic}, nil
+	}
+}
+
+// OpenReader reads a comic book archive of a known size as a media file, such
+// as a file opened from a source. The filename is used for the title
+// and type.
+func OpenReader(r io.ReaderAt, size int64, filename string) (*File, error) {
+	if comic, err := Read(r, size); err != nil {
+		return nil, err
+	} else if file, err := util.NewDocumentFileForReader(r, size, filename, comic.document()); err != nil {
+		return nil, err
+	} else {
+		return &File{file, comic}, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Comic returns the pages and metadata read from the archive
+func (this *File) Comic() *Comic {
+	return this.comic
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *File) String() string {
+	return fmt.Sprintf("<comic.File>{ filename=%v %v }", strconv.Quote(this.Filename()), this.comic)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// document returns the comic as a document, titled with the comic title,
+// or the series and number when there is no title
+func (this *Comic) document() util.Document {
+	doc := util.Document{Type: media.MEDIA_TYPE_COMIC, Keys: this.Keys()}
+	if this.Title != "" {
+		doc.Title = this.Title
+	} else if this.Series != "" && this.Number != "" {
+		doc.Title = this.Series + " #" + this.Number
+	} else if this.Series != "" {
+		doc.Title = this.Series
+	}
+	doc.CoverType, doc.Cover = this.CoverImage()
+	return doc
+}
@@ -0,0 +1,120 @@
+/*
+	Go Language Raspberry Pi Interface
+	(c) Copyright David Thorpe 2019
+	All Rights Reserved
+	For Licensing and Usage information, please see LICENSE.md
+*/
+
+package media
+
+import (
+	"fmt"
+	"io"
+
+	// Frameworks
+	"github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type DeliverySystem uint32
+
+// TuneParams are the parameters for tuning to a multiplex
+type TuneParams struct {
+	// Delivery system
+	System DeliverySystem
+
+	// Frequency in Hz (for satellite, the intermediate frequency in kHz)
+	Frequency uint32
+
+	// Bandwidth in Hz for terrestrial systems, or zero for automatic
+	Bandwidth uint32
+
+	// Symbol rate in symbols per second for cable and satellite systems
+	SymbolRate uint32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Tuner wraps a broadcast receiver, which can be tuned to a multiplex
+// and enumerate the services carried on it
+type Tuner interface {
+	gopi.Driver
+
+	// Tune to a multiplex, and wait for the frontend to lock
+	Tune(TuneParams) error
+
+	// Return the services on the tuned multiplex
+	Services() ([]TunerService, error)
+}
+
+// Recorder writes the transport stream for a live service
+type Recorder interface {
+	// Write the transport stream for the service with the identifier
+	// to w, until stop is closed
+	Record(service string, w io.Writer, stop <-chan struct{}) error
+}
+
+// TunerService is a live service on a multiplex. The service name and
+// provider are returned with METADATA_KEY_SERVICE_NAME and
+// METADATA_KEY_SERVICE_PROVIDER
+type TunerService interface {
+	MediaItem
+
+	// Return the service identifier on the multiplex
+	ServiceId() uint16
+
+	// Return the elementary streams for the service
+	Streams() []MediaStream
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DELIVERY_SYSTEM_NONE DeliverySystem = iota
+	DELIVERY_SYSTEM_DVBT
+	DELIVERY_SYSTEM_DVBT2
+	DELIVERY_SYSTEM_DVBC
+	DELIVERY_SYSTEM_DVBS
+	DELIVERY_SYSTEM_DVBS2
+	DELIVERY_SYSTEM_ATSC
+	DELIVERY_SYSTEM_MAX = DELIVERY_SYSTEM_ATSC
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (s DeliverySystem) String() string {
+	switch s {
+	case DELIVERY_SYSTEM_NONE:
+		return "DELIVERY_SYSTEM_NONE"
+	case DELIVERY_SYSTEM_DVBT:
+		return "DELIVERY_SYSTEM_DVBT"
+	case DELIVERY_SYSTEM_DVBT2:
+		return "DELIVERY_SYSTEM_DVBT2"
+	case DELIVERY_SYSTEM_DVBC:
+		return "DELIVERY_SYSTEM_DVBC"
+	case DELIVERY_SYSTEM_DVBS:
+		return "DELIVERY_SYSTEM_DVBS"
+	case DELIVERY_SYSTEM_DVBS2:
+		return "DELIVERY_SYSTEM_DVBS2"
+	case DELIVERY_SYSTEM_ATSC:
+		return "DELIVERY_SYSTEM_ATSC"
+	default:
+		return "[?? Invalid DeliverySystem value]"
+	}
+}
+
+func (p TuneParams) String() string {
+	str := fmt.Sprintf("<media.TuneParams>{ system=%v frequency=%v", p.System, p.Frequency)
+	if p.Bandwidth != 0 {
+		str += fmt.Sprintf(" bandwidth=%v", p.Bandwidth)
+	}
+	if p.SymbolRate != 0 {
+		str += fmt.Sprintf(" symbol_rate=%v", p.SymbolRate)
+	}
+	return str + " }"
+}
@@ -0,0 +1,404 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package dvb
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Frontend is a DVB frontend device, which tunes to a multiplex
+type Frontend struct {
+	fh *os.File
+}
+
+// Demux is a DVB demultiplexer device, which reads sections
+type Demux struct {
+	fh *os.File
+}
+
+// DVR is a DVB recording device, which outputs the transport stream
+// packets for the PIDs tapped on the demultiplexer
+type DVR struct {
+	fh *os.File
+}
+
+// FrontendStatus is the lock status of a frontend
+type FrontendStatus uint32
+
+// dtvProperty mirrors the packed struct dtv_property
+type dtvProperty struct {
+	cmd      uint32
+	reserved [3]uint32
+	data     uint32
+	_        [dtvPropertyUnionSize - 4]byte
+	result   int32
+}
+
+// dtvProperties mirrors struct dtv_properties
+type dtvProperties struct {
+	num   uint32
+	props uintptr
+}
+
+// dmxSctFilterParams mirrors struct dmx_sct_filter_params
+type dmxSctFilterParams struct {
+	pid     uint16
+	filter  [16]byte
+	mask    [16]byte
+	mode    [16]byte
+	timeout uint32
+	flags   uint32
+}
+
+// dmxPesFilterParams mirrors struct dmx_pes_filter_params
+type dmxPesFilterParams struct {
+	pid     uint16
+	input   uint32
+	output  uint32
+	pesType uint32
+	flags   uint32
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	FE_HAS_SIGNAL  FrontendStatus = 0x01
+	FE_HAS_CARRIER FrontendStatus = 0x02
+	FE_HAS_VITERBI FrontendStatus = 0x04
+	FE_HAS_SYNC    FrontendStatus = 0x08
+	FE_HAS_LOCK    FrontendStatus = 0x10
+	FE_TIMEDOUT    FrontendStatus = 0x20
+)
+
+const (
+	// Size of the union in struct dtv_property, which ends in a pointer
+	dtvPropertyUnionSize = 48 + unsafe.Sizeof(uintptr(0))
+)
+
+const (
+	// Property commands from linux/dvb/frontend.h
+	dtvTune           = 1
+	dtvClear          = 2
+	dtvFrequency      = 3
+	dtvBandwidthHz    = 5
+	dtvSymbolRate     = 8
+	dtvDeliverySystem = 17
+
+	// Delivery systems from linux/dvb/frontend.h
+	sysDVBCAnnexA = 1
+	sysDVBT       = 3
+	sysDVBS       = 5
+	sysDVBS2      = 6
+	sysATSC       = 11
+	sysDVBT2      = 16
+
+	// Filter parameters from linux/dvb/dmx.h
+	dmxCheckCrc       = 1
+	dmxImmediateStart = 4
+	dmxInFrontend     = 0
+	dmxOutTsTap       = 2
+	dmxPesOther       = 20
+)
+
+var (
+	feReadStatus    = ioctlRead('o', 69, unsafe.Sizeof(uint32(0)))
+	feSetProperty   = ioctlWrite('o', 82, unsafe.Sizeof(dtvProperties{}))
+	dmxStop         = ioctl('o', 42)
+	dmxSetFilter    = ioctlWrite('o', 43, unsafe.Sizeof(dmxSctFilterParams{}))
+	dmxSetPesFilter = ioctlWrite('o', 44, unsafe.Sizeof(dmxPesFilterParams{}))
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FRONTEND
+
+// OpenFrontend opens the frontend device for an adapter
+func OpenFrontend(adapter, frontend uint) (*Frontend, error) {
+	if fh, err := os.OpenFile(fmt.Sprintf("/dev/dvb/adapter%v/frontend%v", adapter, frontend), os.O_RDWR, 0); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		return &Frontend{fh}, nil
+	}
+}
+
+// Close releases the frontend device
+func (this *Frontend) Close() error {
+	return this.fh.Close()
+}
+
+// Tune sets the tuning parameters and starts tuning. It does not
+// wait for the frontend to lock.
+func (this *Frontend) Tune(params media.TuneParams) error {
+	system := uint32(0)
+	switch params.System {
+	case media.DELIVERY_SYSTEM_DVBT:
+		system = sysDVBT
+	case media.DELIVERY_SYSTEM_DVBT2:
+		system = sysDVBT2
+	case media.DELIVERY_SYSTEM_DVBC:
+		system = sysDVBCAnnexA
+	case media.DELIVERY_SYSTEM_DVBS:
+		system = sysDVBS
+	case media.DELIVERY_SYSTEM_DVBS2:
+		system = sysDVBS2
+	case media.DELIVERY_SYSTEM_ATSC:
+		system = sysATSC
+	default:
+		return gopi.ErrBadParameter
+	}
+	if params.Frequency == 0 {
+		return gopi.ErrBadParameter
+	}
+
+	// Clear the cache and then set the new parameters
+	if err := this.setProperties(dtvProperty{cmd: dtvClear}); err != nil {
+		return err
+	}
+	props := []dtvProperty{
+		{cmd: dtvDeliverySystem, data: system},
+		{cmd: dtvFrequency, data: params.Frequency},
+	}
+	if params.Bandwidth != 0 {
+		props = append(props, dtvProperty{cmd: dtvBandwidthHz, data: params.Bandwidth})
+	}
+	if params.SymbolRate != 0 {
+		props = append(props, dtvProperty{cmd: dtvSymbolRate, data: params.SymbolRate})
+	}
+	props = append(props, dtvProperty{cmd: dtvTune})
+	return this.setProperties(props...)
+}
+
+// Status returns the lock status of the frontend
+func (this *Frontend) Status() (FrontendStatus, error) {
+	var status uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), feReadStatus, uintptr(unsafe.Pointer(&status))); errno != 0 {
+		return 0, os.NewSyscallError("ioctl", errno)
+	} else {
+		return FrontendStatus(status), nil
+	}
+}
+
+// WaitForLock polls the frontend status until it has locked, or
+// returns gopi.ErrDeadlineExceeded after the timeout
+func (this *Frontend) WaitForLock(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		if status, err := this.Status(); err != nil {
+			return err
+		} else if status&FE_HAS_LOCK != 0 {
+			return nil
+		} else if time.Now().After(deadline) {
+			return gopi.ErrDeadlineExceeded
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (this *Frontend) setProperties(props ...dtvProperty) error {
+	// The properties are copied to the heap, so that they do not move
+	// while the kernel reads them
+	buf := make([]dtvProperty, len(props))
+	copy(buf, props)
+	cmd := dtvProperties{uint32(len(buf)), uintptr(unsafe.Pointer(&buf[0]))}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), feSetProperty, uintptr(unsafe.Pointer(&cmd)))
+	runtime.KeepAlive(buf)
+	if errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	} else {
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DEMUX
+
+// OpenDemux opens the demultiplexer device for an adapter
+func OpenDemux(adapter, demux uint) (*Demux, error) {
+	if fh, err := os.OpenFile(fmt.Sprintf("/dev/dvb/adapter%v/demux%v", adapter, demux), os.O_RDWR, 0); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		return &Demux{fh}, nil
+	}
+}
+
+// Close releases the demultiplexer device
+func (this *Demux) Close() error {
+	return this.fh.Close()
+}
+
+// Table reads all the sections of a table from a PID, returning
+// gopi.ErrDeadlineExceeded if no section is received within the
+// timeout. Any table identifier extension is accepted.
+func (this *Demux) Table(pid uint16, table uint8, timeout time.Duration) ([]*Section, error) {
+	params := dmxSctFilterParams{pid: pid}
+	params.filter[0], params.mask[0] = table, 0xFF
+	return this.read(params, timeout)
+}
+
+// TableForExtension reads all the sections of a table with a table
+// identifier extension, such as the program number for a program map table
+func (this *Demux) TableForExtension(pid uint16, table uint8, extension uint16, timeout time.Duration) ([]*Section, error) {
+	params := dmxSctFilterParams{pid: pid}
+	params.filter[0], params.mask[0] = table, 0xFF
+	params.filter[1], params.mask[1] = uint8(extension>>8), 0xFF
+	params.filter[2], params.mask[2] = uint8(extension), 0xFF
+	return this.read(params, timeout)
+}
+
+// Filter starts reading sections from a PID whose table identifier
+// matches the table under the mask. Sections are then returned by Next
+// until Stop is called.
+func (this *Demux) Filter(pid uint16, table, mask uint8, timeout time.Duration) error {
+	params := dmxSctFilterParams{pid: pid}
+	params.filter[0], params.mask[0] = table, mask
+	return this.filter(params, timeout)
+}
+
+// Next returns the next section with a valid CRC, or
+// gopi.ErrDeadlineExceeded if no section is received within the
+// filter timeout
+func (this *Demux) Next() (*Section, error) {
+	buf := make([]byte, SECTION_MAXSIZE)
+	for {
+		if n, err := this.fh.Read(buf); err == syscall.ETIMEDOUT || isTimeout(err) {
+			return nil, gopi.ErrDeadlineExceeded
+		} else if err != nil {
+			return nil, err
+		} else if section, err := NewSection(buf[:n]); err == nil {
+			return section, nil
+		}
+	}
+}
+
+// Stop stops reading sections
+func (this *Demux) Stop() error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), dmxStop, 0); errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	} else {
+		return nil
+	}
+}
+
+// Tap sends the transport stream packets for a PID to the DVR device.
+// A separate demultiplexer must be opened for each PID.
+func (this *Demux) Tap(pid uint16) error {
+	params := dmxPesFilterParams{
+		pid:     pid,
+		input:   dmxInFrontend,
+		output:  dmxOutTsTap,
+		pesType: dmxPesOther,
+		flags:   dmxImmediateStart,
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), dmxSetPesFilter, uintptr(unsafe.Pointer(&params))); errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	} else {
+		return nil
+	}
+}
+
+func (this *Demux) filter(params dmxSctFilterParams, timeout time.Duration) error {
+	params.timeout = uint32(timeout / time.Millisecond)
+	params.flags = dmxCheckCrc | dmxImmediateStart
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, this.fh.Fd(), dmxSetFilter, uintptr(unsafe.Pointer(&params))); errno != 0 {
+		return os.NewSyscallError("ioctl", errno)
+	} else {
+		return nil
+	}
+}
+
+func (this *Demux) read(params dmxSctFilterParams, timeout time.Duration) ([]*Section, error) {
+	if err := this.filter(params, timeout); err != nil {
+		return nil, err
+	}
+	defer this.Stop()
+
+	// Read sections until all section numbers have been seen
+	sections := make(map[uint8]*Section)
+	for last := -1; last < 0 || len(sections) <= last; {
+		if section, err := this.Next(); err != nil {
+			return nil, err
+		} else if section.Current {
+			sections[section.Number] = section
+			last = int(section.LastNumber)
+		}
+	}
+	result := make([]*Section, 0, len(sections))
+	for i := 0; i < len(sections); i++ {
+		if section, exists := sections[uint8(i)]; exists {
+			result = append(result, section)
+		}
+	}
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DVR
+
+// OpenDVR opens the recording device for an adapter
+func OpenDVR(adapter, dvr uint) (*DVR, error) {
+	if fh, err := os.OpenFile(fmt.Sprintf("/dev/dvb/adapter%v/dvr%v", adapter, dvr), os.O_RDONLY, 0); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		return &DVR{fh}, nil
+	}
+}
+
+// Read reads transport stream packets
+func (this *DVR) Read(data []byte) (int, error) {
+	return this.fh.Read(data)
+}
+
+// Close releases the recording device
+func (this *DVR) Close() error {
+	return this.fh.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func isTimeout(err error) bool {
+	if err, ok := err.(*os.PathError); ok {
+		return err.Err == syscall.ETIMEDOUT
+	}
+	return false
+}
+
+func ioctl(t, nr uintptr) uintptr {
+	return (t << 8) | nr
+}
+
+func ioctlWrite(t, nr, size uintptr) uintptr {
+	return (1 << 30) | (size << 16) | ioctl(t, nr)
+}
+
+func ioctlRead(t, nr, size uintptr) uintptr {
+	return (2 << 30) | (size << 16) | ioctl(t, nr)
+}
@@ -0,0 +1,176 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package dvb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Event is an entry in the event information table
+type Event struct {
+	Id                uint16
+	ServiceId         uint16
+	TransportStreamId uint16
+	OriginalNetworkId uint16
+	Start             time.Time
+	Duration          time.Duration
+	Language          string
+	Title             string
+	Synopsis          string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Present and following events, and schedule, for the actual
+	// transport stream
+	TABLE_EIT_ACTUAL          = 0x4E
+	TABLE_EIT_SCHEDULE_ACTUAL = 0x50
+	TABLE_EIT_SCHEDULE_LAST   = 0x5F
+)
+
+const (
+	DESCRIPTOR_SHORT_EVENT    = 0x4D
+	DESCRIPTOR_EXTENDED_EVENT = 0x4E
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// EVENTS
+
+// IsEventTable returns true if the section is part of an event information
+// table for the actual transport stream
+func (this *Section) IsEventTable() bool {
+	return this.TableId == TABLE_EIT_ACTUAL || (this.TableId >= TABLE_EIT_SCHEDULE_ACTUAL && this.TableId <= TABLE_EIT_SCHEDULE_LAST)
+}
+
+// Events returns the events in an event information table section
+func (this *Section) Events() ([]Event, error) {
+	data := this.Data
+	if this.IsEventTable() == false || len(data) < 6 {
+		return nil, gopi.ErrBadParameter
+	}
+	transport := binary.BigEndian.Uint16(data)
+	network := binary.BigEndian.Uint16(data[2:])
+	events := make([]Event, 0)
+	for data = data[6:]; len(data) >= 12; {
+		length := int(binary.BigEndian.Uint16(data[10:]) & 0x0FFF)
+		if 12+length > len(data) {
+			return nil, gopi.ErrBadParameter
+		}
+		descriptors, err := Descriptors(data[12 : 12+length])
+		if err != nil {
+			return nil, err
+		}
+		event := Event{
+			Id:                binary.BigEndian.Uint16(data),
+			ServiceId:         this.Extension,
+			TransportStreamId: transport,
+			OriginalNetworkId: network,
+			Start:             startTime(data[2:7]),
+			Duration:          bcdDuration(data[7:10]),
+		}
+		extended := make([]string, 0)
+		for _, descriptor := range descriptors {
+			switch descriptor.Tag {
+			case DESCRIPTOR_SHORT_EVENT:
+				event.Language, event.Title, event.Synopsis = descriptor.shortEvent()
+			case DESCRIPTOR_EXTENDED_EVENT:
+				if text := descriptor.extendedEvent(); text != "" {
+					extended = append(extended, text)
+				}
+			}
+		}
+		if len(extended) > 0 {
+			// Extended event text is usually more complete than the synopsis
+			event.Synopsis = strings.Join(extended, "")
+		}
+		events = append(events, event)
+		data = data[12+length:]
+	}
+	return events, nil
+}
+
+// End returns the end time of the event
+func (this Event) End() time.Time {
+	return this.Start.Add(this.Duration)
+}
+
+func (this Event) String() string {
+	return fmt.Sprintf("<dvb.Event>{ id=0x%04X service=0x%04X start=%v duration=%v title=%q }", this.Id, this.ServiceId, this.Start.Format(time.RFC3339), this.Duration, this.Title)
+}
+
+// Identifier returns the globally unique identifier for a service,
+// which is made up of the original network, transport stream and
+// service identifiers
+func Identifier(network, transport, service uint16) string {
+	return fmt.Sprintf("dvb:%04x:%04x:%04x", network, transport, service)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// shortEvent returns the language, title and synopsis from a short
+// event descriptor
+func (this Descriptor) shortEvent() (string, string, string) {
+	data := this.Data
+	if len(data) < 4 || 4+int(data[3]) >= len(data) {
+		return "", "", ""
+	}
+	language := string(data[0:3])
+	title := data[4 : 4+int(data[3])]
+	data = data[4+int(data[3]):]
+	if 1+int(data[0]) > len(data) {
+		return language, Text(title), ""
+	}
+	return language, Text(title), Text(data[1 : 1+int(data[0])])
+}
+
+// extendedEvent returns the text from an extended event descriptor,
+// ignoring any items
+func (this Descriptor) extendedEvent() string {
+	data := this.Data
+	if len(data) < 5 || 5+int(data[4]) >= len(data) {
+		return ""
+	}
+	data = data[5+int(data[4]):]
+	if 1+int(data[0]) > len(data) {
+		return ""
+	}
+	return Text(data[1 : 1+int(data[0])])
+}
+
+// startTime decodes a modified Julian date and BCD time in UTC
+func startTime(data []byte) time.Time {
+	mjd := int(binary.BigEndian.Uint16(data))
+	if mjd == 0xFFFF {
+		return time.Time{}
+	}
+	// The modified Julian date epoch is 17 November 1858
+	date := time.Date(1858, time.November, 17, 0, 0, 0, 0, time.UTC).AddDate(0, 0, mjd)
+	return date.Add(bcdDuration(data[2:5]))
+}
+
+// bcdDuration decodes hours, minutes and seconds encoded as BCD
+func bcdDuration(data []byte) time.Duration {
+	bcd := func(b byte) time.Duration {
+		return time.Duration(b>>4)*10 + time.Duration(b&0x0F)
+	}
+	return bcd(data[0])*time.Hour + bcd(data[1])*time.Minute + bcd(data[2])*time.Second
+}
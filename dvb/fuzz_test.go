@@ -0,0 +1,32 @@
+//go:build go1.18
+// +build go1.18
+
+package dvb_test
+
+import (
+	"testing"
+
+	// Frameworks
+	dvb "github.com/djthorpe/gopi-media/dvb"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FUZZ
+
+func Fuzz_dvb_Section(f *testing.F) {
+	f.Add(uint8(dvb.TABLE_PAT), []byte{0x10, 0x41, 0xE1, 0x00})
+	f.Add(uint8(dvb.TABLE_PMT), []byte{0xE1, 0x01, 0xF0, 0x00, 0x1B, 0xE1, 0x01, 0xF0, 0x00})
+	f.Add(uint8(dvb.TABLE_SDT_ACTUAL), []byte{0x23, 0x3A, 0xFF, 0x10, 0x41, 0xFC, 0x80, 0x00})
+	f.Add(uint8(dvb.TABLE_EIT_ACTUAL), []byte{0x10, 0x01, 0x23, 0x3A, 0x00, 0x4E})
+	f.Fuzz(func(t *testing.T, table uint8, payload []byte) {
+		// Fuzz the payload of sections with a valid header and CRC
+		if section, err := dvb.NewSection(testSection(table, 0x1001, payload)); err != nil {
+			return
+		} else {
+			section.Programs()
+			section.ProgramMap()
+			section.Services()
+			section.Events()
+		}
+	})
+}
@@ -0,0 +1,355 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package dvb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Section is a PSI/SI table section
+type Section struct {
+	TableId    uint8
+	Extension  uint16
+	Version    uint8
+	Number     uint8
+	LastNumber uint8
+	Current    bool
+	Data       []byte
+}
+
+// Program is an entry in the program association table
+type Program struct {
+	Number uint16
+	Pid    uint16
+}
+
+// Stream is an elementary stream in the program map table
+type Stream struct {
+	Type        uint8
+	Pid         uint16
+	Descriptors []Descriptor
+}
+
+// ProgramMap is the program map table for a service
+type ProgramMap struct {
+	Number  uint16
+	PcrPid  uint16
+	Streams []Stream
+}
+
+// Service is an entry in the service description table
+type Service struct {
+	Id                uint16
+	OriginalNetworkId uint16
+	TransportStreamId uint16
+	Type              uint8
+	Provider          string
+	Name              string
+}
+
+// Descriptor is a tagged descriptor within a section
+type Descriptor struct {
+	Tag  uint8
+	Data []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	PID_PAT = 0x0000
+	PID_SDT = 0x0011
+	PID_EIT = 0x0012
+)
+
+const (
+	TABLE_PAT        = 0x00
+	TABLE_PMT        = 0x02
+	TABLE_SDT_ACTUAL = 0x42
+)
+
+const (
+	DESCRIPTOR_SERVICE  = 0x48
+	DESCRIPTOR_TELETEXT = 0x56
+	DESCRIPTOR_SUBTITLE = 0x59
+	DESCRIPTOR_AC3      = 0x6A
+	DESCRIPTOR_ENHANCED = 0x7A
+	DESCRIPTOR_AAC      = 0x7C
+)
+
+const (
+	SECTION_MAXSIZE     = 4096
+	SECTION_HEADER_SIZE = 8
+	SECTION_CRC_SIZE    = 4
+)
+
+const (
+	SERVICE_TYPE_TV      = 0x01
+	SERVICE_TYPE_RADIO   = 0x02
+	SERVICE_TYPE_RADIO_2 = 0x0A
+	SERVICE_TYPE_TV_SD   = 0x16
+	SERVICE_TYPE_TV_HD   = 0x19
+	SERVICE_TYPE_TV_UHD  = 0x1F
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// SECTIONS
+
+// NewSection parses a long-form section, checking the CRC
+func NewSection(data []byte) (*Section, error) {
+	if len(data) < SECTION_HEADER_SIZE+SECTION_CRC_SIZE {
+		return nil, gopi.ErrBadParameter
+	}
+	length := int(binary.BigEndian.Uint16(data[1:]) & 0x0FFF)
+	if data[1]&0x80 == 0 || length+3 > len(data) || length < SECTION_HEADER_SIZE-3+SECTION_CRC_SIZE {
+		return nil, gopi.ErrBadParameter
+	}
+	data = data[:length+3]
+	if crc32(data) != 0 {
+		return nil, fmt.Errorf("Section 0x%02X: CRC error", data[0])
+	}
+	return &Section{
+		TableId:    data[0],
+		Extension:  binary.BigEndian.Uint16(data[3:]),
+		Version:    (data[5] >> 1) & 0x1F,
+		Current:    data[5]&0x01 != 0,
+		Number:     data[6],
+		LastNumber: data[7],
+		Data:       data[SECTION_HEADER_SIZE : len(data)-SECTION_CRC_SIZE],
+	}, nil
+}
+
+// Programs returns the programs in a program association table section
+func (this *Section) Programs() ([]Program, error) {
+	if this.TableId != TABLE_PAT || len(this.Data)%4 != 0 {
+		return nil, gopi.ErrBadParameter
+	}
+	programs := make([]Program, 0, len(this.Data)/4)
+	for i := 0; i < len(this.Data); i += 4 {
+		number := binary.BigEndian.Uint16(this.Data[i:])
+		if number == 0 {
+			// Network information table
+			continue
+		}
+		programs = append(programs, Program{number, binary.BigEndian.Uint16(this.Data[i+2:]) & 0x1FFF})
+	}
+	return programs, nil
+}
+
+// ProgramMap returns the program map in a program map table section
+func (this *Section) ProgramMap() (*ProgramMap, error) {
+	data := this.Data
+	if this.TableId != TABLE_PMT || len(data) < 4 {
+		return nil, gopi.ErrBadParameter
+	}
+	pmt := &ProgramMap{
+		Number: this.Extension,
+		PcrPid: binary.BigEndian.Uint16(data) & 0x1FFF,
+	}
+	info := int(binary.BigEndian.Uint16(data[2:]) & 0x0FFF)
+	if 4+info > len(data) {
+		return nil, gopi.ErrBadParameter
+	}
+	for data = data[4+info:]; len(data) >= 5; {
+		length := int(binary.BigEndian.Uint16(data[3:]) & 0x0FFF)
+		if 5+length > len(data) {
+			return nil, gopi.ErrBadParameter
+		}
+		descriptors, err := Descriptors(data[5 : 5+length])
+		if err != nil {
+			return nil, err
+		}
+		pmt.Streams = append(pmt.Streams, Stream{
+			Type:        data[0],
+			Pid:         binary.BigEndian.Uint16(data[1:]) & 0x1FFF,
+			Descriptors: descriptors,
+		})
+		data = data[5+length:]
+	}
+	return pmt, nil
+}
+
+// Services returns the services in a service description table section
+func (this *Section) Services() ([]Service, error) {
+	data := this.Data
+	if this.TableId != TABLE_SDT_ACTUAL || len(data) < 3 {
+		return nil, gopi.ErrBadParameter
+	}
+	network := binary.BigEndian.Uint16(data)
+	services := make([]Service, 0)
+	for data = data[3:]; len(data) >= 5; {
+		length := int(binary.BigEndian.Uint16(data[3:]) & 0x0FFF)
+		if 5+length > len(data) {
+			return nil, gopi.ErrBadParameter
+		}
+		descriptors, err := Descriptors(data[5 : 5+length])
+		if err != nil {
+			return nil, err
+		}
+		service := Service{
+			Id:                binary.BigEndian.Uint16(data),
+			OriginalNetworkId: network,
+			TransportStreamId: this.Extension,
+		}
+		for _, descriptor := range descriptors {
+			if descriptor.Tag == DESCRIPTOR_SERVICE {
+				service.Type, service.Provider, service.Name = descriptor.service()
+			}
+		}
+		services = append(services, service)
+		data = data[5+length:]
+	}
+	return services, nil
+}
+
+// Descriptors parses a descriptor loop
+func Descriptors(data []byte) ([]Descriptor, error) {
+	descriptors := make([]Descriptor, 0)
+	for len(data) > 0 {
+		if len(data) < 2 || 2+int(data[1]) > len(data) {
+			return nil, gopi.ErrBadParameter
+		}
+		descriptors = append(descriptors, Descriptor{data[0], data[2 : 2+int(data[1])]})
+		data = data[2+int(data[1]):]
+	}
+	return descriptors, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA TYPES
+
+// MediaType returns the media type for an elementary stream
+func (this Stream) MediaType() media.MediaType {
+	switch this.Type {
+	case 0x01, 0x02, 0x10, 0x1B, 0x24:
+		// MPEG-1, MPEG-2, MPEG-4, H.264 and H.265 video
+		return media.MEDIA_TYPE_VIDEO
+	case 0x03, 0x04, 0x0F, 0x11, 0x81, 0x87:
+		// MPEG audio, AAC and ATSC AC-3
+		return media.MEDIA_TYPE_AUDIO
+	case 0x06:
+		// Private data, which is identified by descriptors
+		for _, descriptor := range this.Descriptors {
+			switch descriptor.Tag {
+			case DESCRIPTOR_AC3, DESCRIPTOR_ENHANCED, DESCRIPTOR_AAC:
+				return media.MEDIA_TYPE_AUDIO
+			case DESCRIPTOR_SUBTITLE:
+				return media.MEDIA_TYPE_SUBTITLE
+			}
+		}
+		return media.MEDIA_TYPE_DATA
+	default:
+		return media.MEDIA_TYPE_DATA
+	}
+}
+
+// MediaType returns the media type for a service
+func (this Service) MediaType() media.MediaType {
+	switch this.Type {
+	case SERVICE_TYPE_TV, SERVICE_TYPE_TV_SD, SERVICE_TYPE_TV_HD, SERVICE_TYPE_TV_UHD:
+		return media.MEDIA_TYPE_VIDEO
+	case SERVICE_TYPE_RADIO, SERVICE_TYPE_RADIO_2:
+		return media.MEDIA_TYPE_AUDIO
+	default:
+		return media.MEDIA_TYPE_DATA
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Section) String() string {
+	return fmt.Sprintf("<dvb.Section>{ table_id=0x%02X extension=0x%04X version=%v section=%v/%v size=%v }", this.TableId, this.Extension, this.Version, this.Number, this.LastNumber, len(this.Data))
+}
+
+func (this Service) String() string {
+	return fmt.Sprintf("<dvb.Service>{ id=0x%04X type=0x%02X provider=%q name=%q }", this.Id, this.Type, this.Provider, this.Name)
+}
+
+func (this Stream) String() string {
+	return fmt.Sprintf("<dvb.Stream>{ type=0x%02X pid=0x%04X }", this.Type, this.Pid)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// service returns the service type, provider and name from a
+// service descriptor
+func (this Descriptor) service() (uint8, string, string) {
+	data := this.Data
+	if len(data) < 2 || 2+int(data[1]) >= len(data) {
+		return 0, "", ""
+	}
+	provider := data[2 : 2+int(data[1])]
+	data = data[2+int(data[1]):]
+	if 1+int(data[0]) > len(data) {
+		return 0, "", ""
+	}
+	return this.Data[0], Text(provider), Text(data[1 : 1+int(data[0])])
+}
+
+// Text decodes a DVB string. Strings which are not marked as UTF-8
+// are treated as Latin-1, which is compatible with the default
+// character table for printable ASCII
+func Text(data []byte) string {
+	utf := false
+	if len(data) > 0 && data[0] < 0x20 {
+		switch data[0] {
+		case 0x10:
+			if len(data) < 3 {
+				return ""
+			}
+			data = data[3:]
+		case 0x15:
+			utf = true
+			data = data[1:]
+		default:
+			data = data[1:]
+		}
+	}
+	if utf {
+		return strings.TrimSpace(string(data))
+	}
+	str := make([]rune, 0, len(data))
+	for _, ch := range data {
+		if ch >= 0x80 && ch < 0xA0 {
+			// Control codes, including emphasis on and off
+			continue
+		}
+		str = append(str, rune(ch))
+	}
+	return strings.TrimSpace(string(str))
+}
+
+// crc32 returns the MPEG-2 CRC of the data, which is zero when the data
+// includes a valid CRC
+func crc32(data []byte) uint32 {
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = (crc << 1) ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
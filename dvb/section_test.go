@@ -0,0 +1,149 @@
+package dvb_test
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	dvb "github.com/djthorpe/gopi-media/dvb"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST SECTIONS
+
+func Test_section_000(t *testing.T) {
+	t.Log("Test_section_000")
+}
+
+func Test_section_001(t *testing.T) {
+	// PAT with a network entry and two programs
+	data := testSection(dvb.TABLE_PAT, 0x1001, []byte{
+		0x00, 0x00, 0xE0, 0x10,
+		0x10, 0x41, 0xE1, 0x00,
+		0x10, 0x42, 0xE2, 0x00,
+	})
+	if section, err := dvb.NewSection(data); err != nil {
+		t.Fatal(err)
+	} else if section.Extension != 0x1001 || section.Current == false {
+		t.Error("Unexpected section", section)
+	} else if programs, err := section.Programs(); err != nil {
+		t.Error(err)
+	} else if len(programs) != 2 || programs[0].Number != 0x1041 || programs[0].Pid != 0x0100 || programs[1].Pid != 0x0200 {
+		t.Error("Unexpected programs", programs)
+	}
+
+	// Corrupt the section
+	data[10] ^= 0xFF
+	if _, err := dvb.NewSection(data); err == nil {
+		t.Error("Expected CRC error")
+	}
+}
+
+func Test_section_002(t *testing.T) {
+	// SDT with one television service
+	descriptor := append([]byte{dvb.DESCRIPTOR_SERVICE, 0, dvb.SERVICE_TYPE_TV, 3}, "BBC"...)
+	descriptor = append(descriptor, 0x0A, 0x15)
+	descriptor = append(descriptor, "BBC ONE\xc2\xae"...)
+	descriptor[1] = byte(len(descriptor) - 2)
+	payload := []byte{0x23, 0x3A, 0xFF, 0x10, 0x41, 0xFC, 0x80, byte(len(descriptor))}
+	payload = append(payload, descriptor...)
+	if section, err := dvb.NewSection(testSection(dvb.TABLE_SDT_ACTUAL, 0x1001, payload)); err != nil {
+		t.Fatal(err)
+	} else if services, err := section.Services(); err != nil {
+		t.Error(err)
+	} else if len(services) != 1 {
+		t.Error("Unexpected services", services)
+	} else if s := services[0]; s.Id != 0x1041 || s.OriginalNetworkId != 0x233A || s.TransportStreamId != 0x1001 {
+		t.Error("Unexpected service", s)
+	} else if s.Provider != "BBC" || s.Name != "BBC ONE®" {
+		t.Error("Unexpected service", s)
+	} else if s.MediaType() != media.MEDIA_TYPE_VIDEO {
+		t.Error("Unexpected media type", s.MediaType())
+	}
+}
+
+func Test_section_003(t *testing.T) {
+	// PMT with video, audio, subtitle and teletext streams
+	payload := []byte{
+		0xE1, 0x01, 0xF0, 0x00,
+		0x1B, 0xE1, 0x01, 0xF0, 0x00,
+		0x03, 0xE1, 0x02, 0xF0, 0x00,
+		0x06, 0xE1, 0x03, 0xF0, 0x02, dvb.DESCRIPTOR_SUBTITLE, 0x00,
+		0x06, 0xE1, 0x04, 0xF0, 0x02, dvb.DESCRIPTOR_TELETEXT, 0x00,
+	}
+	expected := []media.MediaType{media.MEDIA_TYPE_VIDEO, media.MEDIA_TYPE_AUDIO, media.MEDIA_TYPE_SUBTITLE, media.MEDIA_TYPE_DATA}
+	if section, err := dvb.NewSection(testSection(dvb.TABLE_PMT, 0x1041, payload)); err != nil {
+		t.Fatal(err)
+	} else if pmt, err := section.ProgramMap(); err != nil {
+		t.Error(err)
+	} else if pmt.Number != 0x1041 || pmt.PcrPid != 0x0101 || len(pmt.Streams) != len(expected) {
+		t.Error("Unexpected program map", pmt)
+	} else {
+		for i, stream := range pmt.Streams {
+			if stream.MediaType() != expected[i] {
+				t.Error("Unexpected media type for", stream, stream.MediaType())
+			}
+		}
+	}
+}
+
+func Test_section_004(t *testing.T) {
+	if str := dvb.Text([]byte("\x86BBC\x87 News")); str != "BBC News" {
+		t.Error("Unexpected text", str)
+	}
+	if str := dvb.Text([]byte("Caf\xe9")); str != "Café" {
+		t.Error("Unexpected text", str)
+	}
+	if _, err := dvb.NewSection([]byte{0x00, 0xB0}); err == nil {
+		t.Error("Expected error for short section")
+	}
+}
+
+func Test_section_005(t *testing.T) {
+	// EIT present/following with one event on 1 June 2019 at 12:30 UTC
+	// (MJD 58635) for 1h15m, with a short event descriptor
+	descriptor := append([]byte{dvb.DESCRIPTOR_SHORT_EVENT, 0, 'e', 'n', 'g', 4}, "News"...)
+	descriptor = append(descriptor, 9)
+	descriptor = append(descriptor, "Headlines"...)
+	descriptor[1] = byte(len(descriptor) - 2)
+	payload := []byte{0x10, 0x01, 0x23, 0x3A, 0x00, dvb.TABLE_EIT_ACTUAL}
+	payload = append(payload, 0x00, 0x01, 0xE5, 0x0B, 0x12, 0x30, 0x00, 0x01, 0x15, 0x00, 0x80, byte(len(descriptor)))
+	payload = append(payload, descriptor...)
+	if section, err := dvb.NewSection(testSection(dvb.TABLE_EIT_ACTUAL, 0x1041, payload)); err != nil {
+		t.Fatal(err)
+	} else if events, err := section.Events(); err != nil {
+		t.Error(err)
+	} else if len(events) != 1 {
+		t.Error("Unexpected events", events)
+	} else if e := events[0]; e.ServiceId != 0x1041 || e.TransportStreamId != 0x1001 || e.OriginalNetworkId != 0x233A {
+		t.Error("Unexpected event", e)
+	} else if e.Start.Format(time.RFC3339) != "2019-06-01T12:30:00Z" || e.Duration != 75*time.Minute {
+		t.Error("Unexpected event times", e.Start, e.Duration)
+	} else if e.Title != "News" || e.Synopsis != "Headlines" || e.Language != "eng" {
+		t.Error("Unexpected event", e)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// testSection returns a long-form section with a valid CRC
+func testSection(table uint8, extension uint16, payload []byte) []byte {
+	data := []byte{table, 0, 0, byte(extension >> 8), byte(extension), 0xC1, 0x00, 0x00}
+	data = append(data, payload...)
+	binary.BigEndian.PutUint16(data[1:], 0xB000|uint16(len(data)+4-3))
+	crc := uint32(0xFFFFFFFF)
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for i := 0; i < 8; i++ {
+			if crc&0x80000000 != 0 {
+				crc = (crc << 1) ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return append(data, byte(crc>>24), byte(crc>>16), byte(crc>>8), byte(crc))
+}
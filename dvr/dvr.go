@@ -0,0 +1,250 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package dvr
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	epg "github.com/djthorpe/gopi-media/epg"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Timer is a scheduled recording of a service
+type Timer struct {
+	// Service is the identifier of the service to record
+	Service string
+
+	Start    time.Time
+	End      time.Time
+	Title    string
+	Synopsis string
+
+	// Path is the file which the transport stream is written to
+	Path string
+}
+
+// Schedule is a set of timers which do not overlap, since each tuner
+// can only record one multiplex at a time. The zero value is an empty
+// schedule which is safe for concurrent use.
+type Schedule struct {
+	lock   sync.Mutex
+	timers []Timer
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// File extension for recordings
+	DVR_EXT = ".ts"
+
+	// Interval between checks for timers which are due
+	DVR_INTERVAL = time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// SCHEDULE
+
+// Add schedules a timer, returning gopi.ErrBadParameter if it is not
+// valid or gopi.ErrOutOfOrder if it overlaps an existing timer
+func (this *Schedule) Add(timer Timer) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if timer.Service == "" || timer.Path == "" || timer.End.After(timer.Start) == false {
+		return gopi.ErrBadParameter
+	}
+	for _, other := range this.timers {
+		if other.Start.Before(timer.End) && other.End.After(timer.Start) {
+			return gopi.ErrOutOfOrder
+		}
+	}
+	this.timers = append(this.timers, timer)
+	sort.Slice(this.timers, func(i, j int) bool { return this.timers[i].Start.Before(this.timers[j].Start) })
+
+	// Success
+	return nil
+}
+
+// AddProgramme schedules a timer for a programme in the guide, with
+// padding before and after the programme, writing the recording in a
+// folder and returning the timer
+func (this *Schedule) AddProgramme(programme epg.Programme, padding time.Duration, folder string) (Timer, error) {
+	timer := Timer{
+		Service:  programme.Service,
+		Start:    programme.Start.Add(-padding),
+		End:      programme.End.Add(padding),
+		Title:    programme.Title,
+		Synopsis: programme.Synopsis,
+	}
+	timer.Path = filepath.Join(folder, Filename(timer))
+	return timer, this.Add(timer)
+}
+
+// Remove cancels a timer, returning gopi.ErrNotFound if it is
+// not scheduled
+func (this *Schedule) Remove(timer Timer) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for i, other := range this.timers {
+		if other == timer {
+			this.timers = append(this.timers[:i], this.timers[i+1:]...)
+			return nil
+		}
+	}
+	return gopi.ErrNotFound
+}
+
+// Timers returns the scheduled timers in order of start time
+func (this *Schedule) Timers() []Timer {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return append([]Timer{}, this.timers...)
+}
+
+// Due removes and returns the first timer which is due to record at a
+// time. Timers which ended before the time are discarded.
+func (this *Schedule) Due(at time.Time) (Timer, bool) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for len(this.timers) > 0 {
+		timer := this.timers[0]
+		if timer.Start.After(at) {
+			break
+		}
+		this.timers = this.timers[1:]
+		if timer.End.After(at) {
+			return timer, true
+		}
+	}
+	return Timer{}, false
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RECORD
+
+// Run records timers as they become due until stop is closed. Each
+// recording is written with an NFO sidecar describing the programme,
+// so the ffmpeg driver picks up the programme metadata when the
+// recording is scanned.
+func (this *Schedule) Run(recorder media.Recorder, log gopi.Logger, stop <-chan struct{}) error {
+	ticker := time.NewTicker(DVR_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			if timer, due := this.Due(now); due {
+				log.Info("Recording %v", timer)
+				if err := Record(recorder, timer, stop); err != nil {
+					log.Error("%v: %v", timer.Path, err)
+				}
+			}
+		}
+	}
+}
+
+// Record writes a recording for a timer, stopping at the end of the
+// timer or when stop is closed
+func Record(recorder media.Recorder, timer Timer, stop <-chan struct{}) error {
+	if err := os.MkdirAll(filepath.Dir(timer.Path), 0755); err != nil {
+		return err
+	}
+	fh, err := os.Create(timer.Path)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	// Stop at the end of the timer
+	end := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-time.After(time.Until(timer.End)):
+		case <-stop:
+		case <-done:
+		}
+		close(end)
+	}()
+	if err := recorder.Record(timer.Service, fh, end); err != nil {
+		return err
+	}
+
+	// Write the sidecar
+	return writeSidecar(timer)
+}
+
+// Filename returns the filename for a recording, which is the title
+// followed by the start date and time
+func Filename(timer Timer) string {
+	title := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(timer.Title))
+	if title == "" {
+		title = "Recording"
+	}
+	return title + " " + timer.Start.Local().Format("2006-01-02 1504") + DVR_EXT
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Timer) String() string {
+	return fmt.Sprintf("<dvr.Timer>{ service=%v start=%v end=%v title=%v path=%v }", this.Service, this.Start.Format(time.RFC3339), this.End.Format(time.RFC3339), strconv.Quote(this.Title), strconv.Quote(this.Path))
+}
+
+func (this *Schedule) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return fmt.Sprintf("<dvr.Schedule>{ timers=%v }", len(this.timers))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func writeSidecar(timer Timer) error {
+	doc := &nfo.Document{
+		XMLName: xml.Name{Local: nfo.ROOT_EPISODE},
+		Title:   timer.Title,
+		Plot:    timer.Synopsis,
+		Aired:   timer.Start.Local().Format("2006-01-02"),
+	}
+	path := strings.TrimSuffix(timer.Path, filepath.Ext(timer.Path)) + nfo.NFO_EXT
+	if fh, err := os.Create(path); err != nil {
+		return err
+	} else {
+		defer fh.Close()
+		return doc.Write(fh)
+	}
+}
@@ -0,0 +1,110 @@
+package dvr_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	dvr "github.com/djthorpe/gopi-media/dvr"
+	epg "github.com/djthorpe/gopi-media/epg"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RECORDER
+
+type recorder struct {
+	service string
+}
+
+func (this *recorder) Record(service string, w io.Writer, stop <-chan struct{}) error {
+	this.service = service
+	packet := make([]byte, 188)
+	packet[0] = 0x47
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+			if _, err := w.Write(packet); err != nil {
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST SCHEDULE
+
+func Test_dvr_000(t *testing.T) {
+	t.Log("Test_dvr_000")
+}
+
+func Test_dvr_001(t *testing.T) {
+	var schedule dvr.Schedule
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	programme := epg.Programme{Service: "dvb:233a:1001:1041", Start: start, End: start.Add(time.Hour), Title: "News/Weather"}
+	if timer, err := schedule.AddProgramme(programme, 2*time.Minute, "/tmp"); err != nil {
+		t.Fatal(err)
+	} else if timer.Start != start.Add(-2*time.Minute) || timer.End != start.Add(62*time.Minute) {
+		t.Error("Unexpected timer", timer)
+	} else if filepath.Dir(timer.Path) != "/tmp" || filepath.Ext(timer.Path) != dvr.DVR_EXT {
+		t.Error("Unexpected path", timer.Path)
+	}
+
+	// Overlapping timers are rejected
+	if _, err := schedule.AddProgramme(programme, 0, "/tmp"); err != gopi.ErrOutOfOrder {
+		t.Error("Expected ErrOutOfOrder, got", err)
+	}
+
+	// Timers are due between their start and end
+	if _, due := schedule.Due(start.Add(-time.Hour)); due {
+		t.Error("Unexpected due timer")
+	} else if timer, due := schedule.Due(start); due == false {
+		t.Error("Expected due timer")
+	} else if timer.Title != "News/Weather" {
+		t.Error("Unexpected timer", timer)
+	} else if timers := schedule.Timers(); len(timers) != 0 {
+		t.Error("Unexpected timers", timers)
+	}
+}
+
+func Test_dvr_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dvr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	timer := dvr.Timer{
+		Service:  "dvb:233a:1001:1041",
+		Start:    time.Now(),
+		End:      time.Now().Add(100 * time.Millisecond),
+		Title:    "News",
+		Synopsis: "The latest headlines.",
+	}
+	timer.Path = filepath.Join(dir, dvr.Filename(timer))
+	r := new(recorder)
+	if err := dvr.Record(r, timer, nil); err != nil {
+		t.Fatal(err)
+	} else if r.service != timer.Service {
+		t.Error("Unexpected service", r.service)
+	}
+	if stat, err := os.Stat(timer.Path); err != nil {
+		t.Error(err)
+	} else if stat.Size() == 0 || stat.Size()%188 != 0 {
+		t.Error("Unexpected size", stat.Size())
+	}
+	if doc, err := nfo.ReadFile(strings.TrimSuffix(timer.Path, dvr.DVR_EXT) + nfo.NFO_EXT); err != nil {
+		t.Error(err)
+	} else if doc.Title != "News" || doc.Plot != "The latest headlines." {
+		t.Error("Unexpected sidecar", doc)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package epg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	dvb "github.com/djthorpe/gopi-media/dvb"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Programme is a broadcast programme on a service
+type Programme struct {
+	// Service is the identifier of the service, which is the
+	// TunerService identifier for DVB, or the channel for XMLTV
+	Service string
+
+	Start    time.Time
+	End      time.Time
+	Title    string
+	Synopsis string
+	Language string
+}
+
+// Source is implemented by drivers which receive programme information,
+// such as a DVB tuner reading event information tables
+type Source interface {
+	// Add programmes to the guide, until no more are received
+	// within the timeout
+	ReadGuide(guide *Guide, timeout time.Duration) error
+}
+
+// Guide stores programmes for a set of services. The zero value is an
+// empty guide which is safe for concurrent use.
+type Guide struct {
+	lock       sync.Mutex
+	programmes map[string][]Programme
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ADD AND EXPIRE
+
+// Add inserts programmes into the guide, replacing any programmes on
+// the same service which overlap them
+func (this *Guide) Add(programmes ...Programme) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for _, programme := range programmes {
+		if programme.Service == "" || programme.End.After(programme.Start) == false {
+			return gopi.ErrBadParameter
+		}
+	}
+	if this.programmes == nil {
+		this.programmes = make(map[string][]Programme)
+	}
+	for _, programme := range programmes {
+		existing := this.programmes[programme.Service]
+		schedule := make([]Programme, 0, len(existing)+1)
+		for _, other := range existing {
+			if other.Start.Before(programme.End) && other.End.After(programme.Start) {
+				continue
+			}
+			schedule = append(schedule, other)
+		}
+		schedule = append(schedule, programme)
+		sort.Slice(schedule, func(i, j int) bool { return schedule[i].Start.Before(schedule[j].Start) })
+		this.programmes[programme.Service] = schedule
+	}
+
+	// Success
+	return nil
+}
+
+// AddSection adds the events from a DVB event information table section
+func (this *Guide) AddSection(section *dvb.Section) error {
+	if events, err := section.Events(); err != nil {
+		return err
+	} else {
+		programmes := make([]Programme, 0, len(events))
+		for _, event := range events {
+			if event.Start.IsZero() || event.Duration == 0 {
+				continue
+			}
+			programmes = append(programmes, Programme{
+				Service:  dvb.Identifier(event.OriginalNetworkId, event.TransportStreamId, event.ServiceId),
+				Start:    event.Start,
+				End:      event.End(),
+				Title:    event.Title,
+				Synopsis: event.Synopsis,
+				Language: event.Language,
+			})
+		}
+		return this.Add(programmes...)
+	}
+}
+
+// Expire removes programmes which ended before a time
+func (this *Guide) Expire(before time.Time) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for service, schedule := range this.programmes {
+		i := 0
+		for i < len(schedule) && schedule[i].End.After(before) == false {
+			i++
+		}
+		if i == len(schedule) {
+			delete(this.programmes, service)
+		} else {
+			this.programmes[service] = schedule[i:]
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// QUERIES
+
+// Services returns the identifiers of services with programmes
+func (this *Guide) Services() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	services := make([]string, 0, len(this.programmes))
+	for service := range this.programmes {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// NowNext returns the programme showing on a service at a time and the
+// programme which follows it. Either may be nil.
+func (this *Guide) NowNext(service string, at time.Time) (*Programme, *Programme) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	var now, next *Programme
+	for _, programme := range this.programmes[service] {
+		programme := programme
+		if programme.End.After(at) == false {
+			continue
+		} else if programme.Start.After(at) == false && now == nil {
+			now = &programme
+		} else if next == nil {
+			next = &programme
+			break
+		}
+	}
+	return now, next
+}
+
+// Programmes returns the programmes on a service which are showing
+// between two times
+func (this *Guide) Programmes(service string, from, to time.Time) []Programme {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	result := make([]Programme, 0)
+	for _, programme := range this.programmes[service] {
+		if programme.Start.Before(to) && programme.End.After(from) {
+			result = append(result, programme)
+		}
+	}
+	return result
+}
+
+// Search returns programmes whose title contains the text, ignoring
+// case, ordered by start time
+func (this *Guide) Search(title string) []Programme {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	title = strings.ToLower(strings.TrimSpace(title))
+	result := make([]Programme, 0)
+	for _, schedule := range this.programmes {
+		for _, programme := range schedule {
+			if strings.Contains(strings.ToLower(programme.Title), title) {
+				result = append(result, programme)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Programme) String() string {
+	return fmt.Sprintf("<epg.Programme>{ service=%v start=%v end=%v title=%v }", this.Service, this.Start.Format(time.RFC3339), this.End.Format(time.RFC3339), strconv.Quote(this.Title))
+}
+
+func (this *Guide) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	count := 0
+	for _, schedule := range this.programmes {
+		count += len(schedule)
+	}
+	return fmt.Sprintf("<epg.Guide>{ services=%v programmes=%v }", len(this.programmes), count)
+}
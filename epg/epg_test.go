@@ -0,0 +1,88 @@
+package epg_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	// Frameworks
+	epg "github.com/djthorpe/gopi-media/epg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST GUIDE
+
+const XMLTV = `<?xml version="1.0" encoding="UTF-8"?>
+<tv>
+  <channel id="bbc1.uk"><display-name>BBC One</display-name></channel>
+  <programme start="20190601120000 +0000" stop="20190601123000 +0000" channel="bbc1.uk">
+    <title lang="en">News</title>
+    <desc lang="en">The latest headlines.</desc>
+  </programme>
+  <programme start="20190601133000 +0100" stop="20190601140000 +0100" channel="bbc1.uk">
+    <title lang="en">Weather</title>
+  </programme>
+  <programme start="20190601140000 +0100" channel="bbc1.uk">
+    <title lang="en">No stop time</title>
+  </programme>
+</tv>`
+
+func Test_epg_000(t *testing.T) {
+	t.Log("Test_epg_000")
+}
+
+func Test_epg_001(t *testing.T) {
+	if programmes, err := epg.ReadXMLTV(strings.NewReader(XMLTV)); err != nil {
+		t.Fatal(err)
+	} else if len(programmes) != 2 {
+		t.Error("Unexpected programmes", programmes)
+	} else if programmes[0].Title != "News" || programmes[0].Synopsis != "The latest headlines." || programmes[0].Language != "en" {
+		t.Error("Unexpected programme", programmes[0])
+	} else if programmes[1].Start.UTC().Format(time.RFC3339) != "2019-06-01T12:30:00Z" {
+		t.Error("Unexpected start time", programmes[1].Start)
+	}
+}
+
+func Test_epg_002(t *testing.T) {
+	var guide epg.Guide
+	programmes, err := epg.ReadXMLTV(strings.NewReader(XMLTV))
+	if err != nil {
+		t.Fatal(err)
+	} else if err := guide.Add(programmes...); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2019, 6, 1, 12, 15, 0, 0, time.UTC)
+	if now, next := guide.NowNext("bbc1.uk", at); now == nil || now.Title != "News" {
+		t.Error("Unexpected now", now)
+	} else if next == nil || next.Title != "Weather" {
+		t.Error("Unexpected next", next)
+	}
+	if now, next := guide.NowNext("bbc1.uk", at.Add(time.Hour)); now != nil || next != nil {
+		t.Error("Unexpected now and next", now, next)
+	}
+	if results := guide.Search("weath"); len(results) != 1 || results[0].Title != "Weather" {
+		t.Error("Unexpected search results", results)
+	}
+	if results := guide.Programmes("bbc1.uk", at, at.Add(time.Minute)); len(results) != 1 {
+		t.Error("Unexpected programmes", results)
+	}
+
+	// A programme which overlaps replaces the existing programme
+	if err := guide.Add(epg.Programme{Service: "bbc1.uk", Start: at, End: at.Add(time.Hour), Title: "Film"}); err != nil {
+		t.Error(err)
+	} else if results := guide.Programmes("bbc1.uk", at.Add(-time.Hour), at.Add(time.Hour)); len(results) != 1 || results[0].Title != "Film" {
+		t.Error("Unexpected programmes", results)
+	}
+
+	// Expire all programmes
+	guide.Expire(at.Add(24 * time.Hour))
+	if services := guide.Services(); len(services) != 0 {
+		t.Error("Unexpected services", services)
+	}
+
+	// Programmes without a service are rejected
+	if err := guide.Add(epg.Programme{Start: at, End: at.Add(time.Hour)}); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.18
+// +build go1.18
+
+package epg_test
+
+import (
+	"bytes"
+	"testing"
+
+	// Frameworks
+	epg "github.com/djthorpe/gopi-media/epg"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FUZZ
+
+func Fuzz_epg_ReadXMLTV(f *testing.F) {
+	f.Add([]byte(XMLTV))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var guide epg.Guide
+		if programmes, err := epg.ReadXMLTV(bytes.NewReader(data)); err == nil {
+			if err := guide.Add(programmes...); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,116 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package epg
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type xmltvText struct {
+	Lang  string `xml:"lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmltvProgramme struct {
+	Start   string      `xml:"start,attr"`
+	Stop    string      `xml:"stop,attr"`
+	Channel string      `xml:"channel,attr"`
+	Title   []xmltvText `xml:"title"`
+	Desc    []xmltvText `xml:"desc"`
+}
+
+type xmltvDocument struct {
+	XMLName    xml.Name         `xml:"tv"`
+	Programmes []xmltvProgramme `xml:"programme"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	xmltvTimeFormat      = "20060102150405 -0700"
+	xmltvLocalTimeFormat = "20060102150405"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// XMLTV
+
+// ReadXMLTVFile reads programmes from an XMLTV file
+func ReadXMLTVFile(filename string) ([]Programme, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return ReadXMLTV(fh)
+	}
+}
+
+// ReadXMLTV reads programmes from an XMLTV document. The channel
+// identifier is used as the service for each programme, and programmes
+// without valid start and stop times are ignored.
+func ReadXMLTV(r io.Reader) ([]Programme, error) {
+	doc := new(xmltvDocument)
+	if err := xml.NewDecoder(r).Decode(doc); err != nil {
+		return nil, err
+	}
+	programmes := make([]Programme, 0, len(doc.Programmes))
+	for _, p := range doc.Programmes {
+		start, err := xmltvTime(p.Start)
+		if err != nil {
+			continue
+		}
+		end, err := xmltvTime(p.Stop)
+		if err != nil || end.After(start) == false || p.Channel == "" {
+			continue
+		}
+		programme := Programme{
+			Service: p.Channel,
+			Start:   start,
+			End:     end,
+		}
+		if len(p.Title) > 0 {
+			programme.Title = strings.TrimSpace(p.Title[0].Value)
+			programme.Language = p.Title[0].Lang
+		}
+		if len(p.Desc) > 0 {
+			programme.Synopsis = strings.TrimSpace(p.Desc[0].Value)
+		}
+		programmes = append(programmes, programme)
+	}
+	return programmes, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// xmltvTime parses an XMLTV date, which is in UTC when there is no
+// time zone offset
+func xmltvTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, gopi.ErrBadParameter
+	} else if t, err := time.Parse(xmltvTimeFormat, value); err == nil {
+		return t, nil
+	} else {
+		return time.Parse(xmltvLocalTimeFormat, value)
+	}
+}
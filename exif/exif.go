@@ -0,0 +1,473 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Exif contains the image attributes read from EXIF data
+type Exif struct {
+	Make         string
+	Model        string
+	Orientation  uint
+	Captured     time.Time
+	ExposureTime string
+	FNumber      float64
+	ISO          uint
+	FocalLength  float64
+
+	// Location is only set when HasLocation is true
+	HasLocation bool
+	Latitude    float64
+	Longitude   float64
+	Altitude    float64
+}
+
+type tag uint16
+
+type entry struct {
+	t     uint16
+	count uint32
+	value []byte
+}
+
+type ifd map[tag]entry
+
+type tiff struct {
+	order binary.ByteOrder
+	data  []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	EXIF_MAXSIZE = 1024 * 1024
+)
+
+const (
+	// IFD0 tags
+	TAG_MAKE        tag = 0x010F
+	TAG_MODEL       tag = 0x0110
+	TAG_ORIENTATION tag = 0x0112
+	TAG_DATETIME    tag = 0x0132
+	TAG_EXIF_IFD    tag = 0x8769
+	TAG_GPS_IFD     tag = 0x8825
+
+	// Exif IFD tags
+	TAG_EXPOSURE_TIME      tag = 0x829A
+	TAG_FNUMBER            tag = 0x829D
+	TAG_ISO                tag = 0x8827
+	TAG_DATETIME_ORIGINAL  tag = 0x9003
+	TAG_OFFSET_TIME_ORIGIN tag = 0x9011
+	TAG_FOCAL_LENGTH       tag = 0x920A
+
+	// GPS IFD tags
+	TAG_GPS_LATITUDE_REF  tag = 0x0001
+	TAG_GPS_LATITUDE      tag = 0x0002
+	TAG_GPS_LONGITUDE_REF tag = 0x0003
+	TAG_GPS_LONGITUDE     tag = 0x0004
+	TAG_GPS_ALTITUDE_REF  tag = 0x0005
+	TAG_GPS_ALTITUDE      tag = 0x0006
+)
+
+const (
+	TYPE_BYTE      = 1
+	TYPE_ASCII     = 2
+	TYPE_SHORT     = 3
+	TYPE_LONG      = 4
+	TYPE_RATIONAL  = 5
+	TYPE_UNDEFINED = 7
+	TYPE_SLONG     = 9
+	TYPE_SRATIONAL = 10
+)
+
+const (
+	exifDateFormat = "2006:01:02 15:04:05"
+)
+
+var (
+	exifHeader = []byte("Exif\x00\x00")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// ReadFile reads EXIF data from a JPEG or TIFF file
+func ReadFile(filename string) (*Exif, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return Read(fh)
+	}
+}
+
+// Read reads EXIF data from a JPEG or TIFF stream, and returns
+// gopi.ErrNotFound if there is no EXIF data
+func Read(r io.Reader) (*Exif, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	switch {
+	case header[0] == 0xFF && header[1] == 0xD8:
+		if data, err := readJPEG(r); err != nil {
+			return nil, err
+		} else {
+			return Decode(data)
+		}
+	case string(header[:]) == "II" || string(header[:]) == "MM":
+		if data, err := ioutil.ReadAll(io.LimitReader(r, EXIF_MAXSIZE)); err != nil {
+			return nil, err
+		} else {
+			return Decode(append(header[:], data...))
+		}
+	default:
+		return nil, gopi.ErrBadParameter
+	}
+}
+
+// Decode parses EXIF data in TIFF format, with or without the
+// "Exif" header used in JPEG files
+func Decode(data []byte) (*Exif, error) {
+	data = bytes.TrimPrefix(data, exifHeader)
+	if len(data) < 8 {
+		return nil, gopi.ErrBadParameter
+	}
+
+	// Read TIFF header
+	this := new(tiff)
+	switch string(data[0:2]) {
+	case "II":
+		this.order = binary.LittleEndian
+	case "MM":
+		this.order = binary.BigEndian
+	default:
+		return nil, gopi.ErrBadParameter
+	}
+	if this.order.Uint16(data[2:]) != 0x002A {
+		return nil, gopi.ErrBadParameter
+	}
+	this.data = data
+
+	// Read IFDs
+	ifd0, err := this.ifd(this.order.Uint32(data[4:]))
+	if err != nil {
+		return nil, err
+	}
+	exif := ifd{}
+	if offset, ok := this.uintFor(ifd0, TAG_EXIF_IFD); ok {
+		if exif, err = this.ifd(uint32(offset)); err != nil {
+			return nil, err
+		}
+	}
+	gps := ifd{}
+	if offset, ok := this.uintFor(ifd0, TAG_GPS_IFD); ok {
+		if gps, err = this.ifd(uint32(offset)); err != nil {
+			return nil, err
+		}
+	}
+
+	// Set attributes
+	result := new(Exif)
+	result.Make = this.stringFor(ifd0, TAG_MAKE)
+	result.Model = this.stringFor(ifd0, TAG_MODEL)
+	if value, ok := this.uintFor(ifd0, TAG_ORIENTATION); ok && value >= 1 && value <= 8 {
+		result.Orientation = value
+	}
+	if value, ok := this.uintFor(exif, TAG_ISO); ok {
+		result.ISO = value
+	}
+	if values := this.rationals(exif, TAG_EXPOSURE_TIME); len(values) == 1 && values[0][1] != 0 {
+		result.ExposureTime = exposure(values[0][0], values[0][1])
+	}
+	if value, ok := this.floatFor(exif, TAG_FNUMBER); ok {
+		result.FNumber = value
+	}
+	if value, ok := this.floatFor(exif, TAG_FOCAL_LENGTH); ok {
+		result.FocalLength = value
+	}
+
+	// Capture time, using the time zone offset where it is available
+	date := this.stringFor(exif, TAG_DATETIME_ORIGINAL)
+	if date == "" {
+		date = this.stringFor(ifd0, TAG_DATETIME)
+	}
+	if date != "" {
+		location := time.Local
+		if offset, err := time.Parse("-07:00", this.stringFor(exif, TAG_OFFSET_TIME_ORIGIN)); err == nil {
+			_, seconds := offset.Zone()
+			location = time.FixedZone("", seconds)
+		}
+		if value, err := time.ParseInLocation(exifDateFormat, date, location); err == nil {
+			result.Captured = value
+		}
+	}
+
+	// Location
+	lat, lon := this.degrees(gps, TAG_GPS_LATITUDE), this.degrees(gps, TAG_GPS_LONGITUDE)
+	if lat != nil && lon != nil {
+		result.HasLocation = true
+		result.Latitude, result.Longitude = *lat, *lon
+		if strings.ToUpper(this.stringFor(gps, TAG_GPS_LATITUDE_REF)) == "S" {
+			result.Latitude = -result.Latitude
+		}
+		if strings.ToUpper(this.stringFor(gps, TAG_GPS_LONGITUDE_REF)) == "W" {
+			result.Longitude = -result.Longitude
+		}
+		if value, ok := this.floatFor(gps, TAG_GPS_ALTITUDE); ok {
+			result.Altitude = value
+			if ref, ok := this.uintFor(gps, TAG_GPS_ALTITUDE_REF); ok && ref == 1 {
+				result.Altitude = -result.Altitude
+			}
+		}
+	}
+
+	// Success
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METADATA
+
+// Keys returns the EXIF attributes as metadata
+func (this *Exif) Keys() map[media.MetadataKey]string {
+	keys := make(map[media.MetadataKey]string)
+	if this.Make != "" {
+		keys[media.METADATA_KEY_CAMERA_MAKE] = this.Make
+	}
+	if this.Model != "" {
+		keys[media.METADATA_KEY_CAMERA_MODEL] = this.Model
+	}
+	if this.Orientation != 0 {
+		keys[media.METADATA_KEY_ORIENTATION] = fmt.Sprint(this.Orientation)
+	}
+	if this.Captured.IsZero() == false {
+		keys[media.METADATA_KEY_CAPTURED] = this.Captured.Format(time.RFC3339)
+	}
+	if this.ExposureTime != "" {
+		keys[media.METADATA_KEY_EXPOSURE_TIME] = this.ExposureTime
+	}
+	if this.FNumber != 0 {
+		keys[media.METADATA_KEY_APERTURE] = strconv.FormatFloat(this.FNumber, 'f', -1, 64)
+	}
+	if this.ISO != 0 {
+		keys[media.METADATA_KEY_ISO] = fmt.Sprint(this.ISO)
+	}
+	if this.FocalLength != 0 {
+		keys[media.METADATA_KEY_FOCAL_LENGTH] = strconv.FormatFloat(this.FocalLength, 'f', -1, 64)
+	}
+	if this.HasLocation {
+		keys[media.METADATA_KEY_LATITUDE] = strconv.FormatFloat(this.Latitude, 'f', 6, 64)
+		keys[media.METADATA_KEY_LONGITUDE] = strconv.FormatFloat(this.Longitude, 'f', 6, 64)
+		keys[media.METADATA_KEY_ALTITUDE] = strconv.FormatFloat(this.Altitude, 'f', 1, 64)
+		keys[media.METADATA_KEY_GEOHASH] = util.Geohash(this.Latitude, this.Longitude, util.GEOHASH_PRECISION)
+	}
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Exif) String() string {
+	str := "<exif>{"
+	str += fmt.Sprintf(" make=%v model=%v", strconv.Quote(this.Make), strconv.Quote(this.Model))
+	if this.Orientation != 0 {
+		str += fmt.Sprintf(" orientation=%v", this.Orientation)
+	}
+	if this.Captured.IsZero() == false {
+		str += fmt.Sprintf(" captured=%v", this.Captured.Format(time.RFC3339))
+	}
+	if this.HasLocation {
+		str += fmt.Sprintf(" location={ %.6f,%.6f }", this.Latitude, this.Longitude)
+	}
+	return str + " }"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// JPEG
+
+// readJPEG returns the EXIF data from the first APP1 segment
+func readJPEG(r io.Reader) ([]byte, error) {
+	var marker [4]byte
+	for {
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return nil, err
+		} else if marker[0] != 0xFF {
+			return nil, gopi.ErrBadParameter
+		}
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		switch {
+		case marker[1] == 0xDA || marker[1] == 0xD9:
+			// Start of scan or end of image: no EXIF data
+			return nil, gopi.ErrNotFound
+		case length < 0:
+			return nil, gopi.ErrBadParameter
+		case marker[1] == 0xE1:
+			data := make([]byte, length)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, err
+			} else if bytes.HasPrefix(data, exifHeader) {
+				return data, nil
+			}
+		default:
+			if _, err := io.CopyN(ioutil.Discard, r, int64(length)); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TIFF
+
+func (this *tiff) ifd(offset uint32) (ifd, error) {
+	data := this.data
+	if offset == 0 || int(offset)+2 > len(data) {
+		return nil, gopi.ErrBadParameter
+	}
+	count := int(this.order.Uint16(data[offset:]))
+	ptr := int(offset) + 2
+	if ptr+count*12 > len(data) {
+		return nil, gopi.ErrBadParameter
+	}
+	entries := make(ifd, count)
+	for i := 0; i < count; i, ptr = i+1, ptr+12 {
+		e := entry{
+			t:     this.order.Uint16(data[ptr+2:]),
+			count: this.order.Uint32(data[ptr+4:]),
+		}
+		size := int64(sizeOf(e.t)) * int64(e.count)
+		if size <= 0 {
+			continue
+		} else if size <= 4 {
+			e.value = data[ptr+8 : ptr+8+int(size)]
+		} else if start := int64(this.order.Uint32(data[ptr+8:])); start+size > int64(len(data)) {
+			continue
+		} else {
+			e.value = data[start : start+size]
+		}
+		entries[tag(this.order.Uint16(data[ptr:]))] = e
+	}
+	return entries, nil
+}
+
+func (this *tiff) stringFor(entries ifd, t tag) string {
+	if e, exists := entries[t]; exists == false || e.t != TYPE_ASCII {
+		return ""
+	} else {
+		return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+	}
+}
+
+func (this *tiff) uintFor(entries ifd, t tag) (uint, bool) {
+	if e, exists := entries[t]; exists == false || len(e.value) == 0 {
+		return 0, false
+	} else {
+		switch e.t {
+		case TYPE_BYTE, TYPE_UNDEFINED:
+			return uint(e.value[0]), true
+		case TYPE_SHORT:
+			return uint(this.order.Uint16(e.value)), true
+		case TYPE_LONG:
+			return uint(this.order.Uint32(e.value)), true
+		default:
+			return 0, false
+		}
+	}
+}
+
+func (this *tiff) rationals(entries ifd, t tag) [][2]uint32 {
+	if e, exists := entries[t]; exists == false || (e.t != TYPE_RATIONAL && e.t != TYPE_SRATIONAL) {
+		return nil
+	} else {
+		values := make([][2]uint32, 0, e.count)
+		for i := 0; i+8 <= len(e.value); i += 8 {
+			values = append(values, [2]uint32{this.order.Uint32(e.value[i:]), this.order.Uint32(e.value[i+4:])})
+		}
+		return values
+	}
+}
+
+func (this *tiff) floatFor(entries ifd, t tag) (float64, bool) {
+	if values := this.rationals(entries, t); len(values) != 1 || values[0][1] == 0 {
+		return 0, false
+	} else {
+		return float64(values[0][0]) / float64(values[0][1]), true
+	}
+}
+
+// degrees returns degrees from degrees, minutes and seconds
+func (this *tiff) degrees(entries ifd, t tag) *float64 {
+	values := this.rationals(entries, t)
+	if len(values) != 3 {
+		return nil
+	}
+	result := float64(0)
+	for i, divisor := range []float64{1, 60, 3600} {
+		if values[i][1] == 0 {
+			return nil
+		}
+		result += float64(values[i][0]) / float64(values[i][1]) / divisor
+	}
+	return &result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func sizeOf(t uint16) int {
+	switch t {
+	case TYPE_BYTE, TYPE_ASCII, TYPE_UNDEFINED:
+		return 1
+	case TYPE_SHORT:
+		return 2
+	case TYPE_LONG, TYPE_SLONG:
+		return 4
+	case TYPE_RATIONAL, TYPE_SRATIONAL:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// exposure returns an exposure time as a fraction of a second, or in
+// seconds for long exposures
+func exposure(num, den uint32) string {
+	if num == 0 {
+		return "0"
+	} else if num >= den {
+		return strconv.FormatFloat(float64(num)/float64(den), 'f', -1, 64)
+	} else if den%num == 0 {
+		return fmt.Sprintf("1/%v", den/num)
+	} else {
+		return fmt.Sprintf("1/%.0f", float64(den)/float64(num))
+	}
+}
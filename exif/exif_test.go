@@ -0,0 +1,172 @@
+package exif_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	exif "github.com/djthorpe/gopi-media/exif"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST DECODE
+
+func Test_exif_000(t *testing.T) {
+	t.Log("Test_exif_000")
+}
+
+func Test_exif_001(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		if e, err := exif.Decode(testTIFF(order)); err != nil {
+			t.Error(err)
+		} else if e.Make != "Canon" || e.Model != "EOS 5D" {
+			t.Error("Unexpected make or model", e)
+		} else if e.Orientation != 6 {
+			t.Error("Unexpected orientation", e.Orientation)
+		} else if e.ISO != 400 {
+			t.Error("Unexpected iso", e.ISO)
+		} else if e.ExposureTime != "1/250" {
+			t.Error("Unexpected exposure time", e.ExposureTime)
+		} else if e.FNumber != 2.8 {
+			t.Error("Unexpected fnumber", e.FNumber)
+		} else if e.Captured.Format("2006-01-02T15:04:05") != "2019-06-01T12:30:00" {
+			t.Error("Unexpected capture time", e.Captured)
+		} else if e.HasLocation == false {
+			t.Error("Expected location")
+		} else if e.Latitude < 51.49 || e.Latitude > 51.51 || e.Longitude > -0.11 || e.Longitude < -0.13 {
+			t.Error("Unexpected location", e.Latitude, e.Longitude)
+		} else {
+			t.Log(e)
+		}
+	}
+}
+
+func Test_exif_002(t *testing.T) {
+	// JPEG with APP0 and APP1 segments
+	data := append([]byte("Exif\x00\x00"), testTIFF(binary.BigEndian)...)
+	buf := new(bytes.Buffer)
+	buf.Write([]byte{0xFF, 0xD8})
+	buf.Write([]byte{0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00})
+	buf.Write([]byte{0xFF, 0xE1})
+	binary.Write(buf, binary.BigEndian, uint16(len(data)+2))
+	buf.Write(data)
+	buf.Write([]byte{0xFF, 0xDA, 0x00, 0x02})
+	if e, err := exif.Read(buf); err != nil {
+		t.Error(err)
+	} else if keys := e.Keys(); keys[media.METADATA_KEY_CAMERA_MAKE] != "Canon" {
+		t.Error("Unexpected keys", keys)
+	} else if keys[media.METADATA_KEY_ISO] != "400" || keys[media.METADATA_KEY_APERTURE] != "2.8" {
+		t.Error("Unexpected keys", keys)
+	} else if keys[media.METADATA_KEY_LATITUDE] == "" || keys[media.METADATA_KEY_GEOHASH] == "" {
+		t.Error("Unexpected keys", keys)
+	}
+}
+
+func Test_exif_003(t *testing.T) {
+	// JPEG without EXIF data
+	if _, err := exif.Read(bytes.NewReader([]byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02})); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	// Not an image
+	if _, err := exif.Read(bytes.NewReader([]byte("hello, world"))); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+	// Truncated TIFF
+	if _, err := exif.Decode(testTIFF(binary.LittleEndian)[:20]); err == nil {
+		t.Error("Expected error for truncated data")
+	}
+	// Missing file
+	if _, err := exif.ReadFile("nonexistent.jpg"); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+type field struct {
+	tag   uint16
+	t     uint16
+	count uint32
+	value []byte
+}
+
+// testTIFF returns TIFF data with IFD0, Exif and GPS IFDs
+func testTIFF(order binary.ByteOrder) []byte {
+	rational := func(values ...uint32) []byte {
+		data := make([]byte, len(values)*4)
+		for i, value := range values {
+			order.PutUint32(data[i*4:], value)
+		}
+		return data
+	}
+	short := func(value uint16) []byte {
+		data := make([]byte, 2)
+		order.PutUint16(data, value)
+		return data
+	}
+	long := func(value uint32) []byte {
+		data := make([]byte, 4)
+		order.PutUint32(data, value)
+		return data
+	}
+	ascii := func(value string) (uint32, []byte) {
+		return uint32(len(value) + 1), append([]byte(value), 0)
+	}
+
+	// Fixed layout: IFD0 at 8, Exif IFD at 200, GPS IFD at 400, data at 600
+	const exifOffset, gpsOffset = 200, 400
+	makeCount, makeValue := ascii("Canon")
+	modelCount, modelValue := ascii("EOS 5D")
+	dateCount, dateValue := ascii("2019:06:01 12:30:00")
+	ifds := map[uint32][]field{
+		8: {
+			{0x010F, 2, makeCount, makeValue},
+			{0x0110, 2, modelCount, modelValue},
+			{0x0112, 3, 1, short(6)},
+			{0x8769, 4, 1, long(exifOffset)},
+			{0x8825, 4, 1, long(gpsOffset)},
+		},
+		exifOffset: {
+			{0x829A, 5, 1, rational(1, 250)},
+			{0x829D, 5, 1, rational(28, 10)},
+			{0x8827, 3, 1, short(400)},
+			{0x9003, 2, dateCount, dateValue},
+		},
+		gpsOffset: {
+			{0x0001, 2, 2, []byte("N\x00")},
+			{0x0002, 5, 3, rational(51, 1, 30, 1, 0, 1)},
+			{0x0003, 2, 2, []byte("W\x00")},
+			{0x0004, 5, 3, rational(0, 1, 7, 1, 30, 1)},
+		},
+	}
+
+	data := make([]byte, 600)
+	if order == binary.LittleEndian {
+		copy(data, "II")
+	} else {
+		copy(data, "MM")
+	}
+	order.PutUint16(data[2:], 0x002A)
+	order.PutUint32(data[4:], 8)
+	for offset, fields := range ifds {
+		order.PutUint16(data[offset:], uint16(len(fields)))
+		ptr := offset + 2
+		for _, f := range fields {
+			order.PutUint16(data[ptr:], f.tag)
+			order.PutUint16(data[ptr+2:], f.t)
+			order.PutUint32(data[ptr+4:], f.count)
+			if len(f.value) <= 4 {
+				copy(data[ptr+8:], f.value)
+			} else {
+				order.PutUint32(data[ptr+8:], uint32(len(data)))
+				data = append(data, f.value...)
+			}
+			ptr += 12
+		}
+	}
+	return data
+}
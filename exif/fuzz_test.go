@@ -0,0 +1,27 @@
+//go:build go1.18
+// +build go1.18
+
+package exif_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	// Frameworks
+	exif "github.com/djthorpe/gopi-media/exif"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FUZZ
+
+func Fuzz_exif_Read(f *testing.F) {
+	f.Add(testTIFF(binary.LittleEndian))
+	f.Add(testTIFF(binary.BigEndian))
+	f.Add([]byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if e, err := exif.Read(bytes.NewReader(data)); err == nil {
+			e.Keys()
+		}
+	})
+}
@@ -14,6 +14,14 @@ import (
 /*
 #cgo pkg-config: libavformat
 #include <libavformat/avformat.h>
+#include <libavutil/pixdesc.h>
+
+extern int avformat_interrupt_callback(void*);
+
+static void avformat_set_interrupt(AVFormatContext* ctx, uintptr_t handle) {
+	ctx->interrupt_callback.callback = handle ? avformat_interrupt_callback : NULL;
+	ctx->interrupt_callback.opaque = (void*)handle;
+}
 */
 import "C"
 
@@ -28,7 +36,13 @@ type (
 )
 
 type (
-	AVIOFlags int
+	AVIOFlags        int
+	AVDisposition    int
+	AVCodecId        int
+	AVMediaType      int
+	AVFieldOrder     int
+	AVColorPrimaries int
+	AVColorTransfer  int
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -41,6 +55,61 @@ const (
 	AVIO_FLAG_READ_WRITE AVIOFlags = (AVIO_FLAG_READ | AVIO_FLAG_WRITE)
 )
 
+const (
+	AV_DISPOSITION_DEFAULT      AVDisposition = C.AV_DISPOSITION_DEFAULT
+	AV_DISPOSITION_ATTACHED_PIC AVDisposition = C.AV_DISPOSITION_ATTACHED_PIC
+)
+
+const (
+	AV_CODEC_ID_NONE  AVCodecId = C.AV_CODEC_ID_NONE
+	AV_CODEC_ID_MJPEG AVCodecId = C.AV_CODEC_ID_MJPEG
+	AV_CODEC_ID_PNG   AVCodecId = C.AV_CODEC_ID_PNG
+	AV_CODEC_ID_BMP   AVCodecId = C.AV_CODEC_ID_BMP
+	AV_CODEC_ID_GIF   AVCodecId = C.AV_CODEC_ID_GIF
+	AV_CODEC_ID_WEBP  AVCodecId = C.AV_CODEC_ID_WEBP
+)
+
+const (
+	AVMEDIA_TYPE_UNKNOWN    AVMediaType = C.AVMEDIA_TYPE_UNKNOWN
+	AVMEDIA_TYPE_VIDEO      AVMediaType = C.AVMEDIA_TYPE_VIDEO
+	AVMEDIA_TYPE_AUDIO      AVMediaType = C.AVMEDIA_TYPE_AUDIO
+	AVMEDIA_TYPE_DATA       AVMediaType = C.AVMEDIA_TYPE_DATA
+	AVMEDIA_TYPE_SUBTITLE   AVMediaType = C.AVMEDIA_TYPE_SUBTITLE
+	AVMEDIA_TYPE_ATTACHMENT AVMediaType = C.AVMEDIA_TYPE_ATTACHMENT
+)
+
+const (
+	AV_FIELD_UNKNOWN     AVFieldOrder = C.AV_FIELD_UNKNOWN
+	AV_FIELD_PROGRESSIVE AVFieldOrder = C.AV_FIELD_PROGRESSIVE
+	AV_FIELD_TT          AVFieldOrder = C.AV_FIELD_TT // Top coded first, top displayed first
+	AV_FIELD_BB          AVFieldOrder = C.AV_FIELD_BB // Bottom coded first, bottom displayed first
+	AV_FIELD_TB          AVFieldOrder = C.AV_FIELD_TB // Top coded first, bottom displayed first
+	AV_FIELD_BT          AVFieldOrder = C.AV_FIELD_BT // Bottom coded first, top displayed first
+)
+
+const (
+	AVCOL_PRI_UNSPECIFIED AVColorPrimaries = C.AVCOL_PRI_UNSPECIFIED
+	AVCOL_PRI_BT709       AVColorPrimaries = C.AVCOL_PRI_BT709
+	AVCOL_PRI_BT470BG     AVColorPrimaries = C.AVCOL_PRI_BT470BG
+	AVCOL_PRI_SMPTE170M   AVColorPrimaries = C.AVCOL_PRI_SMPTE170M
+	AVCOL_PRI_BT2020      AVColorPrimaries = C.AVCOL_PRI_BT2020
+	AVCOL_PRI_SMPTE431    AVColorPrimaries = C.AVCOL_PRI_SMPTE431
+	AVCOL_PRI_SMPTE432    AVColorPrimaries = C.AVCOL_PRI_SMPTE432
+)
+
+const (
+	AVCOL_TRC_UNSPECIFIED  AVColorTransfer = C.AVCOL_TRC_UNSPECIFIED
+	AVCOL_TRC_BT709        AVColorTransfer = C.AVCOL_TRC_BT709
+	AVCOL_TRC_GAMMA22      AVColorTransfer = C.AVCOL_TRC_GAMMA22
+	AVCOL_TRC_GAMMA28      AVColorTransfer = C.AVCOL_TRC_GAMMA28
+	AVCOL_TRC_SMPTE170M    AVColorTransfer = C.AVCOL_TRC_SMPTE170M
+	AVCOL_TRC_IEC61966_2_1 AVColorTransfer = C.AVCOL_TRC_IEC61966_2_1 // sRGB
+	AVCOL_TRC_BT2020_10    AVColorTransfer = C.AVCOL_TRC_BT2020_10
+	AVCOL_TRC_BT2020_12    AVColorTransfer = C.AVCOL_TRC_BT2020_12
+	AVCOL_TRC_SMPTE2084    AVColorTransfer = C.AVCOL_TRC_SMPTE2084    // PQ
+	AVCOL_TRC_ARIB_STD_B67 AVColorTransfer = C.AVCOL_TRC_ARIB_STD_B67 // HLG
+)
+
 var (
 	once_init,once_deinit sync.Once
 )
@@ -75,7 +144,7 @@ func (this *AVFormatContext) Free() {
 	C.avformat_free_context(ctx)
 }
 
-// Open Input
+// Open Input. The context is freed when the input cannot be opened
 func (this *AVFormatContext) OpenInput(filename string, input_format *AVInputFormat) error {
 	filename_ := C.CString(filename)
 	defer C.free(unsafe.Pointer(filename_))
@@ -93,6 +162,21 @@ func (this *AVFormatContext) OpenInput(filename string, input_format *AVInputFor
 	}
 }
 
+// Open Input, calling the interrupt function while the input is opened
+// and probed. Opening is abandoned when the function returns true, and
+// the function is no longer called once the input is opened
+func (this *AVFormatContext) OpenInputWithInterrupt(filename string, input_format *AVInputFormat, interrupt func() bool) error {
+	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
+	handle := newHandle(interrupt)
+	defer freeHandle(handle)
+	C.avformat_set_interrupt(ctx, C.uintptr_t(handle))
+	if err := this.OpenInput(filename, input_format); err != nil {
+		return err
+	}
+	C.avformat_set_interrupt(ctx, 0)
+	return nil
+}
+
 // Close Input
 func (this *AVFormatContext) CloseInput() {
 	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
@@ -223,6 +307,77 @@ func (this *AVStream) Metadata() *AVDictionary {
 	return &AVDictionary{ctx: this.metadata}
 }
 
+func (this *AVStream) Disposition() AVDisposition {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return AVDisposition(ctx.disposition)
+}
+
+func (this *AVStream) CodecId() AVCodecId {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return AVCodecId(ctx.codecpar.codec_id)
+}
+
+func (this *AVStream) CodecType() AVMediaType {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return AVMediaType(ctx.codecpar.codec_type)
+}
+
+// Return the size of video frames in pixels
+func (this *AVStream) Width() int {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return int(ctx.codecpar.width)
+}
+
+func (this *AVStream) Height() int {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return int(ctx.codecpar.height)
+}
+
+// Return the field order for interlaced video
+func (this *AVStream) FieldOrder() AVFieldOrder {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return AVFieldOrder(ctx.codecpar.field_order)
+}
+
+// Return the colour primaries and transfer function for video
+func (this *AVStream) ColorPrimaries() AVColorPrimaries {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return AVColorPrimaries(ctx.codecpar.color_primaries)
+}
+
+func (this *AVStream) ColorTransfer() AVColorTransfer {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	return AVColorTransfer(ctx.codecpar.color_trc)
+}
+
+// Return the bits per colour component for video, from the codec or
+// the pixel format, or zero if unknown
+func (this *AVStream) BitDepth() int {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	if ctx.codecpar.codec_type != C.AVMEDIA_TYPE_VIDEO {
+		return 0
+	} else if ctx.codecpar.bits_per_raw_sample > 0 {
+		return int(ctx.codecpar.bits_per_raw_sample)
+	} else if desc := C.av_pix_fmt_desc_get(C.enum_AVPixelFormat(ctx.codecpar.format)); desc != nil && desc.nb_components > 0 {
+		return int(desc.comp[0].depth)
+	} else {
+		return 0
+	}
+}
+
+// Return the attached picture for the stream, or nil if the
+// stream does not contain an attached picture
+func (this *AVStream) AttachedPicture() []byte {
+	ctx := (*C.AVStream)(unsafe.Pointer(this))
+	if AVDisposition(ctx.disposition)&AV_DISPOSITION_ATTACHED_PIC == 0 {
+		return nil
+	} else if ctx.attached_pic.data == nil || ctx.attached_pic.size <= 0 {
+		return nil
+	} else {
+		return C.GoBytes(unsafe.Pointer(ctx.attached_pic.data), ctx.attached_pic.size)
+	}
+}
+
 func (this *AVStream) String() string {
 	return fmt.Sprintf("<AVStream>{ index=%v id=%v metadata=%v }",this.Index(),this.Id(),this.Metadata())
 }
\ No newline at end of file
@@ -0,0 +1,136 @@
+package ffmpeg
+
+import (
+	"io"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#cgo pkg-config: libavformat
+#include <libavformat/avformat.h>
+
+extern int avio_read_callback(void*, uint8_t*, int);
+extern int64_t avio_seek_callback(void*, int64_t, int);
+
+static AVIOContext* avio_alloc_reader(uintptr_t handle, int size) {
+	unsigned char* buf = av_malloc(size);
+	if (buf == NULL) {
+		return NULL;
+	}
+	AVIOContext* ctx = avio_alloc_context(buf, size, 0, (void*)handle, avio_read_callback, NULL, avio_seek_callback);
+	if (ctx == NULL) {
+		av_free(buf);
+	}
+	return ctx;
+}
+
+static void avio_free_reader(AVIOContext* ctx) {
+	av_freep(&ctx->buffer);
+	avio_context_free(&ctx);
+}
+
+static void avformat_set_pb(AVFormatContext* ctx, AVIOContext* pb) {
+	ctx->pb = pb;
+	ctx->flags |= AVFMT_FLAG_CUSTOM_IO;
+}
+
+static int avio_error_eof() {
+	return AVERROR_EOF;
+}
+
+static int avio_error_io() {
+	return AVERROR(EIO);
+}
+*/
+import "C"
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type (
+	AVIOContext C.struct_AVIOContext
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	AVIO_BUFFER_SIZE = 32768
+	AVSEEK_SIZE      = C.AVSEEK_SIZE
+	AVSEEK_FORCE     = C.AVSEEK_FORCE
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// AVIOCONTEXT
+
+// NewAVIOContext returns a context which reads an input from r rather
+// than opening a file. The context is freed with Free once the input
+// has been closed
+func NewAVIOContext(r io.ReadSeeker) *AVIOContext {
+	handle := newHandle(r)
+	if ctx := C.avio_alloc_reader(C.uintptr_t(handle), AVIO_BUFFER_SIZE); ctx == nil {
+		freeHandle(handle)
+		return nil
+	} else {
+		return (*AVIOContext)(ctx)
+	}
+}
+
+// Free AVIOContext
+func (this *AVIOContext) Free() {
+	ctx := (*C.AVIOContext)(unsafe.Pointer(this))
+	freeHandle(uintptr(ctx.opaque))
+	C.avio_free_reader(ctx)
+}
+
+// SetIO reads the input from a context rather than opening the filename
+// passed to OpenInput. The context is not freed when the input is closed
+func (this *AVFormatContext) SetIO(pb *AVIOContext) {
+	ctx := (*C.AVFormatContext)(unsafe.Pointer(this))
+	C.avformat_set_pb(ctx, (*C.AVIOContext)(unsafe.Pointer(pb)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// avioRead reads into the buffer, returning the number of bytes read
+// or an error code
+func avioRead(handle uintptr, buf []byte) int {
+	if r, ok := handleValue(handle).(io.ReadSeeker); ok == false {
+		return int(C.avio_error_io())
+	} else if n, err := io.ReadAtLeast(r, buf, 1); n > 0 {
+		return n
+	} else if err == io.EOF {
+		return int(C.avio_error_eof())
+	} else {
+		return int(C.avio_error_io())
+	}
+}
+
+// avioSeek seeks the reader, returning the new offset or an error code.
+// The size of the input is returned for AVSEEK_SIZE
+func avioSeek(handle uintptr, offset int64, whence int) int64 {
+	r, ok := handleValue(handle).(io.ReadSeeker)
+	if ok == false {
+		return int64(C.avio_error_io())
+	}
+	whence &^= AVSEEK_FORCE
+	if whence == AVSEEK_SIZE {
+		if pos, err := r.Seek(0, io.SeekCurrent); err != nil {
+			return int64(C.avio_error_io())
+		} else if size, err := r.Seek(0, io.SeekEnd); err != nil {
+			return int64(C.avio_error_io())
+		} else if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return int64(C.avio_error_io())
+		} else {
+			return size
+		}
+	} else if pos, err := r.Seek(offset, whence); err != nil {
+		return int64(C.avio_error_io())
+	} else {
+		return pos
+	}
+}
@@ -0,0 +1,71 @@
+package ffmpeg
+
+import (
+	"sync"
+	"unsafe"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CGO
+
+/*
+#include <stdint.h>
+*/
+import "C"
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+// Go values can't be passed to C, so callbacks are passed a handle
+// which is looked up to find the value
+var (
+	handle_lock   sync.Mutex
+	handle_next   uintptr
+	handle_values = make(map[uintptr]interface{})
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// HANDLES
+
+func newHandle(value interface{}) uintptr {
+	handle_lock.Lock()
+	defer handle_lock.Unlock()
+	handle_next++
+	handle_values[handle_next] = value
+	return handle_next
+}
+
+func handleValue(handle uintptr) interface{} {
+	handle_lock.Lock()
+	defer handle_lock.Unlock()
+	return handle_values[handle]
+}
+
+func freeHandle(handle uintptr) {
+	handle_lock.Lock()
+	defer handle_lock.Unlock()
+	delete(handle_values, handle)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CALLBACKS
+
+//export avformat_interrupt_callback
+func avformat_interrupt_callback(opaque unsafe.Pointer) C.int {
+	if interrupt, ok := handleValue(uintptr(opaque)).(func() bool); ok && interrupt() {
+		return 1
+	} else {
+		return 0
+	}
+}
+
+//export avio_read_callback
+func avio_read_callback(opaque unsafe.Pointer, buf *C.uint8_t, size C.int) C.int {
+	data := (*[1 << 30]byte)(unsafe.Pointer(buf))[:size:size]
+	return C.int(avioRead(uintptr(opaque), data))
+}
+
+//export avio_seek_callback
+func avio_seek_callback(opaque unsafe.Pointer, offset C.int64_t, whence C.int) C.int64_t {
+	return C.int64_t(avioSeek(uintptr(opaque), int64(offset), int(whence)))
+}
@@ -0,0 +1,281 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package fixture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Audio describes a WAV file of silence
+type Audio struct {
+	SampleRate uint32
+	Channels   uint16
+	Duration   time.Duration
+
+	// Tags are written in a RIFF INFO chunk
+	Tags map[media.MetadataKey]string
+}
+
+// Video describes an uncompressed YUV4MPEG2 file of colour bars
+type Video struct {
+	Width, Height uint
+	FrameRate     uint
+	Duration      time.Duration
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_SAMPLE_RATE = 8000
+	DEFAULT_FRAME_RATE  = 25
+	DEFAULT_WIDTH       = 64
+	DEFAULT_HEIGHT      = 48
+)
+
+var (
+	// RIFF INFO chunk identifiers for metadata keys
+	infoKeys = map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE:     "INAM",
+		media.METADATA_KEY_ARTIST:    "IART",
+		media.METADATA_KEY_ALBUM:     "IPRD",
+		media.METADATA_KEY_COMMENT:   "ICMT",
+		media.METADATA_KEY_GENRE:     "IGNR",
+		media.METADATA_KEY_COPYRIGHT: "ICOP",
+		media.METADATA_KEY_YEAR:      "ICRD",
+		media.METADATA_KEY_TRACK:     "IPRT",
+	}
+
+	// 75% colour bars: white, yellow, cyan, green, magenta, red, blue
+	// and black
+	colourBars = []color.RGBA{
+		{191, 191, 191, 255},
+		{191, 191, 0, 255},
+		{0, 191, 191, 255},
+		{0, 191, 0, 255},
+		{191, 0, 191, 255},
+		{191, 0, 0, 255},
+		{0, 0, 191, 255},
+		{0, 0, 0, 255},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIO
+
+// WriteWAV writes a 16-bit PCM WAV file of silence. The output is
+// the same for the same parameters.
+func WriteWAV(w io.Writer, audio Audio) error {
+	if audio.SampleRate == 0 {
+		audio.SampleRate = DEFAULT_SAMPLE_RATE
+	}
+	if audio.Channels == 0 {
+		audio.Channels = 1
+	}
+	if audio.Duration < 0 {
+		return gopi.ErrBadParameter
+	}
+	samples := uint32(audio.Duration * time.Duration(audio.SampleRate) / time.Second)
+	blockAlign := audio.Channels * 2
+	data := samples * uint32(blockAlign)
+	info := infoChunk(audio.Tags)
+
+	// RIFF header and format chunk
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(4+8+16+len(info)+8)+data+data%2)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(buf, binary.LittleEndian, struct {
+		Size                      uint32
+		Format, Channels          uint16
+		SampleRate, ByteRate      uint32
+		BlockAlign, BitsPerSample uint16
+	}{16, 1, audio.Channels, audio.SampleRate, audio.SampleRate * uint32(blockAlign), blockAlign, 16})
+	buf.Write(info)
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, data)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	// Silence, padded to an even length
+	if _, err := io.CopyN(w, zero{}, int64(data+data%2)); err != nil {
+		return err
+	}
+
+	// Success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// VIDEO
+
+// WriteY4M writes colour bars as an uncompressed YUV4MPEG2 stream,
+// which can be read by ffmpeg
+func WriteY4M(w io.Writer, video Video) error {
+	if video.Width == 0 {
+		video.Width = DEFAULT_WIDTH
+	}
+	if video.Height == 0 {
+		video.Height = DEFAULT_HEIGHT
+	}
+	if video.FrameRate == 0 {
+		video.FrameRate = DEFAULT_FRAME_RATE
+	}
+	if video.Width%2 != 0 || video.Height%2 != 0 || video.Duration < 0 {
+		return gopi.ErrBadParameter
+	}
+
+	// Planes for one frame in 4:2:0 format
+	y := make([]byte, video.Width*video.Height)
+	cb := make([]byte, video.Width*video.Height/4)
+	cr := make([]byte, video.Width*video.Height/4)
+	for col := uint(0); col < video.Width; col++ {
+		c := colourBars[col*uint(len(colourBars))/video.Width]
+		yy, u, v := color.RGBToYCbCr(c.R, c.G, c.B)
+		for row := uint(0); row < video.Height; row++ {
+			y[row*video.Width+col] = yy
+			if row%2 == 0 && col%2 == 0 {
+				cb[(row/2)*(video.Width/2)+col/2] = u
+				cr[(row/2)*(video.Width/2)+col/2] = v
+			}
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	fmt.Fprintf(buf, "YUV4MPEG2 W%v H%v F%v:1 Ip A1:1 C420jpeg\n", video.Width, video.Height, video.FrameRate)
+	frames := uint(video.Duration * time.Duration(video.FrameRate) / time.Second)
+	if frames == 0 {
+		frames = 1
+	}
+	for i := uint(0); i < frames; i++ {
+		buf.WriteString("FRAME\n")
+		buf.Write(y)
+		buf.Write(cb)
+		buf.Write(cr)
+	}
+	return buf.Flush()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// IMAGE
+
+// ColourBars returns an image of colour bars
+func ColourBars(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		c := colourBars[x*len(colourBars)/width]
+		for y := 0; y < height; y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// WriteJPEG writes colour bars as a JPEG image
+func WriteJPEG(w io.Writer, width, height int) error {
+	if width <= 0 || height <= 0 {
+		return gopi.ErrBadParameter
+	}
+	return jpeg.Encode(w, ColourBars(width, height), nil)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FILES
+
+// Create writes a fixture to a file, choosing the format from the
+// file extension, which can be .wav, .y4m or .jpg. The audio and video
+// descriptions are used for the formats they apply to.
+func Create(filename string, audio Audio, video Video) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".wav":
+		err = WriteWAV(fh, audio)
+	case ".y4m":
+		err = WriteY4M(fh, video)
+	case ".jpg", ".jpeg":
+		err = WriteJPEG(fh, int(video.Width), int(video.Height))
+	default:
+		err = gopi.ErrBadParameter
+	}
+	if err != nil {
+		fh.Close()
+		os.Remove(filename)
+		return err
+	}
+	return fh.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+type zero struct{}
+
+func (zero) Read(data []byte) (int, error) {
+	for i := range data {
+		data[i] = 0
+	}
+	return len(data), nil
+}
+
+// infoChunk returns a RIFF LIST chunk with INFO tags, or nil if
+// there are no tags which can be written
+func infoChunk(tags map[media.MetadataKey]string) []byte {
+	keys := make([]media.MetadataKey, 0, len(tags))
+	for key, value := range tags {
+		if _, exists := infoKeys[key]; exists && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(i, j int) bool { return infoKeys[keys[i]] < infoKeys[keys[j]] })
+
+	body := bytes.NewBufferString("INFO")
+	for _, key := range keys {
+		value := append([]byte(tags[key]), 0)
+		body.WriteString(infoKeys[key])
+		binary.Write(body, binary.LittleEndian, uint32(len(value)))
+		body.Write(value)
+		if len(value)%2 != 0 {
+			body.WriteByte(0)
+		}
+	}
+	chunk := bytes.NewBufferString("LIST")
+	binary.Write(chunk, binary.LittleEndian, uint32(body.Len()))
+	chunk.Write(body.Bytes())
+	return chunk.Bytes()
+}
@@ -0,0 +1,104 @@
+package fixture_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	fixture "github.com/djthorpe/gopi-media/fixture"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST FIXTURES
+
+func Test_fixture_000(t *testing.T) {
+	t.Log("Test_fixture_000")
+}
+
+func Test_fixture_001(t *testing.T) {
+	buf := new(bytes.Buffer)
+	audio := fixture.Audio{
+		SampleRate: 8000,
+		Channels:   2,
+		Duration:   time.Second / 2,
+		Tags: map[media.MetadataKey]string{
+			media.METADATA_KEY_TITLE:  "Silence",
+			media.METADATA_KEY_ARTIST: "Nobody",
+		},
+	}
+	if err := fixture.WriteWAV(buf, audio); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if string(data[0:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " {
+		t.Fatal("Unexpected header", data[:16])
+	} else if size := binary.LittleEndian.Uint32(data[4:]); int(size) != len(data)-8 {
+		t.Error("Unexpected RIFF size", size, len(data))
+	} else if channels := binary.LittleEndian.Uint16(data[22:]); channels != 2 {
+		t.Error("Unexpected channels", channels)
+	} else if bytes.Contains(data, []byte("INAM\x08\x00\x00\x00Silence\x00")) == false {
+		t.Error("Missing title tag")
+	} else if i := bytes.Index(data, []byte("data")); i < 0 || binary.LittleEndian.Uint32(data[i+4:]) != 16000 {
+		t.Error("Unexpected data chunk")
+	}
+
+	// Output is deterministic
+	other := new(bytes.Buffer)
+	if err := fixture.WriteWAV(other, audio); err != nil {
+		t.Error(err)
+	} else if bytes.Equal(data, other.Bytes()) == false {
+		t.Error("Expected identical output")
+	}
+}
+
+func Test_fixture_002(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := fixture.WriteY4M(buf, fixture.Video{Width: 16, Height: 8, FrameRate: 10, Duration: time.Second / 2}); err != nil {
+		t.Fatal(err)
+	}
+	header := "YUV4MPEG2 W16 H8 F10:1 Ip A1:1 C420jpeg\n"
+	frame := len("FRAME\n") + 16*8*3/2
+	if strings.HasPrefix(buf.String(), header) == false {
+		t.Error("Unexpected header", buf.String()[:len(header)])
+	} else if buf.Len() != len(header)+5*frame {
+		t.Error("Unexpected length", buf.Len())
+	}
+	if err := fixture.WriteY4M(buf, fixture.Video{Width: 15}); err == nil {
+		t.Error("Expected error for odd width")
+	}
+}
+
+func Test_fixture_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fixture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "images", "bars.jpg")
+	if err := fixture.Create(path, fixture.Audio{}, fixture.Video{Width: 32, Height: 16}); err != nil {
+		t.Fatal(err)
+	} else if fh, err := os.Open(path); err != nil {
+		t.Fatal(err)
+	} else {
+		defer fh.Close()
+		if img, err := jpeg.Decode(fh); err != nil {
+			t.Error(err)
+		} else if r, g, b, _ := img.At(2, 8).RGBA(); r>>8 < 180 || g>>8 < 180 || b>>8 < 180 {
+			t.Error("Expected white bar", r>>8, g>>8, b>>8)
+		}
+	}
+	if err := fixture.Create(filepath.Join(dir, "unknown.xyz"), fixture.Audio{}, fixture.Video{}); err == nil {
+		t.Error("Expected error for unknown extension")
+	} else if _, err := os.Stat(filepath.Join(dir, "unknown.xyz")); os.IsNotExist(err) == false {
+		t.Error("Expected file to be removed")
+	}
+}
@@ -6,4 +6,5 @@ require (
 	github.com/djthorpe/gopi v1.0.85
 	github.com/djthorpe/gopi-rpc v1.0.15
 	github.com/mattn/go-sqlite3 v1.11.0
+	golang.org/x/image v0.0.0-20190523035834-f03afa92d3ff
 )
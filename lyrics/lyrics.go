@@ -0,0 +1,235 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package lyrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Lyrics are the lines of a song, which are timed when read from LRC
+// files or embedded LRC text
+type Lyrics struct {
+	Title  string
+	Artist string
+	Album  string
+
+	// Timed is true when every line has a time
+	Timed bool
+	Lines []Line
+}
+
+// Line is a line of lyrics, with the time it is sung when timed
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	LRC_EXT = ".lrc"
+)
+
+var (
+	reTime = regexp.MustCompile(`^\[(\d+):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	reTag  = regexp.MustCompile(`^\[([a-zA-Z]+):(.*)\]$`)
+	reWord = regexp.MustCompile(`<\d+:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// For returns the lyrics for a media file, from an LRC file with the
+// same name or from the embedded lyrics. It returns gopi.ErrNotFound if
+// the file has no lyrics.
+func For(file media.MediaFile) (*Lyrics, error) {
+	if filename := file.Filename(); filename != "" {
+		if lyrics, err := ReadFile(SidecarFor(filename)); err == nil {
+			return lyrics, nil
+		} else if err != gopi.ErrNotFound {
+			return nil, err
+		}
+	}
+	if text := file.StringForKey(media.METADATA_KEY_LYRICS); strings.TrimSpace(text) != "" {
+		return Parse(strings.NewReader(text))
+	}
+	return nil, gopi.ErrNotFound
+}
+
+// SidecarFor returns the LRC file for a media file
+func SidecarFor(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + LRC_EXT
+}
+
+// ReadFile reads lyrics from an LRC or plain text file
+func ReadFile(filename string) (*Lyrics, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return Parse(fh)
+	}
+}
+
+// Parse reads lyrics in LRC format, with timestamps such as
+// "[01:23.45]" and tags such as "[ar:Artist]", or as plain text. Lines
+// with more than one timestamp are repeated, word timestamps are
+// removed and the offset tag is applied. It returns gopi.ErrNotFound
+// if there are no lines.
+func Parse(r io.Reader) (*Lyrics, error) {
+	this := new(Lyrics)
+	offset := time.Duration(0)
+	timed, untimed := make([]Line, 0), make([]Line, 0)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if times, text := timesFor(line); len(times) > 0 {
+			text = strings.TrimSpace(reWord.ReplaceAllString(text, ""))
+			for _, t := range times {
+				timed = append(timed, Line{Time: t, Text: text})
+			}
+		} else if tag := reTag.FindStringSubmatch(line); tag != nil {
+			value := strings.TrimSpace(tag[2])
+			switch strings.ToLower(tag[1]) {
+			case "ti":
+				this.Title = value
+			case "ar":
+				this.Artist = value
+			case "al":
+				this.Album = value
+			case "offset":
+				// A positive offset shows lyrics earlier
+				if ms, err := strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 64); err == nil {
+					offset = time.Duration(ms) * time.Millisecond
+				}
+			}
+		} else if line != "" || len(untimed) > 0 {
+			untimed = append(untimed, Line{Text: line})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Timed lyrics are sorted by time, ignoring any untimed lines
+	if this.Timed = len(timed) > 0; this.Timed {
+		for i := range timed {
+			if timed[i].Time -= offset; timed[i].Time < 0 {
+				timed[i].Time = 0
+			}
+		}
+		sort.SliceStable(timed, func(i, j int) bool { return timed[i].Time < timed[j].Time })
+		this.Lines = timed
+	} else {
+		// Remove trailing blank lines
+		for len(untimed) > 0 && untimed[len(untimed)-1].Text == "" {
+			untimed = untimed[:len(untimed)-1]
+		}
+		this.Lines = untimed
+	}
+	if len(this.Lines) == 0 {
+		return nil, gopi.ErrNotFound
+	}
+
+	// Success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// LineAt returns the index of the line being sung at a position, or
+// false before the first line or when the lyrics are not timed
+func (this *Lyrics) LineAt(position time.Duration) (int, bool) {
+	if this.Timed == false {
+		return 0, false
+	}
+	i := sort.Search(len(this.Lines), func(i int) bool { return this.Lines[i].Time > position })
+	if i == 0 {
+		return 0, false
+	} else {
+		return i - 1, true
+	}
+}
+
+// Text returns the lyrics without timestamps
+func (this *Lyrics) Text() string {
+	lines := make([]string, len(this.Lines))
+	for i, line := range this.Lines {
+		lines[i] = line.Text
+	}
+	return strings.Join(lines, "\n")
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Lyrics) String() string {
+	parts := ""
+	if this.Title != "" {
+		parts += fmt.Sprintf(" title=%v", strconv.Quote(this.Title))
+	}
+	if this.Artist != "" {
+		parts += fmt.Sprintf(" artist=%v", strconv.Quote(this.Artist))
+	}
+	return fmt.Sprintf("<lyrics.Lyrics>{%v timed=%v lines=%v }", parts, this.Timed, len(this.Lines))
+}
+
+func (l Line) String() string {
+	return fmt.Sprintf("[%02d:%05.2f] %v", int(l.Time/time.Minute), (l.Time % time.Minute).Seconds(), l.Text)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// timesFor returns the timestamps at the start of a line, and the text
+// after them
+func timesFor(line string) ([]time.Duration, string) {
+	times := make([]time.Duration, 0, 1)
+	for {
+		match := reTime.FindStringSubmatch(line)
+		if match == nil {
+			return times, line
+		}
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		t := time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+		if fraction := match[3]; fraction != "" {
+			// Hundredths are most common, but tenths and thousandths are used
+			n, _ := strconv.Atoi(fraction)
+			for i := len(fraction); i < 3; i++ {
+				n *= 10
+			}
+			t += time.Duration(n) * time.Millisecond
+		}
+		times = append(times, t)
+		line = line[len(match[0]):]
+	}
+}
@@ -0,0 +1,115 @@
+package lyrics_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	lyrics "github.com/djthorpe/gopi-media/lyrics"
+	mock "github.com/djthorpe/gopi-media/mock"
+)
+
+const (
+	LRC = "\ufeff[ti:Song]\n[ar:Artist]\n[offset:+500]\n[length: 03:20]\n\n[00:12.00]First line\n[00:17.5][01:05.20]Chorus <00:18.00>line\n[00:21.100]\n[00:23.00]Second line\n"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST LYRICS
+
+func Test_lyrics_000(t *testing.T) {
+	t.Log("Test_lyrics_000")
+}
+
+func Test_lyrics_001(t *testing.T) {
+	l, err := lyrics.Parse(strings.NewReader(LRC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Title != "Song" || l.Artist != "Artist" || l.Timed == false || len(l.Lines) != 5 {
+		t.Fatal("Unexpected lyrics", l, l.Lines)
+	}
+	expected := []lyrics.Line{
+		{11500 * time.Millisecond, "First line"},
+		{17000 * time.Millisecond, "Chorus line"},
+		{20600 * time.Millisecond, ""},
+		{22500 * time.Millisecond, "Second line"},
+		{64700 * time.Millisecond, "Chorus line"},
+	}
+	for i, line := range expected {
+		if l.Lines[i] != line {
+			t.Error("Unexpected line", i, l.Lines[i])
+		}
+	}
+	for _, test := range []struct {
+		position time.Duration
+		line     int
+		ok       bool
+	}{
+		{0, 0, false},
+		{11500 * time.Millisecond, 0, true},
+		{19 * time.Second, 1, true},
+		{10 * time.Minute, 4, true},
+	} {
+		if line, ok := l.LineAt(test.position); line != test.line || ok != test.ok {
+			t.Error("Unexpected line at", test.position, line, ok)
+		}
+	}
+}
+
+func Test_lyrics_002(t *testing.T) {
+	l, err := lyrics.Parse(strings.NewReader("\nFirst line\n\nSecond line\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Timed || l.Text() != "First line\n\nSecond line" {
+		t.Error("Unexpected lyrics", l, strconv.Quote(l.Text()))
+	}
+	if _, ok := l.LineAt(time.Second); ok {
+		t.Error("Unexpected line for untimed lyrics")
+	}
+	if _, err := lyrics.Parse(strings.NewReader("[ar:Artist]\n\n")); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound", err)
+	}
+}
+
+func Test_lyrics_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lyrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Embedded lyrics are used without a sidecar
+	file := &mock.File{Path: filepath.Join(dir, "01 Song.mp3"), Metadata: map[media.MetadataKey]string{
+		media.METADATA_KEY_LYRICS: "Embedded line",
+	}}
+	if l, err := lyrics.For(file); err != nil {
+		t.Error(err)
+	} else if l.Text() != "Embedded line" {
+		t.Error("Unexpected lyrics", l)
+	}
+
+	// The sidecar takes precedence
+	if lyrics.SidecarFor(file.Path) != filepath.Join(dir, "01 Song.lrc") {
+		t.Error("Unexpected sidecar", lyrics.SidecarFor(file.Path))
+	} else if err := ioutil.WriteFile(lyrics.SidecarFor(file.Path), []byte(LRC), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if l, err := lyrics.For(file); err != nil {
+		t.Error(err)
+	} else if l.Timed == false || l.Title != "Song" {
+		t.Error("Unexpected lyrics", l)
+	}
+
+	file = &mock.File{Path: filepath.Join(dir, "02 Other.mp3")}
+	if _, err := lyrics.For(file); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound", err)
+	}
+}
@@ -8,6 +8,8 @@
 package media
 
 import (
+	"context"
+
 	// Frameworks
 	"github.com/djthorpe/gopi"
 )
@@ -17,6 +19,9 @@ import (
 
 type MetadataKey uint32
 type MediaType uint32
+type ArtworkType uint32
+type EpisodeOrder uint32
+type ContentAdvisory uint32
 
 type Media interface {
 	gopi.Driver
@@ -29,8 +34,22 @@ type Media interface {
 	TypeFor(filename string) MediaType
 }
 
+// ContextMedia is implemented by drivers which can give up opening
+// a media file when a context is cancelled
+type ContextMedia interface {
+	Media
+
+	// Open a media file, returning the context error if the context
+	// is cancelled or its deadline passes before the file is opened
+	OpenContext(context.Context, string) (MediaFile, error)
+}
+
 type MediaItem interface {
 
+	// Return a unique identifier for the media item, which does not
+	// change when the item is edited, renamed or moved
+	Id() string
+
 	// Return title for the media item, based on the metadata
 	// or the filename
 	Title() string
@@ -51,6 +70,9 @@ type MediaFile interface {
 
 	// Probe the file and enumerate the streams
 	Streams() []MediaStream
+
+	// Return all artwork embedded in the file
+	Artwork() []MediaArtwork
 }
 
 type MediaStream interface {
@@ -58,6 +80,15 @@ type MediaStream interface {
 	Type() MediaType
 }
 
+type MediaArtwork interface {
+	// Return the picture type for the artwork
+	Type() ArtworkType
+
+	// Return the mimetype and image data for the artwork
+	MimeType() string
+	Data() []byte
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // CONSTANTS
 
@@ -79,6 +110,55 @@ const (
 	MEDIA_TYPE_MOVIE      MediaType = (1 << iota)
 	MEDIA_TYPE_BOOKLET    MediaType = (1 << iota)
 	MEDIA_TYPE_RINGTONE   MediaType = (1 << iota)
+	MEDIA_TYPE_COMIC      MediaType = (1 << iota)
+	MEDIA_TYPE_EBOOK      MediaType = (1 << iota)
+)
+
+// Artwork picture types, which follow the ID3v2 APIC and FLAC
+// picture block types
+const (
+	ARTWORK_TYPE_OTHER ArtworkType = iota
+	ARTWORK_TYPE_FILE_ICON
+	ARTWORK_TYPE_OTHER_ICON
+	ARTWORK_TYPE_FRONT_COVER
+	ARTWORK_TYPE_BACK_COVER
+	ARTWORK_TYPE_LEAFLET
+	ARTWORK_TYPE_MEDIA
+	ARTWORK_TYPE_LEAD_ARTIST
+	ARTWORK_TYPE_ARTIST
+	ARTWORK_TYPE_CONDUCTOR
+	ARTWORK_TYPE_BAND
+	ARTWORK_TYPE_COMPOSER
+	ARTWORK_TYPE_LYRICIST
+	ARTWORK_TYPE_RECORDING_LOCATION
+	ARTWORK_TYPE_DURING_RECORDING
+	ARTWORK_TYPE_DURING_PERFORMANCE
+	ARTWORK_TYPE_SCREEN_CAPTURE
+	ARTWORK_TYPE_FISH
+	ARTWORK_TYPE_ILLUSTRATION
+	ARTWORK_TYPE_BAND_LOGO
+	ARTWORK_TYPE_PUBLISHER_LOGO
+	ARTWORK_TYPE_MAX = ARTWORK_TYPE_PUBLISHER_LOGO
+)
+
+// Episode orderings, where the numbering for the order is returned
+// with METADATA_KEY_SEASON and METADATA_KEY_EPISODE_SORT for aired
+// order, METADATA_KEY_DVD_SEASON and METADATA_KEY_DVD_EPISODE for DVD
+// order and METADATA_KEY_ABSOLUTE_EPISODE for absolute order
+const (
+	EPISODE_ORDER_AIRED EpisodeOrder = iota
+	EPISODE_ORDER_DVD
+	EPISODE_ORDER_ABSOLUTE
+	EPISODE_ORDER_MAX = EPISODE_ORDER_ABSOLUTE
+)
+
+// Content advisory values returned with METADATA_KEY_ADVISORY, which
+// are the same as the iTunes rating values
+const (
+	CONTENT_ADVISORY_NONE     ContentAdvisory = 0
+	CONTENT_ADVISORY_EXPLICIT ContentAdvisory = 1
+	CONTENT_ADVISORY_CLEAN    ContentAdvisory = 2
+	CONTENT_ADVISORY_MAX                      = CONTENT_ADVISORY_CLEAN
 )
 
 var (
@@ -108,12 +188,16 @@ var (
 	METADATA_KEY_MODIFIED  = METADATA_KEY('m', 't', 'i', 'm') // iso date/time
 	METADATA_KEY_YEAR      = METADATA_KEY('y', 't', 'i', 'm') // iso date/time
 	METADATA_KEY_PURCHASED = METADATA_KEY('p', 't', 'i', 'm') // iso date/time
+	METADATA_KEY_AIRED     = METADATA_KEY('a', 't', 'i', 'm') // iso date/time
 
 	// Type strings
 	METADATA_KEY_BRAND_MAJOR      = METADATA_KEY('m', 'a', 'b', 'r') // string
 	METADATA_KEY_BRAND_COMPATIBLE = METADATA_KEY('m', 'i', 'b', 'r') // string
 	METADATA_KEY_MEDIA_TYPE       = METADATA_KEY('t', 'y', 'p', 'e') // uint
 
+	// Video
+	METADATA_KEY_HDR = METADATA_KEY('h', 'b', 'o', 'l') // bool (a video stream has a HDR transfer function)
+
 	// Encoding strings
 	METADATA_KEY_ENCODER    = METADATA_KEY('c', 'o', 't', 'x') // string
 	METADATA_KEY_ENCODED_BY = METADATA_KEY('e', 'n', 't', 'x') // string
@@ -122,6 +206,18 @@ var (
 	METADATA_KEY_TRACK = METADATA_KEY('t', 'i', 'n', 't') // uint
 	METADATA_KEY_DISC  = METADATA_KEY('d', 'i', 'n', 't') // uint
 
+	// Gapless playback, in samples
+	METADATA_KEY_ENCODER_DELAY   = METADATA_KEY('e', 'd', 'i', 'n') // uint
+	METADATA_KEY_ENCODER_PADDING = METADATA_KEY('e', 'p', 'i', 'n') // uint
+	METADATA_KEY_SAMPLE_COUNT    = METADATA_KEY('s', 'c', 'i', 'n') // uint
+
+	// ReplayGain, in decibels for a loudness of -18 LUFS, and peaks
+	// where 1.0 is full scale
+	METADATA_KEY_TRACK_GAIN = METADATA_KEY('t', 'g', 'f', 'l') // float
+	METADATA_KEY_TRACK_PEAK = METADATA_KEY('t', 'k', 'f', 'l') // float
+	METADATA_KEY_ALBUM_GAIN = METADATA_KEY('a', 'g', 'f', 'l') // float
+	METADATA_KEY_ALBUM_PEAK = METADATA_KEY('a', 'k', 'f', 'l') // float
+
 	// Music Item specific
 	METADATA_KEY_ALBUM            = METADATA_KEY('a', 'l', 't', 'x') // string
 	METADATA_KEY_ALBUM_SORT       = METADATA_KEY('s', 'l', 't', 'x') // string
@@ -134,6 +230,10 @@ var (
 	METADATA_KEY_GENRE            = METADATA_KEY('g', 'e', 't', 'x') // string
 	METADATA_KEY_COMPILATION      = METADATA_KEY('c', 'b', 'o', 'l') // bool
 	METADATA_KEY_GAPLESS_PLAYBACK = METADATA_KEY('g', 'b', 'o', 'l') // bool
+	METADATA_KEY_RELEASE_TYPE     = METADATA_KEY('r', 'y', 't', 'x') // string (MusicBrainz types)
+	METADATA_KEY_RELEASE_STATUS   = METADATA_KEY('r', 's', 't', 'x') // string (MusicBrainz status)
+	METADATA_KEY_ADVISORY         = METADATA_KEY('a', 'v', 'i', 'n') // uint (ContentAdvisory)
+	METADATA_KEY_LYRICS           = METADATA_KEY('l', 'y', 't', 'x') // string (plain text or LRC)
 
 	// TV Item specific
 	METADATA_KEY_SHOW         = METADATA_KEY('s', 'h', 't', 'x')
@@ -141,9 +241,57 @@ var (
 	METADATA_KEY_EPISODE_ID   = METADATA_KEY('e', 'i', 'n', 't') // uint
 	METADATA_KEY_EPISODE_SORT = METADATA_KEY('f', 'i', 'n', 't') // uint
 
+	// TV episode orderings, where the order is set on the show
+	METADATA_KEY_EPISODE_ORDER    = METADATA_KEY('e', 'o', 'i', 'n') // uint (EpisodeOrder)
+	METADATA_KEY_DVD_SEASON       = METADATA_KEY('d', 's', 'i', 'n') // uint
+	METADATA_KEY_DVD_EPISODE      = METADATA_KEY('d', 'e', 'i', 'n') // uint
+	METADATA_KEY_ABSOLUTE_EPISODE = METADATA_KEY('a', 'b', 'i', 'n') // uint
+
+	// Book specific
+	METADATA_KEY_AUTHOR       = METADATA_KEY('a', 'u', 't', 'x') // string
+	METADATA_KEY_NARRATOR     = METADATA_KEY('n', 'r', 't', 'x') // string
+	METADATA_KEY_SERIES       = METADATA_KEY('s', 'e', 't', 'x') // string
+	METADATA_KEY_SERIES_INDEX = METADATA_KEY('s', 'e', 'f', 'l') // float
+	METADATA_KEY_PAGE_COUNT   = METADATA_KEY('p', 'c', 'i', 'n') // uint
+
 	// Broadcasting strings
 	METADATA_KEY_SERVICE_NAME     = METADATA_KEY('s', 'n', 't', 'x')
 	METADATA_KEY_SERVICE_PROVIDER = METADATA_KEY('s', 'p', 't', 'x')
+	METADATA_KEY_STREAM_URL       = METADATA_KEY('u', 'r', 't', 'x') // string
+
+	// Image specific
+	METADATA_KEY_CAMERA_MAKE   = METADATA_KEY('m', 'k', 't', 'x') // string
+	METADATA_KEY_CAMERA_MODEL  = METADATA_KEY('m', 'd', 't', 'x') // string
+	METADATA_KEY_ORIENTATION   = METADATA_KEY('o', 'i', 'n', 't') // uint (1 to 8)
+	METADATA_KEY_CAPTURED      = METADATA_KEY('s', 't', 'i', 'm') // iso date/time
+	METADATA_KEY_EXPOSURE_TIME = METADATA_KEY('x', 'p', 't', 'x') // string (seconds)
+	METADATA_KEY_APERTURE      = METADATA_KEY('a', 'p', 'f', 'l') // float (f-number)
+	METADATA_KEY_ISO           = METADATA_KEY('i', 's', 'i', 'n') // uint
+	METADATA_KEY_FOCAL_LENGTH  = METADATA_KEY('f', 'l', 'f', 'l') // float (mm)
+
+	// Location
+	METADATA_KEY_LATITUDE  = METADATA_KEY('l', 'a', 'f', 'l') // float (degrees)
+	METADATA_KEY_LONGITUDE = METADATA_KEY('l', 'o', 'f', 'l') // float (degrees)
+	METADATA_KEY_ALTITUDE  = METADATA_KEY('a', 'l', 'f', 'l') // float (metres)
+	METADATA_KEY_GEOHASH   = METADATA_KEY('g', 'h', 't', 'x') // string
+
+	// External identifiers
+	METADATA_KEY_IMDB_ID              = METADATA_KEY('i', 'm', 't', 'x') // string
+	METADATA_KEY_TMDB_ID              = METADATA_KEY('t', 'm', 't', 'x') // string
+	METADATA_KEY_TVDB_ID              = METADATA_KEY('t', 'v', 't', 'x') // string
+	METADATA_KEY_MUSICBRAINZ_ALBUM_ID = METADATA_KEY('m', 'a', 't', 'x') // string
+	METADATA_KEY_ANIDB_ID             = METADATA_KEY('a', 'd', 't', 'x') // string
+	METADATA_KEY_ANILIST_ID           = METADATA_KEY('a', 'n', 't', 'x') // string
+	METADATA_KEY_ISBN                 = METADATA_KEY('i', 'b', 't', 'x') // string
+
+	// User state
+	METADATA_KEY_RATING      = METADATA_KEY('r', 'i', 'n', 't') // uint (0 to 5)
+	METADATA_KEY_FAVOURITE   = METADATA_KEY('f', 'b', 'o', 'l') // bool
+	METADATA_KEY_PLAY_COUNT  = METADATA_KEY('p', 'i', 'n', 't') // uint
+	METADATA_KEY_LAST_PLAYED = METADATA_KEY('l', 't', 'i', 'm') // iso date/time
+	METADATA_KEY_POSITION    = METADATA_KEY('p', 'o', 's', 'd') // duration
+	METADATA_KEY_WATCHED     = METADATA_KEY('w', 'b', 'o', 'l') // bool
+	METADATA_KEY_WATCHED_AT  = METADATA_KEY('w', 't', 'i', 'm') // iso date/time
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -192,12 +340,16 @@ func (k MetadataKey) String() string {
 		return "METADATA_KEY_YEAR"
 	case METADATA_KEY_PURCHASED:
 		return "METADATA_KEY_PURCHASED"
+	case METADATA_KEY_AIRED:
+		return "METADATA_KEY_AIRED"
 	case METADATA_KEY_BRAND_MAJOR:
 		return "METADATA_KEY_BRAND_MAJOR"
 	case METADATA_KEY_BRAND_COMPATIBLE:
 		return "METADATA_KEY_BRAND_COMPATIBLE"
 	case METADATA_KEY_MEDIA_TYPE:
 		return "METADATA_KEY_MEDIA_TYPE"
+	case METADATA_KEY_HDR:
+		return "METADATA_KEY_HDR"
 	case METADATA_KEY_ENCODER:
 		return "METADATA_KEY_ENCODER"
 	case METADATA_KEY_ENCODED_BY:
@@ -206,6 +358,20 @@ func (k MetadataKey) String() string {
 		return "METADATA_KEY_TRACK"
 	case METADATA_KEY_DISC:
 		return "METADATA_KEY_DISC"
+	case METADATA_KEY_ENCODER_DELAY:
+		return "METADATA_KEY_ENCODER_DELAY"
+	case METADATA_KEY_ENCODER_PADDING:
+		return "METADATA_KEY_ENCODER_PADDING"
+	case METADATA_KEY_SAMPLE_COUNT:
+		return "METADATA_KEY_SAMPLE_COUNT"
+	case METADATA_KEY_TRACK_GAIN:
+		return "METADATA_KEY_TRACK_GAIN"
+	case METADATA_KEY_TRACK_PEAK:
+		return "METADATA_KEY_TRACK_PEAK"
+	case METADATA_KEY_ALBUM_GAIN:
+		return "METADATA_KEY_ALBUM_GAIN"
+	case METADATA_KEY_ALBUM_PEAK:
+		return "METADATA_KEY_ALBUM_PEAK"
 	case METADATA_KEY_ALBUM:
 		return "METADATA_KEY_ALBUM"
 	case METADATA_KEY_ALBUM_SORT:
@@ -228,6 +394,14 @@ func (k MetadataKey) String() string {
 		return "METADATA_KEY_COMPILATION"
 	case METADATA_KEY_GAPLESS_PLAYBACK:
 		return "METADATA_KEY_GAPLESS_PLAYBACK"
+	case METADATA_KEY_RELEASE_TYPE:
+		return "METADATA_KEY_RELEASE_TYPE"
+	case METADATA_KEY_RELEASE_STATUS:
+		return "METADATA_KEY_RELEASE_STATUS"
+	case METADATA_KEY_ADVISORY:
+		return "METADATA_KEY_ADVISORY"
+	case METADATA_KEY_LYRICS:
+		return "METADATA_KEY_LYRICS"
 	case METADATA_KEY_SHOW:
 		return "METADATA_KEY_SHOW"
 	case METADATA_KEY_SEASON:
@@ -236,13 +410,160 @@ func (k MetadataKey) String() string {
 		return "METADATA_KEY_EPISODE_ID"
 	case METADATA_KEY_EPISODE_SORT:
 		return "METADATA_KEY_EPISODE_SORT"
+	case METADATA_KEY_EPISODE_ORDER:
+		return "METADATA_KEY_EPISODE_ORDER"
+	case METADATA_KEY_DVD_SEASON:
+		return "METADATA_KEY_DVD_SEASON"
+	case METADATA_KEY_DVD_EPISODE:
+		return "METADATA_KEY_DVD_EPISODE"
+	case METADATA_KEY_ABSOLUTE_EPISODE:
+		return "METADATA_KEY_ABSOLUTE_EPISODE"
+	case METADATA_KEY_AUTHOR:
+		return "METADATA_KEY_AUTHOR"
+	case METADATA_KEY_NARRATOR:
+		return "METADATA_KEY_NARRATOR"
+	case METADATA_KEY_SERIES:
+		return "METADATA_KEY_SERIES"
+	case METADATA_KEY_SERIES_INDEX:
+		return "METADATA_KEY_SERIES_INDEX"
+	case METADATA_KEY_PAGE_COUNT:
+		return "METADATA_KEY_PAGE_COUNT"
 	case METADATA_KEY_SERVICE_NAME:
 		return "METADATA_KEY_SERVICE_NAME"
 	case METADATA_KEY_SERVICE_PROVIDER:
 		return "METADATA_KEY_SERVICE_PROVIDER"
+	case METADATA_KEY_STREAM_URL:
+		return "METADATA_KEY_STREAM_URL"
 	case METADATA_KEY_GROUPING:
 		return "METADATA_KEY_GROUPING"
+	case METADATA_KEY_CAMERA_MAKE:
+		return "METADATA_KEY_CAMERA_MAKE"
+	case METADATA_KEY_CAMERA_MODEL:
+		return "METADATA_KEY_CAMERA_MODEL"
+	case METADATA_KEY_ORIENTATION:
+		return "METADATA_KEY_ORIENTATION"
+	case METADATA_KEY_CAPTURED:
+		return "METADATA_KEY_CAPTURED"
+	case METADATA_KEY_EXPOSURE_TIME:
+		return "METADATA_KEY_EXPOSURE_TIME"
+	case METADATA_KEY_APERTURE:
+		return "METADATA_KEY_APERTURE"
+	case METADATA_KEY_ISO:
+		return "METADATA_KEY_ISO"
+	case METADATA_KEY_FOCAL_LENGTH:
+		return "METADATA_KEY_FOCAL_LENGTH"
+	case METADATA_KEY_LATITUDE:
+		return "METADATA_KEY_LATITUDE"
+	case METADATA_KEY_LONGITUDE:
+		return "METADATA_KEY_LONGITUDE"
+	case METADATA_KEY_ALTITUDE:
+		return "METADATA_KEY_ALTITUDE"
+	case METADATA_KEY_GEOHASH:
+		return "METADATA_KEY_GEOHASH"
+	case METADATA_KEY_IMDB_ID:
+		return "METADATA_KEY_IMDB_ID"
+	case METADATA_KEY_TMDB_ID:
+		return "METADATA_KEY_TMDB_ID"
+	case METADATA_KEY_TVDB_ID:
+		return "METADATA_KEY_TVDB_ID"
+	case METADATA_KEY_MUSICBRAINZ_ALBUM_ID:
+		return "METADATA_KEY_MUSICBRAINZ_ALBUM_ID"
+	case METADATA_KEY_ANIDB_ID:
+		return "METADATA_KEY_ANIDB_ID"
+	case METADATA_KEY_ANILIST_ID:
+		return "METADATA_KEY_ANILIST_ID"
+	case METADATA_KEY_ISBN:
+		return "METADATA_KEY_ISBN"
+	case METADATA_KEY_RATING:
+		return "METADATA_KEY_RATING"
+	case METADATA_KEY_FAVOURITE:
+		return "METADATA_KEY_FAVOURITE"
+	case METADATA_KEY_PLAY_COUNT:
+		return "METADATA_KEY_PLAY_COUNT"
+	case METADATA_KEY_LAST_PLAYED:
+		return "METADATA_KEY_LAST_PLAYED"
+	case METADATA_KEY_POSITION:
+		return "METADATA_KEY_POSITION"
+	case METADATA_KEY_WATCHED:
+		return "METADATA_KEY_WATCHED"
+	case METADATA_KEY_WATCHED_AT:
+		return "METADATA_KEY_WATCHED_AT"
 	default:
 		return "[?? Invalid MetadataKey]"
 	}
 }
+
+func (t ArtworkType) String() string {
+	switch t {
+	case ARTWORK_TYPE_OTHER:
+		return "ARTWORK_TYPE_OTHER"
+	case ARTWORK_TYPE_FILE_ICON:
+		return "ARTWORK_TYPE_FILE_ICON"
+	case ARTWORK_TYPE_OTHER_ICON:
+		return "ARTWORK_TYPE_OTHER_ICON"
+	case ARTWORK_TYPE_FRONT_COVER:
+		return "ARTWORK_TYPE_FRONT_COVER"
+	case ARTWORK_TYPE_BACK_COVER:
+		return "ARTWORK_TYPE_BACK_COVER"
+	case ARTWORK_TYPE_LEAFLET:
+		return "ARTWORK_TYPE_LEAFLET"
+	case ARTWORK_TYPE_MEDIA:
+		return "ARTWORK_TYPE_MEDIA"
+	case ARTWORK_TYPE_LEAD_ARTIST:
+		return "ARTWORK_TYPE_LEAD_ARTIST"
+	case ARTWORK_TYPE_ARTIST:
+		return "ARTWORK_TYPE_ARTIST"
+	case ARTWORK_TYPE_CONDUCTOR:
+		return "ARTWORK_TYPE_CONDUCTOR"
+	case ARTWORK_TYPE_BAND:
+		return "ARTWORK_TYPE_BAND"
+	case ARTWORK_TYPE_COMPOSER:
+		return "ARTWORK_TYPE_COMPOSER"
+	case ARTWORK_TYPE_LYRICIST:
+		return "ARTWORK_TYPE_LYRICIST"
+	case ARTWORK_TYPE_RECORDING_LOCATION:
+		return "ARTWORK_TYPE_RECORDING_LOCATION"
+	case ARTWORK_TYPE_DURING_RECORDING:
+		return "ARTWORK_TYPE_DURING_RECORDING"
+	case ARTWORK_TYPE_DURING_PERFORMANCE:
+		return "ARTWORK_TYPE_DURING_PERFORMANCE"
+	case ARTWORK_TYPE_SCREEN_CAPTURE:
+		return "ARTWORK_TYPE_SCREEN_CAPTURE"
+	case ARTWORK_TYPE_FISH:
+		return "ARTWORK_TYPE_FISH"
+	case ARTWORK_TYPE_ILLUSTRATION:
+		return "ARTWORK_TYPE_ILLUSTRATION"
+	case ARTWORK_TYPE_BAND_LOGO:
+		return "ARTWORK_TYPE_BAND_LOGO"
+	case ARTWORK_TYPE_PUBLISHER_LOGO:
+		return "ARTWORK_TYPE_PUBLISHER_LOGO"
+	default:
+		return "[?? Invalid ArtworkType value]"
+	}
+}
+
+func (o EpisodeOrder) String() string {
+	switch o {
+	case EPISODE_ORDER_AIRED:
+		return "EPISODE_ORDER_AIRED"
+	case EPISODE_ORDER_DVD:
+		return "EPISODE_ORDER_DVD"
+	case EPISODE_ORDER_ABSOLUTE:
+		return "EPISODE_ORDER_ABSOLUTE"
+	default:
+		return "[?? Invalid EpisodeOrder value]"
+	}
+}
+
+func (a ContentAdvisory) String() string {
+	switch a {
+	case CONTENT_ADVISORY_NONE:
+		return "CONTENT_ADVISORY_NONE"
+	case CONTENT_ADVISORY_EXPLICIT:
+		return "CONTENT_ADVISORY_EXPLICIT"
+	case CONTENT_ADVISORY_CLEAN:
+		return "CONTENT_ADVISORY_CLEAN"
+	default:
+		return "[?? Invalid ContentAdvisory value]"
+	}
+}
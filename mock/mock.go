@@ -0,0 +1,313 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package mock
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Config opens an in-memory implementation of media.Media, for testing
+// applications without media files on disk
+type Config struct {
+	// Files which can be opened
+	Files []*File
+
+	// OpenFunc is called to open files which have not been added,
+	// and returns gopi.ErrNotFound when nil
+	OpenFunc func(filename string) (media.MediaFile, error)
+}
+
+// Media is an in-memory implementation of media.Media. Files are
+// added by filename, and the behaviour can be changed with OpenFunc
+// and TypeFunc.
+type Media struct {
+	// OpenFunc is called to open files which have not been added
+	OpenFunc func(filename string) (media.MediaFile, error)
+
+	// TypeFunc is called to guess the type of files which have not
+	// been added, instead of using the file extension
+	TypeFunc func(filename string) media.MediaType
+
+	log    gopi.Logger
+	lock   sync.Mutex
+	files  map[string]*File
+	opened []media.MediaFile
+}
+
+// File is an in-memory implementation of media.MediaFile
+type File struct {
+	// Path is the filename, and Identifier is the stable identifier
+	// which is derived from the path when empty
+	Path       string
+	Identifier string
+
+	// MediaType is the type of the file and Metadata the keys and
+	// values, with METADATA_KEY_TITLE used for the title
+	MediaType media.MediaType
+	Metadata  map[media.MetadataKey]string
+
+	// StreamTypes is the type of each stream in the file and Images
+	// the artwork embedded in the file
+	StreamTypes []media.MediaType
+	Images      []*Artwork
+}
+
+// Artwork is an in-memory implementation of media.MediaArtwork
+type Artwork struct {
+	ArtworkType media.ArtworkType
+	Mimetype    string
+	Bytes       []byte
+}
+
+type stream struct {
+	t media.MediaType
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
+	logger.Debug("<mock.Open>{ files=%v }", len(config.Files))
+
+	this := new(Media)
+	this.log = logger
+	this.OpenFunc = config.OpenFunc
+	this.files = make(map[string]*File, len(config.Files))
+	this.opened = make([]media.MediaFile, 0)
+	for _, file := range config.Files {
+		this.Add(file)
+	}
+
+	// Success
+	return this, nil
+}
+
+func (this *Media) Close() error {
+	this.log.Debug("<mock.Close>{ }")
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Release resources
+	this.files = nil
+	this.opened = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Media) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return fmt.Sprintf("<mock>{ files=%v opened=%v }", len(this.files), len(this.opened))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FILES
+
+// Add makes a file available to Open, replacing any file with the
+// same path
+func (this *Media) Add(file *File) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.files[file.Path] = file
+}
+
+// Remove makes a file unavailable to Open
+func (this *Media) Remove(filename string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	delete(this.files, filename)
+}
+
+// Opened returns the files which have been opened and not destroyed
+func (this *Media) Opened() []media.MediaFile {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]media.MediaFile{}, this.opened...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA INTERFACE IMPLEMENTATION
+
+func (this *Media) Open(filename string) (media.MediaFile, error) {
+	this.log.Debug2("<mock.Open>{ filename=%v }", strconv.Quote(filename))
+
+	this.lock.Lock()
+	file, exists := this.files[filename]
+	open := this.OpenFunc
+	this.lock.Unlock()
+
+	var result media.MediaFile
+	if exists {
+		result = file
+	} else if open == nil {
+		return nil, gopi.ErrNotFound
+	} else if f, err := open(filename); err != nil {
+		return nil, err
+	} else {
+		result = f
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.opened = append(this.opened, result)
+	return result, nil
+}
+
+func (this *Media) Destroy(file media.MediaFile) error {
+	this.log.Debug2("<mock.Destroy>{ file=%v }", file)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for i, other := range this.opened {
+		if other == file {
+			this.opened = append(this.opened[:i], this.opened[i+1:]...)
+			return nil
+		}
+	}
+	return gopi.ErrNotFound
+}
+
+func (this *Media) TypeFor(filename string) media.MediaType {
+	this.lock.Lock()
+	file, exists := this.files[filename]
+	typefor := this.TypeFunc
+	this.lock.Unlock()
+
+	if exists {
+		return file.MediaType
+	} else if typefor != nil {
+		return typefor(filename)
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".mp4", ".m4v", ".mov", ".mkv":
+		return media.MEDIA_TYPE_MOVIE
+	case ".mp3", ".aac", ".m4a", ".flac":
+		return media.MEDIA_TYPE_MUSIC
+	case ".m4b":
+		return media.MEDIA_TYPE_AUDIOBOOK
+	case ".jpg", ".jpeg", ".png":
+		return media.MEDIA_TYPE_IMAGE
+	case ".cbz", ".cbr":
+		return media.MEDIA_TYPE_COMIC
+	case ".epub":
+		return media.MEDIA_TYPE_EBOOK
+	case ".pdf":
+		return media.MEDIA_TYPE_BOOKLET
+	default:
+		return media.MEDIA_TYPE_NONE
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIAFILE INTERFACE IMPLEMENTATION
+
+func (this *File) Id() string {
+	if this.Identifier != "" {
+		return this.Identifier
+	} else {
+		return "mock:" + this.Path
+	}
+}
+
+func (this *File) Filename() string {
+	return this.Path
+}
+
+func (this *File) Title() string {
+	if title, exists := this.Metadata[media.METADATA_KEY_TITLE]; exists && title != "" {
+		return title
+	} else {
+		return strings.TrimSuffix(filepath.Base(this.Path), filepath.Ext(this.Path))
+	}
+}
+
+func (this *File) Type() media.MediaType {
+	return this.MediaType
+}
+
+func (this *File) Keys() []media.MetadataKey {
+	keys := make([]media.MetadataKey, 0, len(this.Metadata))
+	for key := range this.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func (this *File) StringForKey(key media.MetadataKey) string {
+	return this.Metadata[key]
+}
+
+func (this *File) Streams() []media.MediaStream {
+	streams := make([]media.MediaStream, len(this.StreamTypes))
+	for i, t := range this.StreamTypes {
+		streams[i] = &stream{t}
+	}
+	return streams
+}
+
+func (this *File) Artwork() []media.MediaArtwork {
+	artwork := make([]media.MediaArtwork, len(this.Images))
+	for i, image := range this.Images {
+		artwork[i] = image
+	}
+	return artwork
+}
+
+func (this *File) String() string {
+	return fmt.Sprintf("<mock.File>{ path=%v type=%v keys=%v }", strconv.Quote(this.Path), this.MediaType, len(this.Metadata))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIAARTWORK INTERFACE IMPLEMENTATION
+
+func (this *Artwork) Type() media.ArtworkType {
+	return this.ArtworkType
+}
+
+func (this *Artwork) MimeType() string {
+	return this.Mimetype
+}
+
+func (this *Artwork) Data() []byte {
+	return this.Bytes
+}
+
+func (this *Artwork) String() string {
+	return fmt.Sprintf("<mock.Artwork>{ type=%v mimetype=%v size=%v }", this.ArtworkType, this.Mimetype, len(this.Bytes))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIASTREAM INTERFACE IMPLEMENTATION
+
+func (this *stream) Type() media.MediaType {
+	return this.t
+}
+
+func (this *stream) String() string {
+	return fmt.Sprintf("<mock.Stream>{ type=%v }", this.t)
+}
@@ -0,0 +1,97 @@
+package mock_test
+
+import (
+	"errors"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	logger "github.com/djthorpe/gopi/sys/logger"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST MOCK
+
+func Test_mock_000(t *testing.T) {
+	t.Log("Test_mock_000")
+}
+
+func Test_mock_001(t *testing.T) {
+	file := &mock.File{
+		Path:        "/music/track.mp3",
+		MediaType:   media.MEDIA_TYPE_MUSIC,
+		Metadata:    map[media.MetadataKey]string{media.METADATA_KEY_TITLE: "Track", media.METADATA_KEY_ARTIST: "Artist"},
+		StreamTypes: []media.MediaType{media.MEDIA_TYPE_AUDIO},
+		Images:      []*mock.Artwork{{ArtworkType: media.ARTWORK_TYPE_FRONT_COVER, Mimetype: "image/jpeg", Bytes: []byte{0xFF, 0xD8}}},
+	}
+	driver := open(t, mock.Config{Files: []*mock.File{file}})
+	defer driver.Close()
+
+	if f, err := driver.Open("/music/track.mp3"); err != nil {
+		t.Fatal(err)
+	} else if f.Title() != "Track" || f.Id() != "mock:/music/track.mp3" || len(f.Keys()) != 2 {
+		t.Error("Unexpected file", f)
+	} else if streams := f.Streams(); len(streams) != 1 || streams[0].Type() != media.MEDIA_TYPE_AUDIO {
+		t.Error("Unexpected streams", streams)
+	} else if artwork := f.Artwork(); len(artwork) != 1 || artwork[0].MimeType() != "image/jpeg" {
+		t.Error("Unexpected artwork", artwork)
+	} else if err := driver.(*mock.Media).Destroy(f); err != nil {
+		t.Error(err)
+	} else if err := driver.(*mock.Media).Destroy(f); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if _, err := driver.Open("/music/missing.mp3"); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	if mediatype := driver.TypeFor("/music/track.mp3"); mediatype != media.MEDIA_TYPE_MUSIC {
+		t.Error("Unexpected type", mediatype)
+	} else if mediatype := driver.TypeFor("/movies/film.MKV"); mediatype != media.MEDIA_TYPE_MOVIE {
+		t.Error("Unexpected type", mediatype)
+	}
+}
+
+func Test_mock_002(t *testing.T) {
+	failure := errors.New("corrupt file")
+	driver := open(t, mock.Config{
+		OpenFunc: func(filename string) (media.MediaFile, error) {
+			if filename == "/bad.mp4" {
+				return nil, failure
+			}
+			return &mock.File{Path: filename, MediaType: media.MEDIA_TYPE_MOVIE}, nil
+		},
+	})
+	defer driver.Close()
+
+	if _, err := driver.Open("/bad.mp4"); err != failure {
+		t.Error("Expected failure, got", err)
+	} else if f, err := driver.Open("/good.mp4"); err != nil {
+		t.Error(err)
+	} else if f.Title() != "good" || f.Type() != media.MEDIA_TYPE_MOVIE {
+		t.Error("Unexpected file", f)
+	} else if opened := driver.(*mock.Media).Opened(); len(opened) != 1 {
+		t.Error("Unexpected opened files", opened)
+	}
+
+	driver.(*mock.Media).TypeFunc = func(string) media.MediaType { return media.MEDIA_TYPE_AUDIOBOOK }
+	if mediatype := driver.TypeFor("/book.m4b"); mediatype != media.MEDIA_TYPE_AUDIOBOOK {
+		t.Error("Unexpected type", mediatype)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func open(t *testing.T, config mock.Config) media.Media {
+	t.Helper()
+	log, err := gopi.Open(logger.Config{Level: logger.LOG_WARN}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	driver, err := gopi.Open(config, log.(gopi.Logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return driver.(media.Media)
+}
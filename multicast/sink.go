@@ -0,0 +1,270 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package multicast
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Sink sends a transport stream as UDP or RTP datagrams, pacing the
+// datagrams using the programme clock references in the stream so a
+// file is sent at the rate it would be played. Any writer can be used
+// for the datagrams, but it is usually a UDP connection from Dial.
+type Sink struct {
+	lock     sync.Mutex
+	w        io.Writer
+	rtp      bool
+	pending  []byte
+	datagram []byte
+	seq      uint16
+	ssrc     uint32
+	pcr      int64
+	pcrpid   int
+	start    time.Time
+	last     int64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Size of a transport stream packet, and the number of packets
+	// in each datagram, which fits in an ethernet frame
+	TS_PACKET_SIZE       = 188
+	TS_PACKETS_PER_DGRAM = 7
+
+	// RTP payload type for MPEG-2 transport streams and the RTP
+	// timestamp clock rate
+	RTP_PAYLOAD_MP2T = 33
+	RTP_CLOCK_RATE   = 90000
+
+	// Size of the RTP header
+	RTP_HEADER_SIZE = 12
+)
+
+const (
+	ts_sync        = 0x47
+	pcr_clock_rate = 27000000
+	pcr_wrap       = (1 << 33) * 300
+
+	// Clock references which jump by more than this are treated as a
+	// discontinuity and pacing restarts
+	pcr_max_jump = 2 * pcr_clock_rate
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// Dial returns a sink which sends datagrams to a unicast or multicast
+// address in the form host:port. Multicast datagrams use the system
+// default time-to-live, which is usually one so datagrams stay on the
+// local network.
+func Dial(address string, rtp bool) (*Sink, error) {
+	if addr, err := net.ResolveUDPAddr("udp", address); err != nil {
+		return nil, err
+	} else if conn, err := net.DialUDP("udp", nil, addr); err != nil {
+		return nil, err
+	} else {
+		return NewSink(conn, rtp), nil
+	}
+}
+
+// NewSink returns a sink which writes each datagram with a single call
+// to w, with an RTP header when rtp is true
+func NewSink(w io.Writer, rtp bool) *Sink {
+	this := &Sink{
+		w:        w,
+		rtp:      rtp,
+		pending:  make([]byte, 0, TS_PACKET_SIZE),
+		datagram: make([]byte, 0, RTP_HEADER_SIZE+TS_PACKET_SIZE*TS_PACKETS_PER_DGRAM),
+		pcr:      -1,
+		pcrpid:   -1,
+	}
+	if rtp {
+		this.seq = uint16(rand.Uint32())
+		this.ssrc = rand.Uint32()
+	}
+	return this
+}
+
+// Close sends any remaining packets and closes the writer if it is
+// an io.Closer
+func (this *Sink) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	err := this.flush()
+	if closer, ok := this.w.(io.Closer); ok {
+		if cerr := closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITE
+
+// Write accepts transport stream data in any size of chunk. Data
+// before a sync byte is discarded, and the call blocks when data is
+// written faster than the stream is played.
+func (this *Sink) Write(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	n := len(data)
+	for len(data) > 0 {
+		// Find the start of a packet
+		if len(this.pending) == 0 {
+			i := 0
+			for i < len(data) && data[i] != ts_sync {
+				i++
+			}
+			if data = data[i:]; len(data) == 0 {
+				break
+			}
+		}
+
+		// Fill the packet
+		m := TS_PACKET_SIZE - len(this.pending)
+		if m > len(data) {
+			m = len(data)
+		}
+		this.pending = append(this.pending, data[:m]...)
+		data = data[m:]
+		if len(this.pending) < TS_PACKET_SIZE {
+			break
+		}
+		if err := this.packet(this.pending); err != nil {
+			return n - len(data), err
+		}
+		this.pending = this.pending[:0]
+	}
+
+	// Success
+	return n, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Sink) String() string {
+	if this.rtp {
+		return fmt.Sprintf("<multicast.Sink>{ rtp=true ssrc=0x%08X }", this.ssrc)
+	} else {
+		return "<multicast.Sink>{ rtp=false }"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// packet adds a packet to the datagram, waiting until the time for
+// the clock reference in the packet before sending the datagram
+func (this *Sink) packet(packet []byte) error {
+	if len(this.datagram) == 0 && this.rtp {
+		this.datagram = this.datagram[:RTP_HEADER_SIZE]
+	}
+	this.datagram = append(this.datagram, packet...)
+	if pcr, exists := pcrFor(packet); exists {
+		this.wait(int(binary.BigEndian.Uint16(packet[1:])&0x1FFF), pcr)
+	}
+	if this.packets() == TS_PACKETS_PER_DGRAM {
+		return this.flush()
+	}
+	return nil
+}
+
+// packets returns the number of packets in the datagram
+func (this *Sink) packets() int {
+	if this.rtp && len(this.datagram) > 0 {
+		return (len(this.datagram) - RTP_HEADER_SIZE) / TS_PACKET_SIZE
+	} else {
+		return len(this.datagram) / TS_PACKET_SIZE
+	}
+}
+
+// wait sleeps until the wall clock time for a clock reference. Only
+// the clock references on the first PID which carries them are used.
+// The first clock reference, and any discontinuity, restarts the clock.
+func (this *Sink) wait(pid int, pcr int64) {
+	if this.pcrpid < 0 {
+		this.pcrpid = pid
+	} else if pid != this.pcrpid {
+		return
+	}
+	now := time.Now()
+	delta := pcr - this.pcr
+	if delta < 0 {
+		delta += pcr_wrap
+	}
+	if this.pcr < 0 || delta > pcr_max_jump {
+		this.pcr, this.start, this.last = pcr, now, 0
+		return
+	}
+	this.last += delta
+	this.pcr = pcr
+	target := this.start.Add(time.Duration(this.last/(pcr_clock_rate/1000000)) * time.Microsecond)
+	if d := target.Sub(now); d > 0 {
+		time.Sleep(d)
+	} else if d < -time.Second {
+		// Fallen behind, so restart the clock rather than sending a burst
+		this.start, this.last = now, 0
+	}
+}
+
+// flush sends the datagram
+func (this *Sink) flush() error {
+	if this.packets() == 0 {
+		return nil
+	}
+	if this.rtp {
+		header := this.datagram[:RTP_HEADER_SIZE]
+		header[0] = 0x80
+		header[1] = RTP_PAYLOAD_MP2T
+		binary.BigEndian.PutUint16(header[2:], this.seq)
+		binary.BigEndian.PutUint32(header[4:], this.timestamp())
+		binary.BigEndian.PutUint32(header[8:], this.ssrc)
+		this.seq++
+	}
+	_, err := this.w.Write(this.datagram)
+	this.datagram = this.datagram[:0]
+	return err
+}
+
+// timestamp returns the RTP timestamp, which is derived from the most
+// recent clock reference or the wall clock when there is none
+func (this *Sink) timestamp() uint32 {
+	if this.pcr >= 0 {
+		return uint32(this.pcr / (pcr_clock_rate / RTP_CLOCK_RATE))
+	} else {
+		return uint32(time.Now().UnixNano() / int64(time.Second/RTP_CLOCK_RATE))
+	}
+}
+
+// pcrFor returns the programme clock reference in a packet in 27MHz
+// units
+func pcrFor(packet []byte) (int64, bool) {
+	if packet[3]&0x20 == 0 || packet[4] < 7 || packet[5]&0x10 == 0 {
+		return 0, false
+	}
+	base := int64(packet[6])<<25 | int64(packet[7])<<17 | int64(packet[8])<<9 | int64(packet[9])<<1 | int64(packet[10])>>7
+	ext := int64(packet[10]&0x01)<<8 | int64(packet[11])
+	return base*300 + ext, true
+}
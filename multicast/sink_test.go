@@ -0,0 +1,142 @@
+package multicast_test
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	// Frameworks
+	multicast "github.com/djthorpe/gopi-media/multicast"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DATAGRAMS
+
+type datagrams struct {
+	data [][]byte
+	at   []time.Time
+}
+
+func (this *datagrams) Write(data []byte) (int, error) {
+	this.data = append(this.data, append([]byte{}, data...))
+	this.at = append(this.at, time.Now())
+	return len(data), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST SINK
+
+func Test_sink_000(t *testing.T) {
+	t.Log("Test_sink_000")
+}
+
+func Test_sink_001(t *testing.T) {
+	// Ten packets with junk before the first sync byte, written in
+	// uneven chunks
+	stream := []byte{0x00, 0x01}
+	for i := 0; i < 10; i++ {
+		stream = append(stream, packet(0x100, -1)...)
+	}
+	w := new(datagrams)
+	sink := multicast.NewSink(w, false)
+	for len(stream) > 0 {
+		n := 100
+		if n > len(stream) {
+			n = len(stream)
+		}
+		if _, err := sink.Write(stream[:n]); err != nil {
+			t.Fatal(err)
+		}
+		stream = stream[n:]
+	}
+	if len(w.data) != 1 || len(w.data[0]) != 7*multicast.TS_PACKET_SIZE {
+		t.Error("Unexpected datagrams", len(w.data))
+	}
+	if err := sink.Close(); err != nil {
+		t.Error(err)
+	} else if len(w.data) != 2 || len(w.data[1]) != 3*multicast.TS_PACKET_SIZE || w.data[1][0] != 0x47 {
+		t.Error("Unexpected datagrams", len(w.data))
+	}
+}
+
+func Test_sink_002(t *testing.T) {
+	// RTP datagrams paced by clock references 100ms apart
+	w := new(datagrams)
+	sink := multicast.NewSink(w, true)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		sink.Write(packet(0x100, int64(i)*2700000))
+		for j := 0; j < 6; j++ {
+			sink.Write(packet(0x101, -1))
+		}
+	}
+	if len(w.data) != 3 {
+		t.Fatal("Unexpected datagrams", len(w.data))
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Error("Expected pacing, elapsed", elapsed)
+	}
+	for i, data := range w.data {
+		if len(data) != multicast.RTP_HEADER_SIZE+7*multicast.TS_PACKET_SIZE || data[0] != 0x80 || data[1] != multicast.RTP_PAYLOAD_MP2T {
+			t.Error("Unexpected RTP header", data[:multicast.RTP_HEADER_SIZE])
+		}
+		if i > 0 {
+			if seq := binary.BigEndian.Uint16(data[2:]) - binary.BigEndian.Uint16(w.data[i-1][2:]); seq != 1 {
+				t.Error("Unexpected sequence", seq)
+			}
+			if ts := binary.BigEndian.Uint32(data[4:]) - binary.BigEndian.Uint32(w.data[i-1][4:]); ts != 9000 {
+				t.Error("Unexpected timestamp", ts)
+			}
+		}
+	}
+}
+
+func Test_sink_003(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	sink, err := multicast.Dial(conn.LocalAddr().String(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Write(packet(0x100, -1))
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if n, _, err := conn.ReadFromUDP(buf); err != nil {
+		t.Error(err)
+	} else if n != multicast.TS_PACKET_SIZE {
+		t.Error("Unexpected datagram size", n)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// packet returns a transport stream packet, with a clock reference when
+// pcr is not negative
+func packet(pid uint16, pcr int64) []byte {
+	data := make([]byte, multicast.TS_PACKET_SIZE)
+	data[0] = 0x47
+	binary.BigEndian.PutUint16(data[1:], pid)
+	if pcr < 0 {
+		data[3] = 0x10
+		return data
+	}
+	base, ext := pcr/300, pcr%300
+	data[3] = 0x30
+	data[4] = 7
+	data[5] = 0x10
+	data[6] = byte(base >> 25)
+	data[7] = byte(base >> 17)
+	data[8] = byte(base >> 9)
+	data[9] = byte(base >> 1)
+	data[10] = byte(base<<7) | 0x7E | byte(ext>>8)
+	data[11] = byte(ext)
+	return data
+}
@@ -0,0 +1,225 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package multiroom
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Controller sends audio to receivers in other rooms, with the time
+// each chunk should be played on the controller clock. Receivers are
+// added when they send a clock request, and removed when they stop.
+// Writes are paced so audio is sent the latency ahead of when it is
+// played.
+type Controller struct {
+	// Latency is how far ahead of the controller clock audio is
+	// scheduled, which allows for network delays and buffering on the
+	// receivers
+	Latency time.Duration
+
+	lock      sync.Mutex
+	conn      *net.UDPConn
+	peers     sync.Mutex
+	receivers map[string]*peer
+	format    media.AudioFormat
+	pending   []byte
+	seq       uint32
+	base      int64
+	sent      time.Duration
+	done      chan struct{}
+}
+
+type peer struct {
+	addr *net.UDPAddr
+	seen time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_LATENCY = 300 * time.Millisecond
+
+	// Receivers are removed when there has been no clock request for
+	// this long
+	RECEIVER_TIMEOUT = 5 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// Listen returns a controller which receives clock requests on an
+// address in the form host:port
+func Listen(address string) (*Controller, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	this := &Controller{
+		Latency:   DEFAULT_LATENCY,
+		conn:      conn,
+		receivers: make(map[string]*peer),
+		done:      make(chan struct{}),
+	}
+	go this.serve()
+	return this, nil
+}
+
+// Close stops sending audio and answering clock requests
+func (this *Controller) Close() error {
+	err := this.conn.Close()
+	<-this.done
+	return err
+}
+
+// Addr returns the address the controller is listening on
+func (this *Controller) Addr() net.Addr {
+	return this.conn.LocalAddr()
+}
+
+// Receivers returns the addresses of the receivers, sorted
+func (this *Controller) Receivers() []string {
+	this.peers.Lock()
+	defer this.peers.Unlock()
+	receivers := make([]string, 0, len(this.receivers))
+	for key := range this.receivers {
+		receivers = append(receivers, key)
+	}
+	sort.Strings(receivers)
+	return receivers
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIO
+
+// SetFormat sets the format of the audio which is written, and starts
+// a new timeline
+func (this *Controller) SetFormat(format media.AudioFormat) error {
+	if format.BytesPerFrame() == 0 || format.Rate == 0 || format.Channels > 0xFF {
+		return gopi.ErrBadParameter
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.format = format
+	this.pending = this.pending[:0]
+	this.base = 0
+	return nil
+}
+
+// Write sends interleaved PCM audio in the format set with SetFormat,
+// blocking to keep to the latency ahead of playback. When writes stop
+// for longer than half the latency, the audio after the gap is
+// scheduled on a new timeline.
+func (this *Controller) Write(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	size := int(this.format.BytesPerFrame())
+	if size == 0 {
+		return 0, gopi.ErrOutOfOrder
+	}
+
+	// Send whole frames and keep the remainder
+	n := len(data)
+	this.pending = append(this.pending, data...)
+	chunk := MAX_PAYLOAD - MAX_PAYLOAD%size
+	for len(this.pending) >= size {
+		m := len(this.pending) - len(this.pending)%size
+		if m > chunk {
+			m = chunk
+		}
+		this.send(this.pending[:m])
+		this.pending = this.pending[m:]
+	}
+	this.pending = append([]byte{}, this.pending...)
+
+	// Success
+	return n, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Controller) String() string {
+	return fmt.Sprintf("<multiroom.Controller>{ addr=%v latency=%v receivers=%v }", this.conn.LocalAddr(), this.Latency, len(this.Receivers()))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// serve answers clock requests until the connection is closed
+func (this *Controller) serve() {
+	defer close(this.done)
+	buf := make([]byte, msgClockSize)
+	for {
+		n, addr, err := this.conn.ReadFromUDP(buf)
+		t1 := now()
+		if err != nil {
+			return
+		}
+		msg, err := decode(buf[:n])
+		if err != nil || msg.Type != msgClockRequest {
+			continue
+		}
+		this.peers.Lock()
+		this.receivers[addr.String()] = &peer{addr, time.Now()}
+		this.peers.Unlock()
+		this.conn.WriteToUDP(encodeClock(msgClockResponse, msg.T0, t1, now()), addr)
+	}
+}
+
+// send schedules a chunk of audio and sends it to the receivers
+func (this *Controller) send(pcm []byte) {
+	t := now()
+	latency := this.Latency
+	if latency <= 0 {
+		latency = DEFAULT_LATENCY
+	}
+	play := this.base + int64(this.sent)
+	if this.base == 0 || play < t+int64(latency/2) {
+		this.base, this.sent = t+int64(latency), 0
+		play = this.base
+	}
+
+	// Wait until the chunk is the latency ahead of playback
+	if d := time.Duration(play - int64(latency) - t); d > 0 {
+		time.Sleep(d)
+	}
+
+	datagram := encodeAudio(this.seq, play, this.format, pcm)
+	this.seq++
+	this.sent += duration(this.format, len(pcm))
+
+	// A receiver which cannot be reached does not stop the others
+	this.peers.Lock()
+	defer this.peers.Unlock()
+	for key, receiver := range this.receivers {
+		if time.Since(receiver.seen) > RECEIVER_TIMEOUT {
+			delete(this.receivers, key)
+		} else {
+			this.conn.WriteToUDP(datagram, receiver.addr)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package multiroom_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	multiroom "github.com/djthorpe/gopi-media/multiroom"
+	event "github.com/djthorpe/gopi/util/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// SINK
+
+// sink records the time of each write
+type sink struct {
+	event.Publisher
+
+	lock   sync.Mutex
+	format media.AudioFormat
+	bytes  int
+	first  time.Time
+}
+
+func (this *sink) Close() error { return nil }
+func (this *sink) Drain() error { return nil }
+func (this *sink) Stop() error  { return nil }
+
+func (this *sink) SetFormat(format media.AudioFormat) (media.AudioFormat, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.format = format
+	return format, nil
+}
+
+func (this *sink) Format() media.AudioFormat {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.format
+}
+
+func (this *sink) Write(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.first.IsZero() {
+		this.first = time.Now()
+	}
+	this.bytes += len(data)
+	return len(data), nil
+}
+
+func (this *sink) result() (int, time.Time) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.bytes, this.first
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CLOCK
+
+func Test_multiroom_000(t *testing.T) {
+	t.Log("Test_multiroom_000")
+}
+
+func Test_multiroom_001(t *testing.T) {
+	// The controller clock is 10s ahead, and the exchange with the
+	// smallest round trip gives the best estimate
+	clock := new(multiroom.Clock)
+	if _, _, ok := clock.Offset(); ok {
+		t.Error("Expected no estimate")
+	}
+	ahead := int64(10 * time.Second)
+	ms := int64(time.Millisecond)
+	clock.Add(0, ahead+20*ms, ahead+21*ms, 25*ms)
+	clock.Add(100*ms, ahead+101*ms, ahead+101*ms, 102*ms)
+	clock.Add(200*ms, ahead+230*ms, ahead+231*ms, 240*ms)
+	if offset, delay, ok := clock.Offset(); ok == false {
+		t.Error("Expected an estimate")
+	} else if delay != 2*time.Millisecond {
+		t.Error("Unexpected delay", delay)
+	} else if offset != 10*time.Second {
+		t.Error("Unexpected offset", offset)
+	}
+	if clock.Ready() {
+		t.Error("Expected estimate not to be ready")
+	}
+}
+
+func Test_multiroom_002(t *testing.T) {
+	format := media.AudioFormat{Format: media.SAMPLE_FORMAT_S16, Rate: 8000, Channels: 2}
+	controller, err := multiroom.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controller.Close()
+	controller.Latency = 100 * time.Millisecond
+	if err := controller.SetFormat(format); err != nil {
+		t.Fatal(err)
+	}
+
+	// Start two receivers and wait until the controller knows them
+	stop := make(chan struct{})
+	sinks := []*sink{new(sink), new(sink)}
+	var wg sync.WaitGroup
+	for _, s := range sinks {
+		wg.Add(1)
+		go func(s *sink) {
+			defer wg.Done()
+			receiver := &multiroom.Receiver{Sink: s}
+			if err := receiver.Run(controller.Addr().String(), stop); err != nil {
+				t.Error(err)
+			}
+		}(s)
+	}
+	for i := 0; i < 50 && len(controller.Receivers()) < 2; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(controller.Receivers()) != 2 {
+		t.Fatal("Unexpected receivers", controller.Receivers())
+	}
+
+	// Wait for the clock estimates, then send 200ms of audio
+	time.Sleep(600 * time.Millisecond)
+	start := time.Now()
+	frame := make([]byte, format.BytesPerFrame()*80)
+	for i := 0; i < 20; i++ {
+		if _, err := controller.Write(frame); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(300 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+
+	// Both receivers start playing at the same time, the latency after
+	// the first write
+	n0, t0 := sinks[0].result()
+	n1, t1 := sinks[1].result()
+	if n0 != 20*len(frame) || n1 != 20*len(frame) {
+		t.Error("Unexpected bytes received", n0, n1)
+	}
+	if d := t0.Sub(t1); d > 10*time.Millisecond || d < -10*time.Millisecond {
+		t.Error("Receivers out of sync", d)
+	}
+	if d := t0.Sub(start); d < 80*time.Millisecond || d > 150*time.Millisecond {
+		t.Error("Unexpected latency", d)
+	}
+}
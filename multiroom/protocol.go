@@ -0,0 +1,217 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package multiroom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Clock estimates the offset between the local clock and the clock on
+// the controller, from the times recorded by clock request and response
+// messages. The sample with the smallest round trip is used, since it
+// has the least error from network delays. The zero value is safe for
+// concurrent use.
+type Clock struct {
+	lock    sync.Mutex
+	samples []clockSample
+	next    int
+}
+
+type clockSample struct {
+	offset time.Duration
+	delay  time.Duration
+}
+
+// message is a decoded datagram
+type message struct {
+	Type     uint8
+	T0       int64
+	T1       int64
+	T2       int64
+	Seq      uint32
+	Play     int64
+	Format   media.AudioFormat
+	Data     []byte
+	Received int64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Number of clock samples used for the estimate
+	CLOCK_SAMPLES = 8
+
+	// Largest audio payload in a datagram, which fits in an ethernet
+	// frame with the headers
+	MAX_PAYLOAD = 1400
+)
+
+const (
+	msgClockRequest  = 1
+	msgClockResponse = 2
+	msgAudio         = 3
+
+	// Datagrams start with a magic number and the message type
+	msgMagic       = 0x4D52
+	msgHeaderSize  = 3
+	msgClockSize   = msgHeaderSize + 3*8
+	msgAudioHeader = msgHeaderSize + 4 + 8 + 4 + 1 + 1
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CLOCK
+
+// Add records a clock exchange, where t0 is the local time the request
+// was sent, t1 and t2 are the controller times it was received and the
+// response was sent, and t3 is the local time the response was received
+func (this *Clock) Add(t0, t1, t2, t3 int64) {
+	sample := clockSample{
+		offset: time.Duration(((t1 - t0) + (t2 - t3)) / 2),
+		delay:  time.Duration((t3 - t0) - (t2 - t1)),
+	}
+	if sample.delay < 0 {
+		return
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if len(this.samples) < CLOCK_SAMPLES {
+		this.samples = append(this.samples, sample)
+	} else {
+		this.samples[this.next] = sample
+		this.next = (this.next + 1) % CLOCK_SAMPLES
+	}
+}
+
+// Offset returns the time to add to the local clock to get the time on
+// the controller and the round trip time for the estimate, or false if
+// there have been no clock exchanges
+func (this *Clock) Offset() (time.Duration, time.Duration, bool) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if len(this.samples) == 0 {
+		return 0, 0, false
+	}
+	best := this.samples[0]
+	for _, sample := range this.samples[1:] {
+		if sample.delay < best.delay {
+			best = sample
+		}
+	}
+	return best.offset, best.delay, true
+}
+
+// Ready returns true when there are enough samples for a good estimate
+func (this *Clock) Ready() bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return len(this.samples) >= CLOCK_SAMPLES/2
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Clock) String() string {
+	if offset, delay, ok := this.Offset(); ok {
+		return fmt.Sprintf("<multiroom.Clock>{ offset=%v delay=%v }", offset, delay)
+	} else {
+		return "<multiroom.Clock>{ }"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func now() int64 {
+	return time.Now().UnixNano()
+}
+
+func header(t uint8, size int) []byte {
+	data := make([]byte, msgHeaderSize, size)
+	binary.BigEndian.PutUint16(data, msgMagic)
+	data[2] = t
+	return data
+}
+
+func encodeClock(t uint8, t0, t1, t2 int64) []byte {
+	data := header(t, msgClockSize)
+	data = appendUint64(data, uint64(t0))
+	data = appendUint64(data, uint64(t1))
+	return appendUint64(data, uint64(t2))
+}
+
+func encodeAudio(seq uint32, play int64, format media.AudioFormat, pcm []byte) []byte {
+	data := header(msgAudio, msgAudioHeader+len(pcm))
+	data = appendUint32(data, seq)
+	data = appendUint64(data, uint64(play))
+	data = appendUint32(data, uint32(format.Rate))
+	data = append(data, uint8(format.Channels), uint8(format.Format))
+	return append(data, pcm...)
+}
+
+func decode(data []byte) (*message, error) {
+	if len(data) < msgHeaderSize || binary.BigEndian.Uint16(data) != msgMagic {
+		return nil, gopi.ErrUnexpectedResponse
+	}
+	msg := &message{Type: data[2], Received: now()}
+	data = data[msgHeaderSize:]
+	switch msg.Type {
+	case msgClockRequest, msgClockResponse:
+		if len(data) != msgClockSize-msgHeaderSize {
+			return nil, gopi.ErrUnexpectedResponse
+		}
+		msg.T0 = int64(binary.BigEndian.Uint64(data[0:]))
+		msg.T1 = int64(binary.BigEndian.Uint64(data[8:]))
+		msg.T2 = int64(binary.BigEndian.Uint64(data[16:]))
+	case msgAudio:
+		if len(data) < msgAudioHeader-msgHeaderSize {
+			return nil, gopi.ErrUnexpectedResponse
+		}
+		msg.Seq = binary.BigEndian.Uint32(data[0:])
+		msg.Play = int64(binary.BigEndian.Uint64(data[4:]))
+		msg.Format = media.AudioFormat{
+			Rate:     uint(binary.BigEndian.Uint32(data[12:])),
+			Channels: uint(data[16]),
+			Format:   media.SampleFormat(data[17]),
+		}
+		msg.Data = data[18:]
+		if msg.Format.BytesPerFrame() == 0 || msg.Format.Rate == 0 {
+			return nil, gopi.ErrUnexpectedResponse
+		}
+	default:
+		return nil, gopi.ErrUnexpectedResponse
+	}
+	return msg, nil
+}
+
+func appendUint32(data []byte, value uint32) []byte {
+	return append(data, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+}
+
+func appendUint64(data []byte, value uint64) []byte {
+	return appendUint32(appendUint32(data, uint32(value>>32)), uint32(value))
+}
+
+// duration returns the time to play PCM audio
+func duration(format media.AudioFormat, size int) time.Duration {
+	frames := int64(size) / int64(format.BytesPerFrame())
+	return time.Duration(frames * int64(time.Second) / int64(format.Rate))
+}
@@ -0,0 +1,192 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package multiroom
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Receiver plays the audio from a controller on a sink, writing each
+// chunk at the time it should be played on the controller clock, so
+// all the receivers play the same audio at the same time
+type Receiver struct {
+	// Sink plays the audio
+	Sink media.AudioSink
+
+	// Delay is the time between writing audio to the sink and hearing
+	// it, so audio is written early to compensate
+	Delay time.Duration
+
+	// Clock is the estimate of the controller clock
+	Clock Clock
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Interval between clock requests, which is shorter until there
+	// is a good estimate
+	CLOCK_INTERVAL      = time.Second
+	CLOCK_INTERVAL_FAST = 100 * time.Millisecond
+
+	// Chunks which arrive later than this after their play time are
+	// dropped rather than played out of sync
+	MAX_LATE = 20 * time.Millisecond
+
+	// Number of chunks which can be waiting to be played
+	RECEIVE_QUEUE = 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RUN
+
+// Run receives audio from a controller at an address in the form
+// host:port and plays it until stop is closed. Returns an error if the
+// sink does not support the format of the audio.
+func (this *Receiver) Run(address string, stop <-chan struct{}) error {
+	if this.Sink == nil {
+		return gopi.ErrBadParameter
+	}
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return err
+	}
+
+	// Send clock requests and receive messages in the background
+	queue := make(chan *message, RECEIVE_QUEUE)
+	done := make(chan struct{})
+	go this.request(conn, done)
+	go this.receive(conn, queue, done)
+	defer func() {
+		close(done)
+		conn.Close()
+	}()
+
+	// Play each chunk when it is due
+	seq, started := uint32(0), false
+	for {
+		select {
+		case <-stop:
+			return nil
+		case msg := <-queue:
+			// Drop duplicate and out of order chunks, unless the sequence
+			// has gone back far enough that the controller has restarted
+			if delta := int32(msg.Seq - seq); started && delta <= 0 && delta > -RECEIVE_QUEUE {
+				continue
+			}
+			seq, started = msg.Seq, true
+			if err := this.play(msg, stop); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Receiver) String() string {
+	return fmt.Sprintf("<multiroom.Receiver>{ delay=%v clock=%v }", this.Delay, &this.Clock)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// request sends clock requests until done is closed
+func (this *Receiver) request(conn *net.UDPConn, done <-chan struct{}) {
+	for {
+		conn.Write(encodeClock(msgClockRequest, now(), 0, 0))
+		interval := CLOCK_INTERVAL
+		if this.Clock.Ready() == false {
+			interval = CLOCK_INTERVAL_FAST
+		}
+		select {
+		case <-done:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// receive reads datagrams until done is closed, updating the clock
+// estimate and queueing audio once the estimate is good
+func (this *Receiver) receive(conn *net.UDPConn, queue chan<- *message, done <-chan struct{}) {
+	buf := make([]byte, msgAudioHeader+MAX_PAYLOAD)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			// Errors are returned when the controller is not running,
+			// so keep reading until done
+			select {
+			case <-done:
+				return
+			case <-time.After(CLOCK_INTERVAL_FAST):
+				continue
+			}
+		}
+		msg, err := decode(buf[:n])
+		if err != nil {
+			continue
+		}
+		switch msg.Type {
+		case msgClockResponse:
+			this.Clock.Add(msg.T0, msg.T1, msg.T2, msg.Received)
+		case msgAudio:
+			if this.Clock.Ready() {
+				msg.Data = append([]byte{}, msg.Data...)
+				select {
+				case queue <- msg:
+				default:
+					// Queue is full, so drop the chunk
+				}
+			}
+		}
+	}
+}
+
+// play waits until a chunk is due and writes it to the sink
+func (this *Receiver) play(msg *message, stop <-chan struct{}) error {
+	if msg.Format != this.Sink.Format() {
+		if format, err := this.Sink.SetFormat(msg.Format); err != nil {
+			return err
+		} else if format != msg.Format {
+			return gopi.ErrBadParameter
+		}
+	}
+
+	// Convert the play time to the local clock
+	offset, _, _ := this.Clock.Offset()
+	due := time.Unix(0, msg.Play).Add(-offset - this.Delay)
+	if d := time.Until(due); d > 0 {
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(d):
+		}
+	} else if -d > MAX_LATE {
+		return nil
+	}
+	_, err := this.Sink.Write(msg.Data)
+	return err
+}
@@ -0,0 +1,25 @@
+//go:build go1.18
+// +build go1.18
+
+package nfo_test
+
+import (
+	"bytes"
+	"testing"
+
+	// Frameworks
+	nfo "github.com/djthorpe/gopi-media/nfo"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FUZZ
+
+func Fuzz_nfo_Read(f *testing.F) {
+	f.Add([]byte(MOVIE_NFO))
+	f.Add([]byte("https://www.imdb.com/title/tt0133093/"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if doc, err := nfo.Read(bytes.NewReader(data)); err == nil {
+			doc.Keys()
+		}
+	})
+}
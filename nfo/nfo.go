@@ -0,0 +1,246 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package nfo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Document is a Kodi-style NFO file, which describes a movie, tv show,
+// episode or music video
+type Document struct {
+	XMLName       xml.Name
+	Title         string     `xml:"title,omitempty"`
+	OriginalTitle string     `xml:"originaltitle,omitempty"`
+	SortTitle     string     `xml:"sorttitle,omitempty"`
+	ShowTitle     string     `xml:"showtitle,omitempty"`
+	Outline       string     `xml:"outline,omitempty"`
+	Plot          string     `xml:"plot,omitempty"`
+	Tagline       string     `xml:"tagline,omitempty"`
+	Year          string     `xml:"year,omitempty"`
+	Premiered     string     `xml:"premiered,omitempty"`
+	Aired         string     `xml:"aired,omitempty"`
+	Season        string     `xml:"season,omitempty"`
+	Episode       string     `xml:"episode,omitempty"`
+	Genres        []string   `xml:"genre,omitempty"`
+	Studios       []string   `xml:"studio,omitempty"`
+	Artists       []string   `xml:"artist,omitempty"`
+	Album         string     `xml:"album,omitempty"`
+	Track         string     `xml:"track,omitempty"`
+	UniqueIds     []UniqueId `xml:"uniqueid,omitempty"`
+	Id            string     `xml:"id,omitempty"`
+	ImdbId        string     `xml:"imdbid,omitempty"`
+	TmdbId        string     `xml:"tmdbid,omitempty"`
+}
+
+// UniqueId is an identifier for an external database
+type UniqueId struct {
+	Type    string `xml:"type,attr,omitempty"`
+	Default bool   `xml:"default,attr,omitempty"`
+	Value   string `xml:",chardata"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	NFO_EXT     = ".nfo"
+	NFO_MOVIE   = "movie.nfo"
+	NFO_TVSHOW  = "tvshow.nfo"
+	NFO_MAXSIZE = 1024 * 1024
+)
+
+// Suffixes for poster and fanart images written next to a media file
+const (
+	POSTER_SUFFIX = "-poster.jpg"
+	FANART_SUFFIX = "-fanart.jpg"
+)
+
+const (
+	ROOT_MOVIE      = "movie"
+	ROOT_TVSHOW     = "tvshow"
+	ROOT_EPISODE    = "episodedetails"
+	ROOT_MUSICVIDEO = "musicvideo"
+)
+
+var (
+	reImdbId = regexp.MustCompile("\\btt\\d{7,8}\\b")
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// ReadFile reads an NFO file from disk
+func ReadFile(filename string) (*Document, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return Read(fh)
+	}
+}
+
+// Read parses an NFO file, which is either an XML document or a plain
+// text file containing an IMDB URL
+func Read(r io.Reader) (*Document, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, NFO_MAXSIZE))
+	if err != nil {
+		return nil, err
+	}
+
+	// Any trailing data after the root element (usually a URL) is ignored
+	// by the decoder
+	this := new(Document)
+	if bytes.Contains(data, []byte("<")) {
+		if err := xml.NewDecoder(bytes.NewReader(data)).Decode(this); err != nil {
+			return nil, err
+		}
+	}
+	switch this.XMLName.Local {
+	case ROOT_MOVIE, ROOT_TVSHOW, ROOT_EPISODE, ROOT_MUSICVIDEO:
+		return this, nil
+	case "":
+		// Plain text NFO files may contain only an IMDB reference
+		if id := reImdbId.Find(data); id != nil {
+			this.ImdbId = string(id)
+			return this, nil
+		}
+	}
+
+	// Unsupported NFO file
+	return nil, gopi.ErrBadParameter
+}
+
+// SidecarsFor returns the NFO files which could describe a media file,
+// in order from lowest to highest precedence
+func SidecarsFor(filename string) []string {
+	dir := filepath.Dir(filename)
+	base := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	return []string{
+		filepath.Join(filepath.Dir(dir), NFO_TVSHOW),
+		filepath.Join(dir, NFO_TVSHOW),
+		filepath.Join(dir, NFO_MOVIE),
+		filepath.Join(dir, base+NFO_EXT),
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METADATA
+
+// Type returns the media type described by the document
+func (this *Document) Type() media.MediaType {
+	switch this.XMLName.Local {
+	case ROOT_MOVIE:
+		return media.MEDIA_TYPE_MOVIE
+	case ROOT_TVSHOW:
+		return media.MEDIA_TYPE_TVSHOW
+	case ROOT_EPISODE:
+		return media.MEDIA_TYPE_TVEPISODE
+	case ROOT_MUSICVIDEO:
+		return media.MEDIA_TYPE_MUSICVIDEO
+	default:
+		return media.MEDIA_TYPE_NONE
+	}
+}
+
+// Keys returns the metadata in the document. A tv show document only
+// returns keys which apply to all episodes of the show.
+func (this *Document) Keys() map[media.MetadataKey]string {
+	keys := make(map[media.MetadataKey]string)
+	if this.Type() == media.MEDIA_TYPE_TVSHOW {
+		set(keys, media.METADATA_KEY_SHOW, this.Title)
+		set(keys, media.METADATA_KEY_GENRE, strings.Join(this.Genres, ", "))
+		set(keys, media.METADATA_KEY_PUBLISHER, strings.Join(this.Studios, ", "))
+		return keys
+	}
+
+	// Strings
+	set(keys, media.METADATA_KEY_TITLE, this.Title)
+	set(keys, media.METADATA_KEY_TITLE_SORT, this.SortTitle)
+	set(keys, media.METADATA_KEY_SHOW, this.ShowTitle)
+	set(keys, media.METADATA_KEY_DESCRIPTION, this.Outline)
+	set(keys, media.METADATA_KEY_SYNOPSIS, this.Plot)
+	set(keys, media.METADATA_KEY_GENRE, strings.Join(this.Genres, ", "))
+	set(keys, media.METADATA_KEY_PUBLISHER, strings.Join(this.Studios, ", "))
+	set(keys, media.METADATA_KEY_ARTIST, strings.Join(this.Artists, ", "))
+	set(keys, media.METADATA_KEY_ALBUM, this.Album)
+
+	// Numbers
+	setUint(keys, media.METADATA_KEY_SEASON, this.Season)
+	setUint(keys, media.METADATA_KEY_EPISODE_SORT, this.Episode)
+	setUint(keys, media.METADATA_KEY_TRACK, this.Track)
+
+	// Dates
+	if this.Premiered != "" {
+		set(keys, media.METADATA_KEY_YEAR, this.Premiered)
+	} else {
+		set(keys, media.METADATA_KEY_YEAR, this.Year)
+	}
+	set(keys, media.METADATA_KEY_AIRED, this.Aired)
+
+	// External identifiers
+	set(keys, media.METADATA_KEY_IMDB_ID, this.ImdbId)
+	set(keys, media.METADATA_KEY_TMDB_ID, this.TmdbId)
+	if reImdbId.MatchString(this.Id) {
+		set(keys, media.METADATA_KEY_IMDB_ID, this.Id)
+	}
+	for _, id := range this.UniqueIds {
+		switch strings.ToLower(id.Type) {
+		case "imdb":
+			set(keys, media.METADATA_KEY_IMDB_ID, id.Value)
+		case "tmdb":
+			set(keys, media.METADATA_KEY_TMDB_ID, id.Value)
+		case "tvdb":
+			set(keys, media.METADATA_KEY_TVDB_ID, id.Value)
+		}
+	}
+
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Document) String() string {
+	return fmt.Sprintf("<nfo.Document>{ type=%v title=%v }", this.Type(), strconv.Quote(this.Title))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func set(keys map[media.MetadataKey]string, key media.MetadataKey, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		keys[key] = value
+	}
+}
+
+func setUint(keys map[media.MetadataKey]string, key media.MetadataKey, value string) {
+	if value_, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32); err == nil {
+		keys[key] = fmt.Sprint(value_)
+	}
+}
@@ -0,0 +1,158 @@
+package nfo_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+)
+
+const (
+	MOVIE_NFO = `<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>
+<movie>
+	<title>The Matrix</title>
+	<sorttitle>Matrix</sorttitle>
+	<outline>A hacker learns the truth</outline>
+	<plot>Thomas Anderson is a computer programmer</plot>
+	<premiered>1999-03-31</premiered>
+	<genre>Action</genre>
+	<genre>Science Fiction</genre>
+	<uniqueid type="imdb" default="true">tt0133093</uniqueid>
+	<uniqueid type="tmdb">603</uniqueid>
+</movie>
+https://www.imdb.com/title/tt0133093/`
+	EPISODE_NFO = `<episodedetails>
+	<title>Pilot</title>
+	<showtitle>Example Show</showtitle>
+	<season>1</season>
+	<episode>01</episode>
+	<aired>2008-01-20</aired>
+	<uniqueid type="tvdb">349232</uniqueid>
+</episodedetails>`
+	TVSHOW_NFO = `<tvshow>
+	<title>Example Show</title>
+	<plot>Show plot</plot>
+	<genre>Drama</genre>
+</tvshow>`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST READ
+
+func Test_nfo_000(t *testing.T) {
+	t.Log("Test_nfo_000")
+}
+
+func Test_nfo_001(t *testing.T) {
+	if doc, err := nfo.Read(strings.NewReader(MOVIE_NFO)); err != nil {
+		t.Fatal(err)
+	} else if doc.Type() != media.MEDIA_TYPE_MOVIE {
+		t.Error("Unexpected type", doc.Type())
+	} else {
+		keys := doc.Keys()
+		if keys[media.METADATA_KEY_TITLE] != "The Matrix" {
+			t.Error("Unexpected title", keys[media.METADATA_KEY_TITLE])
+		}
+		if keys[media.METADATA_KEY_GENRE] != "Action, Science Fiction" {
+			t.Error("Unexpected genre", keys[media.METADATA_KEY_GENRE])
+		}
+		if keys[media.METADATA_KEY_YEAR] != "1999-03-31" {
+			t.Error("Unexpected year", keys[media.METADATA_KEY_YEAR])
+		}
+		if keys[media.METADATA_KEY_IMDB_ID] != "tt0133093" {
+			t.Error("Unexpected imdb id", keys[media.METADATA_KEY_IMDB_ID])
+		}
+		if keys[media.METADATA_KEY_TMDB_ID] != "603" {
+			t.Error("Unexpected tmdb id", keys[media.METADATA_KEY_TMDB_ID])
+		}
+	}
+}
+
+func Test_nfo_002(t *testing.T) {
+	if doc, err := nfo.Read(strings.NewReader(EPISODE_NFO)); err != nil {
+		t.Fatal(err)
+	} else if doc.Type() != media.MEDIA_TYPE_TVEPISODE {
+		t.Error("Unexpected type", doc.Type())
+	} else {
+		keys := doc.Keys()
+		if keys[media.METADATA_KEY_SEASON] != "1" {
+			t.Error("Unexpected season", keys[media.METADATA_KEY_SEASON])
+		}
+		if keys[media.METADATA_KEY_EPISODE_SORT] != "1" {
+			t.Error("Unexpected episode", keys[media.METADATA_KEY_EPISODE_SORT])
+		}
+		if keys[media.METADATA_KEY_AIRED] != "2008-01-20" {
+			t.Error("Unexpected aired", keys[media.METADATA_KEY_AIRED])
+		}
+		if keys[media.METADATA_KEY_TVDB_ID] != "349232" {
+			t.Error("Unexpected tvdb id", keys[media.METADATA_KEY_TVDB_ID])
+		}
+	}
+}
+
+func Test_nfo_003(t *testing.T) {
+	if doc, err := nfo.Read(strings.NewReader(TVSHOW_NFO)); err != nil {
+		t.Fatal(err)
+	} else {
+		keys := doc.Keys()
+		if keys[media.METADATA_KEY_SHOW] != "Example Show" {
+			t.Error("Unexpected show", keys[media.METADATA_KEY_SHOW])
+		}
+		if _, exists := keys[media.METADATA_KEY_SYNOPSIS]; exists {
+			t.Error("Show plot should not apply to episodes")
+		}
+		if _, exists := keys[media.METADATA_KEY_TITLE]; exists {
+			t.Error("Show title should not apply to episodes")
+		}
+	}
+}
+
+func Test_nfo_004(t *testing.T) {
+	if doc, err := nfo.Read(strings.NewReader("http://www.imdb.com/title/tt0133093/\n")); err != nil {
+		t.Fatal(err)
+	} else if doc.Keys()[media.METADATA_KEY_IMDB_ID] != "tt0133093" {
+		t.Error("Unexpected keys", doc.Keys())
+	}
+	if _, err := nfo.Read(strings.NewReader("Some release notes")); err == nil {
+		t.Error("Expected error for unsupported file")
+	}
+	if _, err := nfo.Read(strings.NewReader("<album><title>x</title></album>")); err == nil {
+		t.Error("Expected error for unsupported root element")
+	}
+}
+
+func Test_nfo_005(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	season := filepath.Join(dir, "Season 1")
+	if err := os.Mkdir(season, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "tvshow.nfo"), []byte(TVSHOW_NFO), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sidecars := nfo.SidecarsFor(filepath.Join(season, "S01E01.mkv"))
+	if len(sidecars) != 4 {
+		t.Fatal("Unexpected sidecars", sidecars)
+	} else if sidecars[0] != filepath.Join(dir, "tvshow.nfo") {
+		t.Error("Unexpected sidecar", sidecars[0])
+	} else if sidecars[3] != filepath.Join(season, "S01E01.nfo") {
+		t.Error("Unexpected sidecar", sidecars[3])
+	}
+	if doc, err := nfo.ReadFile(sidecars[0]); err != nil {
+		t.Error(err)
+	} else if doc.Type() != media.MEDIA_TYPE_TVSHOW {
+		t.Error("Unexpected type", doc.Type())
+	}
+	if _, err := nfo.ReadFile(sidecars[3]); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,258 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package nfo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	artwork "github.com/djthorpe/gopi-media/artwork"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW DOCUMENT
+
+// NewDocument returns an NFO document from the metadata of a media item.
+// When the item type is not known, items with a show name are written as
+// episodes and all other items as movies.
+func NewDocument(item media.MediaItem) (*Document, error) {
+	this := new(Document)
+	switch rootFor(item) {
+	case ROOT_MOVIE:
+		this.XMLName.Local = ROOT_MOVIE
+		this.Title = item.StringForKey(media.METADATA_KEY_TITLE)
+		this.SortTitle = item.StringForKey(media.METADATA_KEY_TITLE_SORT)
+	case ROOT_EPISODE:
+		this.XMLName.Local = ROOT_EPISODE
+		this.Title = item.StringForKey(media.METADATA_KEY_TITLE)
+		this.ShowTitle = item.StringForKey(media.METADATA_KEY_SHOW)
+		this.Season = item.StringForKey(media.METADATA_KEY_SEASON)
+		this.Episode = item.StringForKey(media.METADATA_KEY_EPISODE_SORT)
+		this.Aired = item.StringForKey(media.METADATA_KEY_AIRED)
+	case ROOT_MUSICVIDEO:
+		this.XMLName.Local = ROOT_MUSICVIDEO
+		this.Title = item.StringForKey(media.METADATA_KEY_TITLE)
+		this.Artists = split(item.StringForKey(media.METADATA_KEY_ARTIST))
+		this.Album = item.StringForKey(media.METADATA_KEY_ALBUM)
+		this.Track = item.StringForKey(media.METADATA_KEY_TRACK)
+	default:
+		return nil, gopi.ErrBadParameter
+	}
+
+	// Fall back to the item title, which may be derived from the filename
+	if this.Title == "" {
+		this.Title = item.Title()
+	}
+
+	// Common elements
+	this.Outline = item.StringForKey(media.METADATA_KEY_DESCRIPTION)
+	this.Plot = item.StringForKey(media.METADATA_KEY_SYNOPSIS)
+	this.Genres = split(item.StringForKey(media.METADATA_KEY_GENRE))
+	this.Studios = split(item.StringForKey(media.METADATA_KEY_PUBLISHER))
+
+	// Write a year and a full date where one is available
+	if year := item.StringForKey(media.METADATA_KEY_YEAR); len(year) >= 4 {
+		this.Year = year[0:4]
+		if len(year) >= 10 {
+			this.Premiered = year[0:10]
+		}
+	}
+
+	// External identifiers, the first of which is the default
+	for _, id := range []struct {
+		t   string
+		key media.MetadataKey
+	}{
+		{"imdb", media.METADATA_KEY_IMDB_ID},
+		{"tmdb", media.METADATA_KEY_TMDB_ID},
+		{"tvdb", media.METADATA_KEY_TVDB_ID},
+	} {
+		if value := item.StringForKey(id.key); value != "" {
+			this.UniqueIds = append(this.UniqueIds, UniqueId{
+				Type:    id.t,
+				Default: len(this.UniqueIds) == 0,
+				Value:   value,
+			})
+		}
+	}
+
+	// Success
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITE
+
+// Write outputs the document as XML
+func (this *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(this); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Supported returns true if an NFO document can be written for an item,
+// which is when it is a movie, episode or music video
+func Supported(item media.MediaItem) bool {
+	return rootFor(item) != ""
+}
+
+// WriteSidecar writes an NFO file next to a media file, replacing any
+// existing NFO file for the media file, and returns the path written.
+// Poster and fanart images are written alongside with Kodi naming, from
+// the cache when it is not nil or the front cover embedded in the file
+func WriteSidecar(file media.MediaFile, cache *artwork.Cache) (string, error) {
+	filename := file.Filename()
+	if filename == "" {
+		return "", gopi.ErrBadParameter
+	}
+	doc, err := NewDocument(file)
+	if err != nil {
+		return "", err
+	}
+
+	// Write the document
+	base := filepath.Join(filepath.Dir(filename), strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)))
+	path := base + NFO_EXT
+	if err := writeFile(path, doc.Write); err != nil {
+		return "", err
+	}
+
+	// Write the artwork as JPEG images
+	for suffix, names := range map[string][]string{
+		POSTER_SUFFIX: {artwork.ARTWORK_POSTER, artwork.ARTWORK_COVER},
+		FANART_SUFFIX: {artwork.ARTWORK_FANART},
+	} {
+		if r, format, err := artworkFor(file, cache, names); err == gopi.ErrNotFound {
+			continue
+		} else if err != nil {
+			return "", err
+		} else {
+			// JPEG artwork is copied unchanged
+			err := writeFile(base+suffix, func(w io.Writer) error {
+				var err error
+				if format == artwork.FORMAT_JPEG {
+					_, err = io.Copy(w, r)
+				} else {
+					_, err = artwork.Convert(w, r, artwork.Options{Format: artwork.FORMAT_JPEG})
+				}
+				return err
+			})
+			r.Close()
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	// Success
+	return path, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// writeFile writes to a temporary file then moves it into place, so
+// an existing file is not left half-written
+func writeFile(path string, write func(io.Writer) error) error {
+	if fh, err := ioutil.TempFile(filepath.Dir(path), ".nfo"); err != nil {
+		return err
+	} else if err := fh.Chmod(0644); err != nil {
+		fh.Close()
+		os.Remove(fh.Name())
+		return err
+	} else if err := write(fh); err != nil {
+		fh.Close()
+		os.Remove(fh.Name())
+		return err
+	} else if err := fh.Close(); err != nil {
+		os.Remove(fh.Name())
+		return err
+	} else if err := os.Rename(fh.Name(), path); err != nil {
+		os.Remove(fh.Name())
+		return err
+	} else {
+		return nil
+	}
+}
+
+// artworkFor returns the first artwork in the cache with one of the
+// names, or the embedded front cover for a poster or cover, and the
+// format of the artwork, or gopi.ErrNotFound
+func artworkFor(file media.MediaFile, cache *artwork.Cache, names []string) (io.ReadCloser, artwork.Format, error) {
+	cover := false
+	for _, name := range names {
+		if cache != nil && file.Id() != "" {
+			if path, err := cache.Get(file.Id(), name); err == nil {
+				if fh, err := os.Open(path); err != nil {
+					return nil, artwork.FORMAT_NONE, err
+				} else {
+					return fh, artwork.FormatForName(strings.TrimPrefix(filepath.Ext(path), ".")), nil
+				}
+			} else if err != gopi.ErrNotFound {
+				return nil, artwork.FORMAT_NONE, err
+			}
+		}
+		if name == artwork.ARTWORK_POSTER || name == artwork.ARTWORK_COVER {
+			cover = true
+		}
+	}
+	if cover {
+		for _, image := range file.Artwork() {
+			if image.Type() == media.ARTWORK_TYPE_FRONT_COVER && len(image.Data()) > 0 {
+				return ioutil.NopCloser(bytes.NewReader(image.Data())), artwork.FormatForMimeType(image.MimeType()), nil
+			}
+		}
+	}
+	return nil, artwork.FORMAT_NONE, gopi.ErrNotFound
+}
+
+func rootFor(item media.MediaItem) string {
+	switch item.Type() {
+	case media.MEDIA_TYPE_MOVIE:
+		return ROOT_MOVIE
+	case media.MEDIA_TYPE_TVEPISODE:
+		return ROOT_EPISODE
+	case media.MEDIA_TYPE_MUSICVIDEO:
+		return ROOT_MUSICVIDEO
+	case media.MEDIA_TYPE_NONE:
+		if item.StringForKey(media.METADATA_KEY_SHOW) != "" {
+			return ROOT_EPISODE
+		} else {
+			return ROOT_MOVIE
+		}
+	default:
+		return ""
+	}
+}
+
+func split(value string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(value, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,221 @@
+package nfo_test
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	artwork "github.com/djthorpe/gopi-media/artwork"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIA FILE
+
+type file struct {
+	filename string
+	t        media.MediaType
+	keys     map[media.MetadataKey]string
+}
+
+func (this *file) Id() string                    { return "" }
+func (this *file) Title() string                 { return filepath.Base(this.filename) }
+func (this *file) Type() media.MediaType         { return this.t }
+func (this *file) Filename() string              { return this.filename }
+func (this *file) Streams() []media.MediaStream  { return nil }
+func (this *file) Artwork() []media.MediaArtwork { return nil }
+
+func (this *file) Keys() []media.MetadataKey {
+	keys := make([]media.MetadataKey, 0, len(this.keys))
+	for k := range this.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (this *file) StringForKey(key media.MetadataKey) string {
+	return this.keys[key]
+}
+
+// cover is a media file with an embedded front cover
+type cover struct {
+	*file
+	data picture
+}
+
+type picture []byte
+
+func (this *cover) Id() string                    { return "abc" }
+func (this *cover) Artwork() []media.MediaArtwork { return []media.MediaArtwork{this.data} }
+func (this picture) Type() media.ArtworkType      { return media.ARTWORK_TYPE_FRONT_COVER }
+func (this picture) Data() []byte                 { return this }
+
+func (this picture) MimeType() string {
+	if bytes.HasPrefix(this, []byte{0xFF, 0xD8}) {
+		return "image/jpeg"
+	} else {
+		return "image/png"
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST WRITE
+
+func Test_writer_001(t *testing.T) {
+	item := &file{"/tmp/movie.mp4", media.MEDIA_TYPE_MOVIE, map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE:   "The Matrix",
+		media.METADATA_KEY_YEAR:    "1999-03-31T00:00:00Z",
+		media.METADATA_KEY_GENRE:   "Action, Science Fiction",
+		media.METADATA_KEY_IMDB_ID: "tt0133093",
+	}}
+	buf := new(bytes.Buffer)
+	if doc, err := nfo.NewDocument(item); err != nil {
+		t.Fatal(err)
+	} else if err := doc.Write(buf); err != nil {
+		t.Fatal(err)
+	} else if doc2, err := nfo.Read(buf); err != nil {
+		t.Fatal(err)
+	} else if doc2.Type() != media.MEDIA_TYPE_MOVIE {
+		t.Error("Unexpected type", doc2.Type())
+	} else {
+		keys := doc2.Keys()
+		if keys[media.METADATA_KEY_TITLE] != "The Matrix" {
+			t.Error("Unexpected title", keys[media.METADATA_KEY_TITLE])
+		}
+		if keys[media.METADATA_KEY_YEAR] != "1999-03-31" {
+			t.Error("Unexpected year", keys[media.METADATA_KEY_YEAR])
+		}
+		if keys[media.METADATA_KEY_GENRE] != "Action, Science Fiction" {
+			t.Error("Unexpected genre", keys[media.METADATA_KEY_GENRE])
+		}
+		if keys[media.METADATA_KEY_IMDB_ID] != "tt0133093" {
+			t.Error("Unexpected imdb id", keys[media.METADATA_KEY_IMDB_ID])
+		}
+	}
+}
+
+func Test_writer_002(t *testing.T) {
+	item := &file{"/tmp/episode.mkv", media.MEDIA_TYPE_NONE, map[media.MetadataKey]string{
+		media.METADATA_KEY_SHOW:         "Example Show",
+		media.METADATA_KEY_SEASON:       "2",
+		media.METADATA_KEY_EPISODE_SORT: "3",
+	}}
+	if doc, err := nfo.NewDocument(item); err != nil {
+		t.Fatal(err)
+	} else if doc.Type() != media.MEDIA_TYPE_TVEPISODE {
+		t.Error("Unexpected type", doc.Type())
+	} else if doc.Title != "episode.mkv" {
+		t.Error("Unexpected title", doc.Title)
+	}
+	if _, err := nfo.NewDocument(&file{"/tmp/song.mp3", media.MEDIA_TYPE_MUSIC, nil}); err == nil {
+		t.Error("Expected error for unsupported type")
+	}
+}
+
+func Test_writer_003(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	item := &file{filepath.Join(dir, "movie.mp4"), media.MEDIA_TYPE_MOVIE, map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE: "Example",
+	}}
+	if path, err := nfo.WriteSidecar(item, nil); err != nil {
+		t.Fatal(err)
+	} else if path != filepath.Join(dir, "movie.nfo") {
+		t.Error("Unexpected path", path)
+	} else if doc, err := nfo.ReadFile(path); err != nil {
+		t.Error(err)
+	} else if doc.Title != "Example" {
+		t.Error("Unexpected title", doc.Title)
+	}
+}
+
+func Test_writer_004(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 2))); err != nil {
+		t.Fatal(err)
+	}
+	item := &cover{&file{filepath.Join(dir, "movie.mp4"), media.MEDIA_TYPE_MOVIE, nil}, buf.Bytes()}
+
+	// Poster from the embedded cover, no fanart
+	if _, err := nfo.WriteSidecar(item, nil); err != nil {
+		t.Fatal(err)
+	} else if fh, err := os.Open(filepath.Join(dir, "movie-poster.jpg")); err != nil {
+		t.Error(err)
+	} else if config, format, err := image.DecodeConfig(fh); err != nil {
+		t.Error(err)
+	} else if format != "jpeg" || config.Width != 4 || config.Height != 2 {
+		t.Error("Unexpected poster", format, config.Width, config.Height)
+	} else if _, err := os.Stat(filepath.Join(dir, "movie-fanart.jpg")); os.IsNotExist(err) == false {
+		t.Error("Unexpected fanart", err)
+	}
+
+	// Fanart from the cache
+	cache := &artwork.Cache{Path: filepath.Join(dir, "cache")}
+	if _, err := cache.Put("abc", artwork.ARTWORK_FANART, bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	} else if _, err := nfo.WriteSidecar(item, cache); err != nil {
+		t.Fatal(err)
+	} else if _, err := os.Stat(filepath.Join(dir, "movie-fanart.jpg")); err != nil {
+		t.Error(err)
+	}
+
+	// Poster from the cache before the embedded cover
+	poster := new(bytes.Buffer)
+	if err := png.Encode(poster, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	} else if _, err := cache.Put("abc", artwork.ARTWORK_POSTER, poster); err != nil {
+		t.Fatal(err)
+	} else if _, err := nfo.WriteSidecar(item, cache); err != nil {
+		t.Fatal(err)
+	} else if fh, err := os.Open(filepath.Join(dir, "movie-poster.jpg")); err != nil {
+		t.Error(err)
+	} else if config, _, err := image.DecodeConfig(fh); err != nil {
+		t.Error(err)
+	} else if config.Width != 8 {
+		t.Error("Unexpected poster", config.Width, config.Height)
+	}
+}
+
+func Test_writer_005(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// JPEG covers are copied unchanged
+	buf := new(bytes.Buffer)
+	if err := jpeg.Encode(buf, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+	item := &cover{&file{filepath.Join(dir, "movie.mp4"), media.MEDIA_TYPE_MOVIE, nil}, buf.Bytes()}
+	if _, err := nfo.WriteSidecar(item, nil); err != nil {
+		t.Fatal(err)
+	} else if data, err := ioutil.ReadFile(filepath.Join(dir, "movie-poster.jpg")); err != nil {
+		t.Error(err)
+	} else if bytes.Equal(data, buf.Bytes()) == false {
+		t.Error("Expected cover to be copied unchanged")
+	}
+
+	// Music is not supported
+	if nfo.Supported(&file{"song.mp3", media.MEDIA_TYPE_MUSIC, nil}) {
+		t.Error("Expected music to be unsupported")
+	} else if nfo.Supported(item) == false {
+		t.Error("Expected movie to be supported")
+	}
+}
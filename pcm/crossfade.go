@@ -0,0 +1,165 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package pcm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Curve is the shape of the gain as one track fades out and the next
+// fades in
+type Curve uint
+
+// Crossfade mixes the end of one track into the start of the next
+type Crossfade struct {
+	Duration time.Duration
+	Curve    Curve
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	CURVE_LINEAR      Curve = iota // Constant amplitude, with a dip in loudness
+	CURVE_EQUAL_POWER              // Constant loudness for uncorrelated tracks
+	CURVE_SMOOTH                   // S-shaped, with a slow start and end
+	CURVE_MAX         = CURVE_SMOOTH
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CROSSFADE
+
+// Frames returns the number of frames in the crossfade for a format,
+// which is zero when there is no crossfade
+func (this Crossfade) Frames(format media.AudioFormat) uint {
+	if this.Duration <= 0 {
+		return 0
+	}
+	return uint(uint64(this.Duration) * uint64(format.Rate) / uint64(time.Second))
+}
+
+// Mix returns the end of the outgoing track mixed with the start of
+// the incoming track, where both are interleaved samples with a number
+// of channels. The fade lasts for the length of the outgoing samples,
+// which are usually Frames() long, and incoming samples after the fade
+// are returned unchanged.
+func (this Crossfade) Mix(channels uint, from, to []float32) []float32 {
+	if channels == 0 {
+		return nil
+	}
+	size := len(from)
+	if len(to) > size {
+		size = len(to)
+	}
+	frames := len(from) / int(channels)
+	result := make([]float32, size)
+	copy(result, to)
+	for i := 0; i < frames; i++ {
+		out, in := this.Curve.Gain(float64(i) / float64(frames))
+		for j := i * int(channels); j < (i+1)*int(channels); j++ {
+			result[j] = from[j]*float32(out) + result[j]*float32(in)
+		}
+	}
+	return result
+}
+
+// Allowed returns false when the next track follows on from the last
+// track of an album and either is marked for gapless playback, so
+// that live and continuous albums are not faded between tracks
+func Allowed(from, to media.MediaItem) bool {
+	if from == nil || to == nil {
+		return true
+	} else if gapless(from) == false && gapless(to) == false {
+		return true
+	}
+	album := strings.TrimSpace(from.StringForKey(media.METADATA_KEY_ALBUM))
+	if album == "" || album != strings.TrimSpace(to.StringForKey(media.METADATA_KEY_ALBUM)) {
+		return true
+	}
+	if number(from, media.METADATA_KEY_DISC) != number(to, media.METADATA_KEY_DISC) {
+		// The first track of the next disc follows on
+		return number(to, media.METADATA_KEY_TRACK) > 1
+	}
+	return number(to, media.METADATA_KEY_TRACK) != number(from, media.METADATA_KEY_TRACK)+1
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CURVE
+
+// Gain returns the gain of the outgoing and incoming tracks at a
+// position through the fade, from 0.0 to 1.0
+func (c Curve) Gain(position float64) (float64, float64) {
+	if position <= 0 {
+		return 1, 0
+	} else if position >= 1 {
+		return 0, 1
+	}
+	switch c {
+	case CURVE_EQUAL_POWER:
+		return math.Cos(position * math.Pi / 2), math.Sin(position * math.Pi / 2)
+	case CURVE_SMOOTH:
+		s := position * position * (3 - 2*position)
+		return 1 - s, s
+	default:
+		return 1 - position, position
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (c Curve) String() string {
+	switch c {
+	case CURVE_LINEAR:
+		return "CURVE_LINEAR"
+	case CURVE_EQUAL_POWER:
+		return "CURVE_EQUAL_POWER"
+	case CURVE_SMOOTH:
+		return "CURVE_SMOOTH"
+	default:
+		return "[?? Invalid Curve value]"
+	}
+}
+
+func (this Crossfade) String() string {
+	return fmt.Sprintf("<pcm.Crossfade>{ duration=%v curve=%v }", this.Duration, this.Curve)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// gapless returns true if an item is marked for gapless playback
+func gapless(item media.MediaItem) bool {
+	flag := strings.TrimSpace(item.StringForKey(media.METADATA_KEY_GAPLESS_PLAYBACK))
+	if value, err := strconv.ParseBool(flag); err == nil {
+		return value
+	}
+	return false
+}
+
+// number returns a track or disc number, which may be written as "3/12"
+func number(item media.MediaItem, key media.MetadataKey) uint64 {
+	value := strings.TrimSpace(item.StringForKey(key))
+	if i := strings.Index(value, "/"); i >= 0 {
+		value = value[:i]
+	}
+	n, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+	return n
+}
@@ -0,0 +1,163 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package pcm
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Processor changes decoded samples before they are written to an
+// AudioSink
+type Processor interface {
+	// Process interleaved samples in a format, returning the format and
+	// samples after processing, which may be the same slice. Processing
+	// no samples returns the format after processing.
+	Process(media.AudioFormat, []float32) (media.AudioFormat, []float32)
+
+	// Reset clears any state between tracks or after seeking
+	Reset()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Chain applies processors in order, and can be changed while audio is
+// being processed
+type Chain struct {
+	lock       sync.Mutex
+	processors []Processor
+}
+
+// Downmix mixes audio with more channels down to mono or stereo. For
+// stereo, the centre channel is mixed into both sides and the LFE
+// channel is discarded, using the channel order of WAV and FFmpeg
+// (front left, front right, centre, LFE, then left and right pairs).
+type Downmix struct {
+	Channels uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CHAIN
+
+// NewChain returns a chain of processors
+func NewChain(processors ...Processor) *Chain {
+	this := new(Chain)
+	this.Set(processors...)
+	return this
+}
+
+// Set replaces the processors, which takes effect from the next samples
+// processed
+func (this *Chain) Set(processors ...Processor) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.processors = append([]Processor{}, processors...)
+}
+
+// Processors returns the current processors
+func (this *Chain) Processors() []Processor {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]Processor{}, this.processors...)
+}
+
+func (this *Chain) Process(format media.AudioFormat, samples []float32) (media.AudioFormat, []float32) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, processor := range this.processors {
+		format, samples = processor.Process(format, samples)
+	}
+	return format, samples
+}
+
+func (this *Chain) Reset() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, processor := range this.processors {
+		processor.Reset()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DOWNMIX
+
+func (this Downmix) Process(format media.AudioFormat, samples []float32) (media.AudioFormat, []float32) {
+	if this.Channels == 0 || this.Channels > 2 || format.Channels <= this.Channels {
+		return format, samples
+	}
+	channels := int(format.Channels)
+	frames := len(samples) / channels
+	result := make([]float32, frames*int(this.Channels))
+	for i := 0; i < frames; i++ {
+		frame := samples[i*channels : i*channels+channels]
+		if this.Channels == 1 {
+			result[i] = mono(frame)
+		} else {
+			result[i*2], result[i*2+1] = stereo(frame)
+		}
+	}
+	format.Channels = this.Channels
+	return format, result
+}
+
+func (this Downmix) Reset() {
+	// Downmix has no state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Chain) String() string {
+	return fmt.Sprintf("<pcm.Chain>{ processors=%v }", this.Processors())
+}
+
+func (this Downmix) String() string {
+	return fmt.Sprintf("<pcm.Downmix>{ channels=%v }", this.Channels)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func mono(frame []float32) float32 {
+	if len(frame) == 2 {
+		return (frame[0] + frame[1]) / 2
+	}
+	left, right := stereo(frame)
+	return (left + right) / 2
+}
+
+// stereo mixes a frame with three or more channels into left and right,
+// scaled so that a full scale centre channel does not clip
+func stereo(frame []float32) (float32, float32) {
+	const centre = math.Sqrt2 / 2
+	left, right := frame[0], frame[1]
+	scale := float32(1)
+	if len(frame) > 2 {
+		left += frame[2] * centre
+		right += frame[2] * centre
+		scale += centre
+	}
+	for i := 4; i+1 < len(frame); i += 2 {
+		left += frame[i] * centre
+		right += frame[i+1] * centre
+	}
+	if len(frame) > 4 {
+		scale += centre
+	}
+	return left / scale, right / scale
+}
@@ -0,0 +1,142 @@
+package pcm_test
+
+import (
+	"math"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	pcm "github.com/djthorpe/gopi-media/pcm"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST DSP
+
+func Test_dsp_000(t *testing.T) {
+	t.Log("Test_dsp_000")
+}
+
+func Test_dsp_001(t *testing.T) {
+	// A 5.1 frame with centre and rear channels downmixed to stereo, then
+	// to mono
+	format := media.AudioFormat{Format: media.SAMPLE_FORMAT_S16, Rate: 48000, Channels: 6}
+	frame := []float32{1, 0, 1, 1, 1, 0}
+	if f, samples := (pcm.Downmix{Channels: 2}).Process(format, frame); f.Channels != 2 || len(samples) != 2 {
+		t.Error("Unexpected downmix", f, samples)
+	} else if samples[0] != 1 || samples[1] <= 0 || samples[1] >= 0.5 {
+		t.Error("Unexpected downmix", samples)
+	}
+	if f, samples := (pcm.Downmix{Channels: 1}).Process(media.AudioFormat{Channels: 2}, []float32{1, 0, 0.5, 0.5}); f.Channels != 1 || len(samples) != 2 || samples[0] != 0.5 || samples[1] != 0.5 {
+		t.Error("Unexpected downmix", f, samples)
+	}
+
+	// Audio with fewer channels is unchanged
+	if f, samples := (pcm.Downmix{Channels: 2}).Process(media.AudioFormat{Channels: 1}, []float32{1}); f.Channels != 1 || len(samples) != 1 {
+		t.Error("Unexpected downmix", f, samples)
+	}
+}
+
+func Test_dsp_002(t *testing.T) {
+	format := media.AudioFormat{Format: media.SAMPLE_FORMAT_FLOAT32, Rate: 48000, Channels: 1}
+
+	// A low shelf boost of 6dB doubles the level of a constant signal,
+	// and a peak at 1kHz boosts a 1kHz sine wave but not a 10kHz one
+	if level := settle(pcm.BassBoost(6.0206), format, 0); math.Abs(level-2) > 0.01 {
+		t.Error("Unexpected level", level)
+	}
+	peak := pcm.NewEqualizer(pcm.Band{Type: pcm.BAND_PEAK, Frequency: 1000, Gain: -12, Q: 2})
+	if level := settle(peak, format, 1000); math.Abs(level-0.25) > 0.02 {
+		t.Error("Unexpected level", level)
+	}
+	if level := settle(peak, format, 10000); math.Abs(level-1) > 0.05 {
+		t.Error("Unexpected level", level)
+	}
+	if level := settle(pcm.NewEqualizer(pcm.Band{Type: pcm.BAND_HIGH_SHELF, Frequency: 4000, Gain: -6.0206}), format, 20000); math.Abs(level-0.5) > 0.02 {
+		t.Error("Unexpected level", level)
+	}
+
+	// Bands above half the sample rate are ignored
+	if level := settle(pcm.NewEqualizer(pcm.Band{Frequency: 30000, Gain: 12}), format, 1000); math.Abs(level-1) > 0.01 {
+		t.Error("Unexpected level", level)
+	}
+}
+
+func Test_dsp_003(t *testing.T) {
+	// The chain can be changed between calls
+	chain := pcm.NewChain(pcm.Downmix{Channels: 1})
+	format := media.AudioFormat{Format: media.SAMPLE_FORMAT_S16, Rate: 48000, Channels: 2}
+	if f, samples := chain.Process(format, []float32{1, 0}); f.Channels != 1 || samples[0] != 0.5 {
+		t.Error("Unexpected process", f, samples)
+	}
+	chain.Set()
+	if f, samples := chain.Process(format, []float32{1, 0}); f.Channels != 2 || len(samples) != 2 {
+		t.Error("Unexpected process", f, samples)
+	} else if len(chain.Processors()) != 0 {
+		t.Error("Unexpected processors", chain.Processors())
+	}
+}
+
+func Test_dsp_004(t *testing.T) {
+	// Six channels are written and downmixed to a stereo output, with
+	// a partial frame kept until the next write
+	output := &sink{format: media.AudioFormat{Format: media.SAMPLE_FORMAT_S16, Rate: 48000, Channels: 2}}
+	s := pcm.NewSink(output, pcm.NewChain(pcm.Downmix{Channels: 2}))
+	format, err := s.SetFormat(media.AudioFormat{Format: media.SAMPLE_FORMAT_FLOAT32, Rate: 48000, Channels: 6})
+	if err != nil {
+		t.Fatal(err)
+	} else if format.Channels != 6 || format.Format != media.SAMPLE_FORMAT_S16 {
+		t.Fatal("Unexpected format", format)
+	}
+	data, _ := pcm.Encode(format, []float32{0.5, -0.5, 0, 0, 0, 0, 0.25, 0.25, 0, 0, 0, 0})
+	if n, err := s.Write(data[:15]); err != nil || n != 15 {
+		t.Error("Unexpected write", n, err)
+	} else if n, err := s.Write(data[15:]); err != nil || n != 9 {
+		t.Error("Unexpected write", n, err)
+	}
+	if samples, err := pcm.Decode(output.format, output.data); err != nil {
+		t.Error(err)
+	} else if len(samples) != 4 || samples[0] <= 0 || samples[1] != -samples[0] || math.Abs(float64(samples[0]-2*samples[2])) > 1e-4 {
+		t.Error("Unexpected samples", samples)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// settle returns the peak level of a sine wave (or a constant when the
+// frequency is zero) after an equalizer has settled
+func settle(eq *pcm.Equalizer, format media.AudioFormat, frequency float64) float64 {
+	samples := make([]float32, format.Rate)
+	for i := range samples {
+		if frequency == 0 {
+			samples[i] = 0.25
+		} else {
+			samples[i] = 0.25 * float32(math.Sin(2*math.Pi*frequency*float64(i)/float64(format.Rate)))
+		}
+	}
+	_, samples = eq.Process(format, samples)
+	level := 0.0
+	for _, sample := range samples[len(samples)/2:] {
+		level = math.Max(level, math.Abs(float64(sample))/0.25)
+	}
+	return level
+}
+
+// sink collects audio in one format, which cannot be changed
+type sink struct {
+	format media.AudioFormat
+	data   []byte
+}
+
+func (this *sink) Close() error                  { return nil }
+func (this *sink) Subscribe() <-chan gopi.Event  { return nil }
+func (this *sink) Unsubscribe(<-chan gopi.Event) {}
+func (this *sink) Format() media.AudioFormat     { return this.format }
+func (this *sink) Drain() error                  { return nil }
+func (this *sink) Stop() error                   { return nil }
+func (this *sink) Write(data []byte) (int, error) {
+	this.data = append(this.data, data...)
+	return len(data), nil
+}
+func (this *sink) SetFormat(media.AudioFormat) (media.AudioFormat, error) { return this.format, nil }
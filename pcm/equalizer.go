@@ -0,0 +1,194 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package pcm
+
+import (
+	"fmt"
+	"math"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type BandType uint
+
+// Band is one band of a parametric equalizer, with a centre or corner
+// frequency in Hz and a gain in decibels. Q is the width of the band,
+// or 0.707 when zero.
+type Band struct {
+	Type      BandType
+	Frequency float64
+	Gain      float64
+	Q         float64
+}
+
+// Equalizer is a parametric equalizer, which filters each channel with
+// a biquad filter for each band
+type Equalizer struct {
+	bands   []Band
+	format  media.AudioFormat
+	filters [][]*biquad
+}
+
+// biquad is a filter for one band and channel, using the coefficients
+// from the Audio EQ Cookbook by Robert Bristow-Johnson
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	BAND_PEAK       BandType = iota // Boost or cut around the frequency
+	BAND_LOW_SHELF                  // Boost or cut below the frequency
+	BAND_HIGH_SHELF                 // Boost or cut above the frequency
+	BAND_MAX        = BAND_HIGH_SHELF
+)
+
+const (
+	DEFAULT_Q            = math.Sqrt2 / 2
+	BASS_BOOST_FREQUENCY = 100
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// EQUALIZER
+
+// NewEqualizer returns an equalizer with bands. Bands with a frequency
+// of zero or above half the sample rate are ignored.
+func NewEqualizer(bands ...Band) *Equalizer {
+	return &Equalizer{bands: append([]Band{}, bands...)}
+}
+
+// BassBoost returns an equalizer which boosts or cuts low frequencies
+// by a gain in decibels
+func BassBoost(gain float64) *Equalizer {
+	return NewEqualizer(Band{Type: BAND_LOW_SHELF, Frequency: BASS_BOOST_FREQUENCY, Gain: gain})
+}
+
+// Bands returns the bands of the equalizer
+func (this *Equalizer) Bands() []Band {
+	return append([]Band{}, this.bands...)
+}
+
+func (this *Equalizer) Process(format media.AudioFormat, samples []float32) (media.AudioFormat, []float32) {
+	if format != this.format {
+		this.format = format
+		this.filters = this.filtersFor(format)
+	}
+	if len(this.filters) == 0 {
+		return format, samples
+	}
+	channels := len(this.filters)
+	for i, sample := range samples {
+		value := float64(sample)
+		for _, filter := range this.filters[i%channels] {
+			value = filter.process(value)
+		}
+		samples[i] = float32(value)
+	}
+	return format, samples
+}
+
+func (this *Equalizer) Reset() {
+	for _, filters := range this.filters {
+		for _, filter := range filters {
+			filter.x1, filter.x2, filter.y1, filter.y2 = 0, 0, 0, 0
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (t BandType) String() string {
+	switch t {
+	case BAND_PEAK:
+		return "BAND_PEAK"
+	case BAND_LOW_SHELF:
+		return "BAND_LOW_SHELF"
+	case BAND_HIGH_SHELF:
+		return "BAND_HIGH_SHELF"
+	default:
+		return "[?? Invalid BandType value]"
+	}
+}
+
+func (b Band) String() string {
+	return fmt.Sprintf("<pcm.Band>{ type=%v frequency=%vHz gain=%vdB q=%v }", b.Type, b.Frequency, b.Gain, b.Q)
+}
+
+func (this *Equalizer) String() string {
+	return fmt.Sprintf("<pcm.Equalizer>{ bands=%v }", this.bands)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// filtersFor returns the filters for each channel, with one filter for
+// each band
+func (this *Equalizer) filtersFor(format media.AudioFormat) [][]*biquad {
+	filters := make([][]*biquad, format.Channels)
+	for channel := range filters {
+		for _, band := range this.bands {
+			if filter := newBiquad(band, float64(format.Rate)); filter != nil {
+				filters[channel] = append(filters[channel], filter)
+			}
+		}
+	}
+	return filters
+}
+
+func newBiquad(band Band, rate float64) *biquad {
+	if band.Frequency <= 0 || band.Frequency >= rate/2 || band.Type > BAND_MAX {
+		return nil
+	}
+	q := band.Q
+	if q <= 0 {
+		q = DEFAULT_Q
+	}
+	a := math.Pow(10, band.Gain/40)
+	w0 := 2 * math.Pi * band.Frequency / rate
+	cos, alpha := math.Cos(w0), math.Sin(w0)/(2*q)
+	shelf := 2 * math.Sqrt(a) * alpha
+
+	var b0, b1, b2, a0, a1, a2 float64
+	switch band.Type {
+	case BAND_PEAK:
+		b0, b1, b2 = 1+alpha*a, -2*cos, 1-alpha*a
+		a0, a1, a2 = 1+alpha/a, -2*cos, 1-alpha/a
+	case BAND_LOW_SHELF:
+		b0 = a * ((a + 1) - (a-1)*cos + shelf)
+		b1 = 2 * a * ((a - 1) - (a+1)*cos)
+		b2 = a * ((a + 1) - (a-1)*cos - shelf)
+		a0 = (a + 1) + (a-1)*cos + shelf
+		a1 = -2 * ((a - 1) + (a+1)*cos)
+		a2 = (a + 1) + (a-1)*cos - shelf
+	case BAND_HIGH_SHELF:
+		b0 = a * ((a + 1) + (a-1)*cos + shelf)
+		b1 = -2 * a * ((a - 1) + (a+1)*cos)
+		b2 = a * ((a + 1) + (a-1)*cos - shelf)
+		a0 = (a + 1) - (a-1)*cos + shelf
+		a1 = 2 * ((a - 1) - (a+1)*cos)
+		a2 = (a + 1) - (a-1)*cos - shelf
+	}
+	return &biquad{b0: b0 / a0, b1: b1 / a0, b2: b2 / a0, a1: a1 / a0, a2: a2 / a0}
+}
+
+func (this *biquad) process(x float64) float64 {
+	y := this.b0*x + this.b1*this.x1 + this.b2*this.x2 - this.a1*this.y1 - this.a2*this.y2
+	this.x2, this.x1 = this.x1, x
+	this.y2, this.y1 = this.y1, y
+	return y
+}
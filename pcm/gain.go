@@ -0,0 +1,121 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package pcm
+
+import (
+	"fmt"
+	"math"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Gain is a processor which multiplies samples by a linear factor
+type Gain float64
+
+type GainMode uint
+
+// Normalization selects the ReplayGain applied to each item, so that
+// tracks from across a library play at a similar loudness. Preamp is
+// added to the gain, and Default is used for items without a gain.
+// When PreventClipping is true, the gain is reduced so that the peak
+// of the track or album does not clip.
+type Normalization struct {
+	Mode            GainMode
+	Preamp, Default float64
+	PreventClipping bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	GAIN_MODE_NONE  GainMode = iota // Play at the original level
+	GAIN_MODE_TRACK                 // Use the track gain, or the album gain
+	GAIN_MODE_ALBUM                 // Use the album gain, or the track gain
+	GAIN_MODE_MAX   = GAIN_MODE_ALBUM
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GAIN
+
+// Decibels returns the gain for a number of decibels
+func Decibels(value float64) Gain {
+	return Gain(math.Pow(10, value/20))
+}
+
+// Decibels returns the gain in decibels
+func (g Gain) Decibels() float64 {
+	return 20 * math.Log10(float64(g))
+}
+
+func (g Gain) Process(format media.AudioFormat, samples []float32) (media.AudioFormat, []float32) {
+	if g != 1 {
+		for i := range samples {
+			samples[i] *= float32(g)
+		}
+	}
+	return format, samples
+}
+
+func (g Gain) Reset() {
+	// Gain has no state
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NORMALIZATION
+
+// GainFor returns the gain to apply when playing an item
+func (this Normalization) GainFor(item media.MediaItem) Gain {
+	if this.Mode == GAIN_MODE_NONE || this.Mode > GAIN_MODE_MAX {
+		return 1
+	}
+	value, peak := this.Default, 0.0
+	if g, exists := util.ReplayGainFor(item); exists {
+		if g.Album && (this.Mode == GAIN_MODE_ALBUM || g.Track == false) {
+			value, peak = g.AlbumGain, g.AlbumPeak
+		} else {
+			value, peak = g.TrackGain, g.TrackPeak
+		}
+	}
+	gain := Decibels(value + this.Preamp)
+	if this.PreventClipping && peak > 0 && float64(gain)*peak > 1 {
+		gain = Gain(1 / peak)
+	}
+	return gain
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (m GainMode) String() string {
+	switch m {
+	case GAIN_MODE_NONE:
+		return "GAIN_MODE_NONE"
+	case GAIN_MODE_TRACK:
+		return "GAIN_MODE_TRACK"
+	case GAIN_MODE_ALBUM:
+		return "GAIN_MODE_ALBUM"
+	default:
+		return "[?? Invalid GainMode value]"
+	}
+}
+
+func (g Gain) String() string {
+	return fmt.Sprintf("<pcm.Gain>{ %.2fdB }", g.Decibels())
+}
+
+func (this Normalization) String() string {
+	return fmt.Sprintf("<pcm.Normalization>{ mode=%v preamp=%vdB default=%vdB prevent_clipping=%v }", this.Mode, this.Preamp, this.Default, this.PreventClipping)
+}
@@ -0,0 +1,56 @@
+package pcm_test
+
+import (
+	"math"
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	pcm "github.com/djthorpe/gopi-media/pcm"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST GAIN
+
+func Test_gain_000(t *testing.T) {
+	t.Log("Test_gain_000")
+}
+
+func Test_gain_001(t *testing.T) {
+	if g := pcm.Decibels(-6.0206); math.Abs(float64(g)-0.5) > 1e-4 {
+		t.Error("Unexpected gain", g)
+	} else if db := pcm.Gain(2).Decibels(); math.Abs(db-6.0206) > 1e-4 {
+		t.Error("Unexpected decibels", db)
+	}
+	if _, samples := pcm.Gain(0.5).Process(media.AudioFormat{Channels: 1}, []float32{1, -0.5}); samples[0] != 0.5 || samples[1] != -0.25 {
+		t.Error("Unexpected samples", samples)
+	}
+}
+
+func Test_gain_002(t *testing.T) {
+	track := &mock.File{Path: "track.flac", Metadata: map[media.MetadataKey]string{
+		media.METADATA_KEY_TRACK_GAIN: "-6",
+		media.METADATA_KEY_TRACK_PEAK: "0.5",
+		media.METADATA_KEY_ALBUM_GAIN: "3",
+		media.METADATA_KEY_ALBUM_PEAK: "0.9",
+	}}
+	untagged := &mock.File{Path: "track.mp3"}
+	for _, test := range []struct {
+		normalization pcm.Normalization
+		item          media.MediaItem
+		expected      float64
+	}{
+		{pcm.Normalization{}, track, 0},
+		{pcm.Normalization{Mode: pcm.GAIN_MODE_TRACK}, track, -6},
+		{pcm.Normalization{Mode: pcm.GAIN_MODE_ALBUM}, track, 3},
+		{pcm.Normalization{Mode: pcm.GAIN_MODE_TRACK, Preamp: 4}, track, -2},
+		{pcm.Normalization{Mode: pcm.GAIN_MODE_TRACK, Preamp: 13, PreventClipping: true}, track, 6.0206},
+		{pcm.Normalization{Mode: pcm.GAIN_MODE_ALBUM, PreventClipping: true}, track, 0.9151},
+		{pcm.Normalization{Mode: pcm.GAIN_MODE_ALBUM, Default: -7, Preamp: 1}, untagged, -6},
+	} {
+		if gain := test.normalization.GainFor(test.item).Decibels(); math.Abs(gain-test.expected) > 1e-3 {
+			t.Error(test.normalization, "Unexpected gain", gain)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package pcm
+
+import (
+	"encoding/binary"
+	"math"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONVERSION
+
+// Decode returns interleaved PCM audio as samples between -1.0 and
+// 1.0, so that it can be mixed and processed before it is written to
+// an AudioSink. It returns gopi.ErrBadParameter if the format is unknown
+// or the data is not whole frames.
+func Decode(format media.AudioFormat, data []byte) ([]float32, error) {
+	size := int(format.Format.BytesPerSample())
+	if size == 0 || format.Channels == 0 || len(data)%int(format.BytesPerFrame()) != 0 {
+		return nil, gopi.ErrBadParameter
+	}
+	samples := make([]float32, len(data)/size)
+	for i := range samples {
+		sample := data[i*size : i*size+size]
+		switch format.Format {
+		case media.SAMPLE_FORMAT_U8:
+			samples[i] = float32(int(sample[0])-128) / 128
+		case media.SAMPLE_FORMAT_S16:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(sample))) / (1 << 15)
+		case media.SAMPLE_FORMAT_S24:
+			value := int32(uint32(sample[0])<<8|uint32(sample[1])<<16|uint32(sample[2])<<24) >> 8
+			samples[i] = float32(value) / (1 << 23)
+		case media.SAMPLE_FORMAT_S32:
+			samples[i] = float32(float64(int32(binary.LittleEndian.Uint32(sample))) / (1 << 31))
+		case media.SAMPLE_FORMAT_FLOAT32:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(sample))
+		}
+	}
+	return samples, nil
+}
+
+// Encode returns samples as interleaved PCM audio in a format, clipping
+// samples outside -1.0 to 1.0. It returns gopi.ErrBadParameter if the
+// format is unknown or the samples are not whole frames.
+func Encode(format media.AudioFormat, samples []float32) ([]byte, error) {
+	size := int(format.Format.BytesPerSample())
+	if size == 0 || format.Channels == 0 || len(samples)%int(format.Channels) != 0 {
+		return nil, gopi.ErrBadParameter
+	}
+	data := make([]byte, len(samples)*size)
+	for i, value := range samples {
+		sample := data[i*size : i*size+size]
+		switch format.Format {
+		case media.SAMPLE_FORMAT_U8:
+			sample[0] = uint8(scale(value, 7) + 128)
+		case media.SAMPLE_FORMAT_S16:
+			binary.LittleEndian.PutUint16(sample, uint16(int16(scale(value, 15))))
+		case media.SAMPLE_FORMAT_S24:
+			v := uint32(int32(scale(value, 23)))
+			sample[0], sample[1], sample[2] = uint8(v), uint8(v>>8), uint8(v>>16)
+		case media.SAMPLE_FORMAT_S32:
+			binary.LittleEndian.PutUint32(sample, uint32(int32(scale(value, 31))))
+		case media.SAMPLE_FORMAT_FLOAT32:
+			binary.LittleEndian.PutUint32(sample, math.Float32bits(clip(value)))
+		}
+	}
+	return data, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// clip returns a sample between -1.0 and 1.0
+func clip(value float32) float32 {
+	if value > 1 {
+		return 1
+	} else if value < -1 {
+		return -1
+	} else {
+		return value
+	}
+}
+
+// scale returns a sample as a signed integer with a number of bits
+// after the sign, clipped to the range of the integer
+func scale(value float32, bits uint) int64 {
+	max := int64(1)<<bits - 1
+	v := int64(math.Floor(float64(value)*float64(int64(1)<<bits) + 0.5))
+	if v > max {
+		return max
+	} else if v < -max-1 {
+		return -max - 1
+	} else {
+		return v
+	}
+}
@@ -0,0 +1,137 @@
+package pcm_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	pcm "github.com/djthorpe/gopi-media/pcm"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CONVERSION
+
+func Test_pcm_000(t *testing.T) {
+	t.Log("Test_pcm_000")
+}
+
+func Test_pcm_001(t *testing.T) {
+	samples := []float32{0, 0.5, -0.5, -1, 2, -2}
+	for format := media.SAMPLE_FORMAT_U8; format <= media.SAMPLE_FORMAT_MAX; format++ {
+		f := media.AudioFormat{Format: format, Rate: 44100, Channels: 2}
+		data, err := pcm.Encode(f, samples)
+		if err != nil {
+			t.Error(format, err)
+			continue
+		} else if len(data) != len(samples)*int(format.BytesPerSample()) {
+			t.Error(format, "Unexpected size", len(data))
+		}
+		decoded, err := pcm.Decode(f, data)
+		if err != nil {
+			t.Error(format, err)
+			continue
+		}
+		// Samples outside the range are clipped, and the maximum is one
+		// step below 1.0 for integer formats
+		for i, expected := range []float32{0, 0.5, -0.5, -1, 1, -1} {
+			if math.Abs(float64(decoded[i]-expected)) > 1.0/64 {
+				t.Error(format, "Unexpected sample", i, decoded[i])
+			}
+		}
+	}
+}
+
+func Test_pcm_002(t *testing.T) {
+	f := media.AudioFormat{Format: media.SAMPLE_FORMAT_S16, Rate: 48000, Channels: 2}
+	if data, err := pcm.Encode(f, []float32{-1, 0.5}); err != nil {
+		t.Error(err)
+	} else if data[0] != 0x00 || data[1] != 0x80 || data[2] != 0x00 || data[3] != 0x40 {
+		t.Error("Unexpected data", data)
+	}
+	if _, err := pcm.Decode(f, []byte{1, 2, 3}); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	} else if _, err := pcm.Encode(f, []float32{0}); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	} else if _, err := pcm.Decode(media.AudioFormat{Channels: 2}, nil); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter", err)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CROSSFADE
+
+func Test_crossfade_001(t *testing.T) {
+	fade := pcm.Crossfade{Duration: 2 * time.Second, Curve: pcm.CURVE_EQUAL_POWER}
+	if frames := fade.Frames(media.AudioFormat{Rate: 44100}); frames != 88200 {
+		t.Error("Unexpected frames", frames)
+	} else if frames := (pcm.Crossfade{}).Frames(media.AudioFormat{Rate: 44100}); frames != 0 {
+		t.Error("Unexpected frames", frames)
+	}
+	for curve := pcm.CURVE_LINEAR; curve <= pcm.CURVE_MAX; curve++ {
+		if out, in := curve.Gain(0); out != 1 || in != 0 {
+			t.Error(curve, "Unexpected gain", out, in)
+		} else if out, in := curve.Gain(1); out != 0 || in != 1 {
+			t.Error(curve, "Unexpected gain", out, in)
+		} else if out, in := curve.Gain(0.5); math.Abs(out-in) > 1e-9 {
+			t.Error(curve, "Unexpected gain", out, in)
+		}
+	}
+	if out, _ := pcm.CURVE_EQUAL_POWER.Gain(0.5); math.Abs(out-math.Sqrt2/2) > 1e-9 {
+		t.Error("Unexpected gain", out)
+	}
+}
+
+func Test_crossfade_002(t *testing.T) {
+	// Four stereo frames of the outgoing track fade into six of the
+	// incoming track
+	fade := pcm.Crossfade{Curve: pcm.CURVE_LINEAR}
+	from := []float32{1, 1, 1, 1, 1, 1, 1, 1}
+	to := []float32{-1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1, -1}
+	mix := fade.Mix(2, from, to)
+	expected := []float32{1, 1, 0.5, 0.5, 0, 0, -0.5, -0.5, -1, -1, -1, -1}
+	if len(mix) != len(expected) {
+		t.Fatal("Unexpected length", len(mix))
+	}
+	for i := range expected {
+		if mix[i] != expected[i] {
+			t.Error("Unexpected sample", i, mix[i])
+		}
+	}
+
+	// The incoming track is shorter than the fade
+	if mix := fade.Mix(2, from, to[:2]); len(mix) != len(from) || mix[0] != 1 || mix[2] != 0.75 {
+		t.Error("Unexpected mix", mix)
+	}
+}
+
+func Test_crossfade_003(t *testing.T) {
+	track := func(album, disc, number, gapless string) media.MediaItem {
+		return &mock.File{Path: "track.mp3", Metadata: map[media.MetadataKey]string{
+			media.METADATA_KEY_ALBUM:            album,
+			media.METADATA_KEY_DISC:             disc,
+			media.METADATA_KEY_TRACK:            number,
+			media.METADATA_KEY_GAPLESS_PLAYBACK: gapless,
+		}}
+	}
+	for _, test := range []struct {
+		from, to media.MediaItem
+		allowed  bool
+	}{
+		{track("Live", "1", "3/12", "1"), track("Live", "1", "4/12", ""), false},
+		{track("Live", "1", "3", ""), track("Live", "1", "4", ""), true},
+		{track("Live", "1", "3", "1"), track("Live", "1", "5", "1"), true},
+		{track("Live", "1", "12", "true"), track("Live", "2", "1", "true"), false},
+		{track("Live", "1", "3", "1"), track("Other", "1", "4", "1"), true},
+		{track("", "", "3", "1"), track("", "", "4", "1"), true},
+		{track("Live", "1", "3", "0"), track("Live", "1", "4", "0"), true},
+		{nil, track("Live", "1", "1", "1"), true},
+	} {
+		if allowed := pcm.Allowed(test.from, test.to); allowed != test.allowed {
+			t.Error("Unexpected allowed for", test.from, test.to, allowed)
+		}
+	}
+}
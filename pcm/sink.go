@@ -0,0 +1,138 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package pcm
+
+import (
+	"fmt"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Sink is an AudioSink which processes audio with a chain before it is
+// written to another sink, such as the ALSA output. The format written
+// to the sink can have more channels than the output when the chain
+// downmixes.
+type Sink struct {
+	media.AudioSink
+	chain *Chain
+
+	lock    sync.Mutex
+	format  media.AudioFormat
+	pending []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewSink returns a sink which processes audio with a chain and writes
+// it to another sink
+func NewSink(sink media.AudioSink, chain *Chain) *Sink {
+	return &Sink{AudioSink: sink, chain: chain, format: sink.Format()}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Chain returns the processors applied to the audio
+func (this *Sink) Chain() *Chain {
+	return this.chain
+}
+
+// SetFormat sets the format of the output to the format after
+// processing, and returns the format which should be written
+func (this *Sink) SetFormat(format media.AudioFormat) (media.AudioFormat, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	this.pending = this.pending[:0]
+	this.chain.Reset()
+	processed, _ := this.chain.Process(format, nil)
+	nearest, err := this.AudioSink.SetFormat(processed)
+	if err != nil {
+		return media.AudioFormat{}, err
+	}
+
+	// Keep the channels when the output accepts the processed channels
+	this.format = nearest
+	input := format
+	input.Format, input.Rate = nearest.Format, nearest.Rate
+	if processed, _ := this.chain.Process(input, nil); processed == nearest {
+		this.format = input
+	}
+	return this.format, nil
+}
+
+func (this *Sink) Format() media.AudioFormat {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.format
+}
+
+func (this *Sink) Write(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Process whole frames and keep the remainder
+	n := len(data)
+	size := int(this.format.BytesPerFrame())
+	if size == 0 {
+		return 0, gopi.ErrOutOfOrder
+	}
+	data = append(this.pending, data...)
+	whole := len(data) - len(data)%size
+	samples, err := Decode(this.format, data[:whole])
+	if err != nil {
+		return 0, err
+	}
+	format, samples := this.chain.Process(this.format, samples)
+	if format.Channels != this.AudioSink.Format().Channels {
+		return 0, gopi.ErrUnexpectedResponse
+	} else if output, err := Encode(this.AudioSink.Format(), samples); err != nil {
+		return 0, err
+	} else if _, err := this.AudioSink.Write(output); err != nil {
+		return 0, err
+	}
+	this.pending = append(this.pending[:0], data[whole:]...)
+
+	// Success
+	return n, nil
+}
+
+func (this *Sink) Drain() error {
+	this.lock.Lock()
+	this.pending = this.pending[:0]
+	this.lock.Unlock()
+	return this.AudioSink.Drain()
+}
+
+func (this *Sink) Stop() error {
+	// Stop without the lock so a blocked write returns
+	if err := this.AudioSink.Stop(); err != nil {
+		return err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.pending = this.pending[:0]
+	this.chain.Reset()
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Sink) String() string {
+	return fmt.Sprintf("<pcm.Sink>{ format=%v chain=%v sink=%v }", this.Format(), this.chain, this.AudioSink)
+}
@@ -0,0 +1,335 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package podcast
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Feed is a podcast, which is read from an RSS or Atom feed
+type Feed struct {
+	URL         string    `json:"url"`
+	Title       string    `json:"title,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Image       string    `json:"image,omitempty"`
+	Episodes    []Episode `json:"episodes,omitempty"`
+}
+
+// Episode is an item in a podcast feed with an audio or video enclosure
+type Episode struct {
+	Guid        string        `json:"guid"`
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Published   time.Time     `json:"published,omitempty"`
+	Duration    time.Duration `json:"duration,omitempty"`
+	Season      uint          `json:"season,omitempty"`
+	Episode     uint          `json:"episode,omitempty"`
+
+	// Enclosure URL, mimetype and length in bytes
+	URL      string `json:"url"`
+	MimeType string `json:"mimetype,omitempty"`
+	Length   int64  `json:"length,omitempty"`
+
+	// Path is the downloaded file, or empty if the episode has not
+	// been downloaded, and Listened is true once the episode has
+	// been played
+	Path     string `json:"path,omitempty"`
+	Listened bool   `json:"listened,omitempty"`
+}
+
+type rssImage struct {
+	URL  string `xml:"url"`
+	Href string `xml:"href,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type rssItem struct {
+	Guid        string       `xml:"guid"`
+	Title       string       `xml:"title"`
+	Description string       `xml:"description"`
+	Summary     string       `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	PubDate     string       `xml:"pubDate"`
+	Duration    string       `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	Season      string       `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	Episode     string       `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	Enclosure   rssEnclosure `xml:"enclosure"`
+}
+
+type rssChannel struct {
+	Title       string     `xml:"title"`
+	Description string     `xml:"description"`
+	Author      string     `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	Images      []rssImage `xml:"image"`
+	Items       []rssItem  `xml:"item"`
+}
+
+type rssDocument struct {
+	Channel rssChannel `xml:"channel"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+type atomEntry struct {
+	Id        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Summary   string     `xml:"summary"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Links     []atomLink `xml:"link"`
+}
+
+type atomDocument struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Author   string      `xml:"author>name"`
+	Logo     string      `xml:"logo"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+var (
+	rssTimeFormats = []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"Mon, 2 Jan 2006 15:04:05 MST",
+		"2 Jan 2006 15:04:05 -0700",
+		time.RFC3339,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// ReadFeed reads an RSS 2.0 or Atom feed. Items without an audio or
+// video enclosure are ignored, and items without a guid are identified
+// by the enclosure URL.
+func ReadFeed(r io.Reader) (*Feed, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "rss":
+				doc := new(rssDocument)
+				if err := dec.DecodeElement(doc, &start); err != nil {
+					return nil, err
+				}
+				return doc.feed(), nil
+			case "feed":
+				doc := new(atomDocument)
+				if err := dec.DecodeElement(doc, &start); err != nil {
+					return nil, err
+				}
+				return doc.feed(), nil
+			default:
+				return nil, gopi.ErrBadParameter
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METHODS
+
+// Episode returns an episode in the feed by guid
+func (this *Feed) Episode(guid string) (*Episode, bool) {
+	for i := range this.Episodes {
+		if this.Episodes[i].Guid == guid {
+			return &this.Episodes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Merge updates the feed from a newer copy, keeping the download and
+// listened state for episodes which are in both
+func (this *Feed) Merge(other *Feed) {
+	episodes := make([]Episode, 0, len(other.Episodes))
+	for _, episode := range other.Episodes {
+		if existing, exists := this.Episode(episode.Guid); exists {
+			episode.Path = existing.Path
+			episode.Listened = existing.Listened
+		}
+		episodes = append(episodes, episode)
+	}
+
+	// Keep downloaded episodes which are no longer in the feed
+	for _, episode := range this.Episodes {
+		if _, exists := other.Episode(episode.Guid); exists == false && episode.Path != "" {
+			episodes = append(episodes, episode)
+		}
+	}
+
+	url := this.URL
+	*this = *other
+	this.URL = url
+	this.Episodes = episodes
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Feed) String() string {
+	return fmt.Sprintf("<podcast.Feed>{ url=%v title=%v episodes=%v }", strconv.Quote(this.URL), strconv.Quote(this.Title), len(this.Episodes))
+}
+
+func (this Episode) String() string {
+	return fmt.Sprintf("<podcast.Episode>{ guid=%v title=%v published=%v url=%v }", strconv.Quote(this.Guid), strconv.Quote(this.Title), this.Published.Format(time.RFC3339), strconv.Quote(this.URL))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *rssDocument) feed() *Feed {
+	channel := this.Channel
+	feed := &Feed{
+		Title:       strings.TrimSpace(channel.Title),
+		Author:      strings.TrimSpace(channel.Author),
+		Description: strings.TrimSpace(channel.Description),
+		Episodes:    make([]Episode, 0, len(channel.Items)),
+	}
+	for _, image := range channel.Images {
+		if image.Href != "" {
+			feed.Image = strings.TrimSpace(image.Href)
+		} else if image.URL != "" && feed.Image == "" {
+			feed.Image = strings.TrimSpace(image.URL)
+		}
+	}
+	for _, item := range channel.Items {
+		episode := Episode{
+			Guid:        strings.TrimSpace(item.Guid),
+			Title:       strings.TrimSpace(item.Title),
+			Description: strings.TrimSpace(item.Description),
+			Published:   parseTime(item.PubDate),
+			Duration:    parseDuration(item.Duration),
+			Season:      parseUint(item.Season),
+			Episode:     parseUint(item.Episode),
+			URL:         strings.TrimSpace(item.Enclosure.URL),
+			MimeType:    item.Enclosure.Type,
+		}
+		if episode.Description == "" {
+			episode.Description = strings.TrimSpace(item.Summary)
+		}
+		episode.Length, _ = strconv.ParseInt(strings.TrimSpace(item.Enclosure.Length), 10, 64)
+		if episode.valid() {
+			feed.Episodes = append(feed.Episodes, episode)
+		}
+	}
+	return feed
+}
+
+func (this *atomDocument) feed() *Feed {
+	feed := &Feed{
+		Title:       strings.TrimSpace(this.Title),
+		Author:      strings.TrimSpace(this.Author),
+		Description: strings.TrimSpace(this.Subtitle),
+		Image:       strings.TrimSpace(this.Logo),
+		Episodes:    make([]Episode, 0, len(this.Entries)),
+	}
+	for _, entry := range this.Entries {
+		episode := Episode{
+			Guid:        strings.TrimSpace(entry.Id),
+			Title:       strings.TrimSpace(entry.Title),
+			Description: strings.TrimSpace(entry.Summary),
+			Published:   parseTime(entry.Published),
+		}
+		if episode.Published.IsZero() {
+			episode.Published = parseTime(entry.Updated)
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "enclosure" {
+				episode.URL = strings.TrimSpace(link.Href)
+				episode.MimeType = link.Type
+				episode.Length, _ = strconv.ParseInt(strings.TrimSpace(link.Length), 10, 64)
+				break
+			}
+		}
+		if episode.valid() {
+			feed.Episodes = append(feed.Episodes, episode)
+		}
+	}
+	return feed
+}
+
+// valid returns true if an episode has an audio or video enclosure,
+// setting the guid from the URL when there isn't one
+func (this *Episode) valid() bool {
+	if this.URL == "" {
+		return false
+	}
+	if this.MimeType != "" && strings.HasPrefix(this.MimeType, "audio/") == false && strings.HasPrefix(this.MimeType, "video/") == false {
+		return false
+	}
+	if this.Guid == "" {
+		this.Guid = this.URL
+	}
+	return true
+}
+
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, format := range rssTimeFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseDuration parses an itunes:duration value, which is either a
+// number of seconds or in the form [HH:]MM:SS
+func parseDuration(value string) time.Duration {
+	var seconds uint64
+	for _, part := range strings.Split(strings.TrimSpace(value), ":") {
+		if n, err := strconv.ParseUint(part, 10, 32); err != nil {
+			return 0
+		} else {
+			seconds = seconds*60 + n
+		}
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func parseUint(value string) uint {
+	if n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32); err != nil {
+		return 0
+	} else {
+		return uint(n)
+	}
+}
@@ -0,0 +1,433 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package podcast
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+	util "github.com/djthorpe/gopi-media/util"
+	errors "github.com/djthorpe/gopi/util/errors"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Subscriptions is a set of podcast feeds with the download and
+// listened state of each episode. The zero value has no subscriptions
+// and is safe for concurrent use.
+type Subscriptions struct {
+	lock  sync.Mutex
+	feeds []*Feed
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Extension for partially downloaded episodes
+	PODCAST_PARTIAL_EXT = ".part"
+
+	// Maximum size of a feed
+	PODCAST_MAXSIZE = 16 * 1024 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// READ AND WRITE
+
+// ReadFile reads subscriptions from a file, returning gopi.ErrNotFound
+// if the file does not exist
+func ReadFile(filename string) (*Subscriptions, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return Read(fh)
+	}
+}
+
+// Read reads subscriptions which were written with Write
+func Read(r io.Reader) (*Subscriptions, error) {
+	this := new(Subscriptions)
+	if err := json.NewDecoder(r).Decode(&this.feeds); err != nil {
+		return nil, err
+	}
+	return this, nil
+}
+
+// Write writes the subscriptions as JSON
+func (this *Subscriptions) Write(w io.Writer) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(this.feeds)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// SUBSCRIBE
+
+// Subscribe adds a feed URL, which has no episodes until it is
+// refreshed. Returns gopi.ErrNotModified if the feed is already
+// subscribed.
+func (this *Subscriptions) Subscribe(feed string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if u, err := url.Parse(feed); err != nil || u.IsAbs() == false {
+		return gopi.ErrBadParameter
+	} else if this.feedLocked(feed) != nil {
+		return gopi.ErrNotModified
+	}
+	this.feeds = append(this.feeds, &Feed{URL: feed})
+	return nil
+}
+
+// Unsubscribe removes a feed, returning gopi.ErrNotFound if it is
+// not subscribed. Downloaded episodes are not removed.
+func (this *Subscriptions) Unsubscribe(feed string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for i, other := range this.feeds {
+		if other.URL == feed {
+			this.feeds = append(this.feeds[:i], this.feeds[i+1:]...)
+			return nil
+		}
+	}
+	return gopi.ErrNotFound
+}
+
+// Feeds returns a copy of the subscribed feeds
+func (this *Subscriptions) Feeds() []Feed {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	feeds := make([]Feed, len(this.feeds))
+	for i, feed := range this.feeds {
+		feeds[i] = *feed
+		feeds[i].Episodes = append([]Episode{}, feed.Episodes...)
+	}
+	return feeds
+}
+
+// SetListened sets the listened state for an episode
+func (this *Subscriptions) SetListened(feed, guid string, listened bool) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if f := this.feedLocked(feed); f == nil {
+		return gopi.ErrNotFound
+	} else if episode, exists := f.Episode(guid); exists == false {
+		return gopi.ErrNotFound
+	} else {
+		episode.Listened = listened
+		return nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// REFRESH
+
+// Refresh fetches every subscribed feed and merges in new episodes,
+// returning the episodes which were not in each feed before, keyed
+// by feed URL
+func (this *Subscriptions) Refresh(client *http.Client) (map[string][]Episode, error) {
+	var errs errors.CompoundError
+	added := make(map[string][]Episode)
+	for _, feed := range this.Feeds() {
+		if updated, err := Fetch(client, feed.URL); err != nil {
+			errs.Add(fmt.Errorf("%v: %v", feed.URL, err))
+		} else if episodes := this.merge(feed.URL, updated); len(episodes) > 0 {
+			added[feed.URL] = episodes
+		}
+	}
+	return added, errs.ErrorOrSelf()
+}
+
+// Run refreshes the feeds at an interval until stop is closed, and
+// downloads new episodes into a folder when the folder is not empty.
+// Refreshes are skipped while util.DefaultNetwork is offline.
+func (this *Subscriptions) Run(client *http.Client, interval time.Duration, folder string, log gopi.Logger, stop <-chan struct{}) error {
+	if interval <= 0 {
+		return gopi.ErrBadParameter
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if util.DefaultNetwork.Skip("podcast refresh") == false {
+			this.run(client, folder, log)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// run refreshes the feeds once and downloads new episodes
+func (this *Subscriptions) run(client *http.Client, folder string, log gopi.Logger) {
+	added, err := this.Refresh(client)
+	if err != nil {
+		log.Warn("%v", err)
+	}
+	for feed, episodes := range added {
+		for _, episode := range episodes {
+			if folder == "" {
+				log.Info("New episode %v", episode)
+			} else if path, err := this.Download(client, feed, episode.Guid, folder); err != nil {
+				log.Error("%v: %v", episode.URL, err)
+			} else {
+				log.Info("Downloaded %v", path)
+			}
+		}
+	}
+}
+
+// Fetch reads a feed from a URL, with util.BackgroundClient when
+// client is nil
+func Fetch(client *http.Client, feed string) (*Feed, error) {
+	if client == nil {
+		client = util.BackgroundClient
+	}
+	resp, err := client.Get(feed)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%v: %v", feed, resp.Status)
+	}
+	if f, err := ReadFeed(io.LimitReader(resp.Body, PODCAST_MAXSIZE)); err != nil {
+		return nil, err
+	} else {
+		f.URL = feed
+		return f, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DOWNLOAD AND STREAM
+
+// Download writes an episode into a folder for the show, with NFO
+// sidecars for the show and episode so the ffmpeg driver indexes it
+// as a TV episode, and returns the path to the episode. It uses
+// util.BackgroundClient when client is nil.
+func (this *Subscriptions) Download(client *http.Client, feed, guid, folder string) (string, error) {
+	if client == nil {
+		client = util.BackgroundClient
+	}
+	this.lock.Lock()
+	f := this.feedLocked(feed)
+	if f == nil {
+		this.lock.Unlock()
+		return "", gopi.ErrNotFound
+	}
+	episode, exists := f.Episode(guid)
+	if exists == false {
+		this.lock.Unlock()
+		return "", gopi.ErrNotFound
+	}
+	show, e := *f, *episode
+	this.lock.Unlock()
+
+	// Create the folder and show sidecar
+	folder = filepath.Join(folder, sanitize(show.Title, "Podcast"))
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", err
+	} else if err := writeShowSidecar(folder, &show); err != nil {
+		return "", err
+	}
+
+	// Download the episode
+	filename := filepath.Join(folder, Filename(e))
+	if fh, err := os.Create(filename + PODCAST_PARTIAL_EXT); err != nil {
+		return "", err
+	} else if err := Stream(client, e, fh); err != nil {
+		fh.Close()
+		os.Remove(filename + PODCAST_PARTIAL_EXT)
+		return "", err
+	} else if err := fh.Close(); err != nil {
+		os.Remove(filename + PODCAST_PARTIAL_EXT)
+		return "", err
+	} else if err := os.Rename(filename+PODCAST_PARTIAL_EXT, filename); err != nil {
+		return "", err
+	} else if err := writeEpisodeSidecar(filename, &show, &e); err != nil {
+		return "", err
+	}
+
+	// Record the path
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if f := this.feedLocked(feed); f != nil {
+		if episode, exists := f.Episode(guid); exists {
+			episode.Path = filename
+		}
+	}
+
+	// Success
+	return filename, nil
+}
+
+// Stream writes the enclosure for an episode to w, with
+// util.DefaultClient when client is nil
+func Stream(client *http.Client, episode Episode, w io.Writer) error {
+	if client == nil {
+		client = util.DefaultClient
+	}
+	resp, err := client.Get(episode.URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v: %v", episode.URL, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Filename returns the filename for a downloaded episode, which is the
+// date it was published followed by the title, with an extension from
+// the enclosure URL or mimetype
+func Filename(episode Episode) string {
+	name := sanitize(episode.Title, "Episode")
+	if episode.Published.IsZero() == false {
+		name = episode.Published.Format("2006-01-02") + " " + name
+	}
+	ext := ""
+	if u, err := url.Parse(episode.URL); err == nil {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+	if ext == "" && episode.MimeType != "" {
+		if exts, err := mime.ExtensionsByType(episode.MimeType); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	}
+	return name + ext
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Subscriptions) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	return fmt.Sprintf("<podcast.Subscriptions>{ feeds=%v }", len(this.feeds))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Subscriptions) feedLocked(feed string) *Feed {
+	for _, f := range this.feeds {
+		if f.URL == feed {
+			return f
+		}
+	}
+	return nil
+}
+
+// merge updates a feed and returns the episodes which were added
+func (this *Subscriptions) merge(feed string, updated *Feed) []Episode {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	f := this.feedLocked(feed)
+	if f == nil {
+		return nil
+	}
+	added := make([]Episode, 0)
+	for _, episode := range updated.Episodes {
+		if _, exists := f.Episode(episode.Guid); exists == false {
+			added = append(added, episode)
+		}
+	}
+	f.Merge(updated)
+	return added
+}
+
+func writeShowSidecar(folder string, feed *Feed) error {
+	path := filepath.Join(folder, nfo.NFO_TVSHOW)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	doc := &nfo.Document{
+		XMLName: xml.Name{Local: nfo.ROOT_TVSHOW},
+		Title:   feed.Title,
+		Plot:    feed.Description,
+	}
+	if feed.Author != "" {
+		doc.Studios = []string{feed.Author}
+	}
+	return writeSidecar(path, doc)
+}
+
+func writeEpisodeSidecar(filename string, feed *Feed, episode *Episode) error {
+	doc := &nfo.Document{
+		XMLName:   xml.Name{Local: nfo.ROOT_EPISODE},
+		Title:     episode.Title,
+		ShowTitle: feed.Title,
+		Plot:      episode.Description,
+	}
+	if episode.Published.IsZero() == false {
+		doc.Aired = episode.Published.Format("2006-01-02")
+	}
+	if episode.Season != 0 {
+		doc.Season = fmt.Sprint(episode.Season)
+	}
+	if episode.Episode != 0 {
+		doc.Episode = fmt.Sprint(episode.Episode)
+	}
+	return writeSidecar(strings.TrimSuffix(filename, filepath.Ext(filename))+nfo.NFO_EXT, doc)
+}
+
+func writeSidecar(path string, doc *nfo.Document) error {
+	if fh, err := os.Create(path); err != nil {
+		return err
+	} else {
+		defer fh.Close()
+		return doc.Write(fh)
+	}
+}
+
+// sanitize removes characters which are not allowed in filenames, and
+// returns empty for names which would refer to a parent folder
+func sanitize(name, empty string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if strings.Trim(name, ".") == "" {
+		return empty
+	}
+	return name
+}
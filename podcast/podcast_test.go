@@ -0,0 +1,219 @@
+package podcast_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+	podcast "github.com/djthorpe/gopi-media/podcast"
+)
+
+const RSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+<channel>
+	<title>The Example Show</title>
+	<description>A show about examples</description>
+	<itunes:author>Example Media</itunes:author>
+	<itunes:image href="http://example.com/show.jpg"/>
+	<item>
+		<title>Second Episode</title>
+		<guid>ep2</guid>
+		<pubDate>Tue, 4 Jun 2019 09:00:00 +0000</pubDate>
+		<itunes:summary>More examples</itunes:summary>
+		<itunes:duration>1:02:03</itunes:duration>
+		<itunes:season>1</itunes:season>
+		<itunes:episode>2</itunes:episode>
+		<enclosure url="http://example.com/ep2.mp3" length="1234" type="audio/mpeg"/>
+	</item>
+	<item>
+		<title>Show notes only</title>
+		<guid>notes</guid>
+	</item>
+	<item>
+		<title>First Episode</title>
+		<pubDate>Tue, 28 May 2019 09:00:00 GMT</pubDate>
+		<itunes:duration>754</itunes:duration>
+		<enclosure url="http://example.com/ep1.mp3" type="audio/mpeg"/>
+	</item>
+</channel>
+</rss>`
+
+const ATOM = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Atom Show</title>
+	<author><name>Someone</name></author>
+	<entry>
+		<id>urn:atom:1</id>
+		<title>Atom Episode</title>
+		<updated>2019-06-01T12:00:00Z</updated>
+		<link rel="alternate" href="http://example.com/atom/1"/>
+		<link rel="enclosure" href="http://example.com/atom/1.m4a" type="audio/mp4" length="99"/>
+	</entry>
+</feed>`
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST FEEDS
+
+func Test_podcast_000(t *testing.T) {
+	t.Log("Test_podcast_000")
+}
+
+func Test_podcast_001(t *testing.T) {
+	feed, err := podcast.ReadFeed(strings.NewReader(RSS))
+	if err != nil {
+		t.Fatal(err)
+	} else if feed.Title != "The Example Show" || feed.Author != "Example Media" || feed.Image != "http://example.com/show.jpg" {
+		t.Error("Unexpected feed", feed)
+	} else if len(feed.Episodes) != 2 {
+		t.Fatal("Unexpected episodes", feed.Episodes)
+	}
+	if e := feed.Episodes[0]; e.Guid != "ep2" || e.Description != "More examples" || e.Season != 1 || e.Episode != 2 || e.Length != 1234 {
+		t.Error("Unexpected episode", e)
+	} else if e.Duration != time.Hour+2*time.Minute+3*time.Second || e.Published.Format(time.RFC3339) != "2019-06-04T09:00:00Z" {
+		t.Error("Unexpected episode", e.Duration, e.Published)
+	}
+	if e := feed.Episodes[1]; e.Guid != "http://example.com/ep1.mp3" || e.Duration != 754*time.Second || e.Published.IsZero() {
+		t.Error("Unexpected episode", e)
+	}
+}
+
+func Test_podcast_002(t *testing.T) {
+	feed, err := podcast.ReadFeed(strings.NewReader(ATOM))
+	if err != nil {
+		t.Fatal(err)
+	} else if feed.Title != "Atom Show" || feed.Author != "Someone" || len(feed.Episodes) != 1 {
+		t.Fatal("Unexpected feed", feed)
+	} else if e := feed.Episodes[0]; e.Guid != "urn:atom:1" || e.URL != "http://example.com/atom/1.m4a" || e.Length != 99 || e.Published.IsZero() {
+		t.Error("Unexpected episode", e)
+	} else if name := podcast.Filename(e); name != "2019-06-01 Atom Episode.m4a" {
+		t.Error("Unexpected filename", name)
+	}
+	if _, err := podcast.ReadFeed(strings.NewReader("<html></html>")); err == nil {
+		t.Error("Expected error for unsupported feed")
+	}
+}
+
+func Test_podcast_003(t *testing.T) {
+	older, _ := podcast.ReadFeed(strings.NewReader(RSS))
+	older.Episodes[0].Listened = true
+	older.Episodes[1].Path = "/tmp/ep1.mp3"
+	newer, _ := podcast.ReadFeed(strings.NewReader(RSS))
+	newer.Episodes = newer.Episodes[:1]
+	older.Merge(newer)
+	if len(older.Episodes) != 2 {
+		t.Error("Expected downloaded episode to be kept", older.Episodes)
+	} else if older.Episodes[0].Listened == false || older.Episodes[1].Path != "/tmp/ep1.mp3" {
+		t.Error("Unexpected state", older.Episodes)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST SUBSCRIPTIONS
+
+func Test_podcast_004(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed" {
+			w.Write([]byte(strings.Replace(RSS, "http://example.com/", "http://"+r.Host+"/", -1)))
+		} else if r.URL.Path == "/ep2.mp3" {
+			w.Write([]byte("audio"))
+		} else {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "podcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	subscriptions := new(podcast.Subscriptions)
+	if err := subscriptions.Subscribe(server.URL + "/feed"); err != nil {
+		t.Fatal(err)
+	} else if err := subscriptions.Subscribe(server.URL + "/feed"); err == nil {
+		t.Error("Expected error for duplicate subscription")
+	}
+	if added, err := subscriptions.Refresh(server.Client()); err != nil {
+		t.Fatal(err)
+	} else if len(added[server.URL+"/feed"]) != 2 {
+		t.Error("Unexpected episodes", added)
+	}
+	if added, err := subscriptions.Refresh(server.Client()); err != nil {
+		t.Error(err)
+	} else if len(added) != 0 {
+		t.Error("Unexpected episodes", added)
+	}
+
+	// Download episode and check the sidecars
+	path, err := subscriptions.Download(server.Client(), server.URL+"/feed", "ep2", dir)
+	if err != nil {
+		t.Fatal(err)
+	} else if path != filepath.Join(dir, "The Example Show", "2019-06-04 Second Episode.mp3") {
+		t.Error("Unexpected path", path)
+	} else if data, err := ioutil.ReadFile(path); err != nil || string(data) != "audio" {
+		t.Error("Unexpected data", string(data), err)
+	}
+	if doc, err := nfo.ReadFile(strings.TrimSuffix(path, ".mp3") + nfo.NFO_EXT); err != nil {
+		t.Error(err)
+	} else if doc.ShowTitle != "The Example Show" || doc.Season != "1" || doc.Episode != "2" || doc.Aired != "2019-06-04" {
+		t.Error("Unexpected sidecar", doc)
+	}
+	if doc, err := nfo.ReadFile(filepath.Join(dir, "The Example Show", nfo.NFO_TVSHOW)); err != nil {
+		t.Error(err)
+	} else if doc.Title != "The Example Show" {
+		t.Error("Unexpected sidecar", doc)
+	}
+
+	// Missing enclosure
+	if _, err := subscriptions.Download(server.Client(), server.URL+"/feed", "http://"+server.Listener.Addr().String()+"/ep1.mp3", dir); err == nil {
+		t.Error("Expected error for missing enclosure")
+	}
+
+	// Listened state is written and read back
+	if err := subscriptions.SetListened(server.URL+"/feed", "ep2", true); err != nil {
+		t.Error(err)
+	}
+	buf := new(strings.Builder)
+	if err := subscriptions.Write(buf); err != nil {
+		t.Fatal(err)
+	} else if other, err := podcast.Read(strings.NewReader(buf.String())); err != nil {
+		t.Error(err)
+	} else if feeds := other.Feeds(); len(feeds) != 1 || feeds[0].Episodes[0].Listened == false || feeds[0].Episodes[0].Path != path {
+		t.Error("Unexpected feeds", feeds)
+	}
+}
+
+func Test_podcast_005(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(RSS))
+	}))
+	defer server.Close()
+
+	// Default client
+	if feed, err := podcast.Fetch(nil, server.URL); err != nil {
+		t.Error(err)
+	} else if feed.Title != "The Example Show" {
+		t.Error("Unexpected feed", feed)
+	}
+
+	// Names which refer to a parent folder
+	for _, title := range []string{".", "..", " ... "} {
+		if name := podcast.Filename(podcast.Episode{Title: title, URL: "http://example.com/ep.mp3"}); name != "Episode.mp3" {
+			t.Error("Unexpected filename", name)
+		}
+	}
+
+	// Interval must be set
+	if err := new(podcast.Subscriptions).Run(nil, 0, "", nil, nil); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter, got", err)
+	}
+}
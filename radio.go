@@ -0,0 +1,59 @@
+/*
+	Go Language Raspberry Pi Interface
+	(c) Copyright David Thorpe 2019
+	All Rights Reserved
+	For Licensing and Usage information, please see LICENSE.md
+*/
+
+package media
+
+import (
+	"io"
+
+	// Frameworks
+	"github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Radio manages a list of internet radio stations, and emits a
+// RadioEvent whenever the title playing on a station changes
+type Radio interface {
+	gopi.Driver
+	gopi.Publisher
+
+	// Return the stations in the list
+	Stations() []RadioStation
+
+	// Add a station to the list, replacing any station with the
+	// same identifier, and remove a station from the list
+	AddStation(RadioStation) error
+	RemoveStation(RadioStation) error
+
+	// Write the audio stream for a station to w, until stop is closed
+	Stream(station RadioStation, w io.Writer, stop <-chan struct{}) error
+}
+
+// RadioStation is an internet radio station. The stream URL is returned
+// with METADATA_KEY_STREAM_URL
+type RadioStation interface {
+	MediaItem
+
+	// Return the URL for the audio stream
+	URL() string
+
+	// Return the URL for the station logo, or an empty string
+	Logo() string
+}
+
+// RadioEvent is emitted when the title playing on a station changes
+type RadioEvent interface {
+	gopi.Event
+
+	// Return the station
+	Station() RadioStation
+
+	// Return the title now playing
+	NowPlaying() string
+}
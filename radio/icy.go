@@ -0,0 +1,159 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package radio
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// MetadataReader removes the ICY metadata which is interleaved with the
+// audio data in a SHOUTcast or Icecast stream, and calls a function
+// whenever the title changes
+type MetadataReader struct {
+	r         io.Reader
+	interval  int
+	remaining int
+	title     string
+	changed   func(string)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Request header to ask for metadata, and the response header
+	// with the metadata interval
+	ICY_REQUEST_HEADER  = "Icy-MetaData"
+	ICY_INTERVAL_HEADER = "Icy-Metaint"
+
+	// Metadata key for the title
+	ICY_STREAM_TITLE = "StreamTitle"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewMetadataReader returns a reader for a stream with metadata every
+// interval bytes. When the interval is zero the stream has no
+// metadata and is returned unchanged. The changed function is called
+// with each new title and can be nil.
+func NewMetadataReader(r io.Reader, interval int, changed func(string)) *MetadataReader {
+	return &MetadataReader{
+		r:         r,
+		interval:  interval,
+		remaining: interval,
+		changed:   changed,
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// Read reads audio data, reading and removing metadata blocks
+func (this *MetadataReader) Read(data []byte) (int, error) {
+	if this.interval <= 0 {
+		return this.r.Read(data)
+	}
+	if this.remaining == 0 {
+		if err := this.readMetadata(); err != nil {
+			return 0, err
+		}
+		this.remaining = this.interval
+	}
+	if len(data) > this.remaining {
+		data = data[:this.remaining]
+	}
+	n, err := this.r.Read(data)
+	this.remaining -= n
+	return n, err
+}
+
+// Title returns the most recent title, or an empty string
+func (this *MetadataReader) Title() string {
+	return this.title
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// METADATA
+
+// ParseMetadata returns the values in a metadata block, which are
+// in the form key='value'; and padded with zero bytes. Values which
+// are not valid UTF-8 are decoded as ISO-8859-1.
+func ParseMetadata(block []byte) map[string]string {
+	str := strings.TrimRight(string(block), "\x00")
+	values := make(map[string]string)
+	for str != "" {
+		i := strings.Index(str, "='")
+		if i <= 0 {
+			break
+		}
+		key, rest := strings.TrimSpace(str[:i]), str[i+2:]
+		if j := strings.Index(rest, "';"); j < 0 {
+			values[key] = decodeText(strings.TrimSuffix(rest, "'"))
+			break
+		} else {
+			values[key] = decodeText(rest[:j])
+			str = rest[j+2:]
+		}
+	}
+	return values
+}
+
+// ParseInterval returns the metadata interval from the response
+// header value, or zero if there is no metadata in the stream
+func ParseInterval(value string) int {
+	if interval, err := strconv.ParseUint(strings.TrimSpace(value), 10, 31); err != nil {
+		return 0
+	} else {
+		return int(interval)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *MetadataReader) readMetadata() error {
+	length := make([]byte, 1)
+	if _, err := io.ReadFull(this.r, length); err != nil {
+		return err
+	} else if length[0] == 0 {
+		return nil
+	}
+	block := make([]byte, int(length[0])*16)
+	if _, err := io.ReadFull(this.r, block); err == io.EOF {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+	if title, exists := ParseMetadata(block)[ICY_STREAM_TITLE]; exists && title != this.title {
+		this.title = title
+		if this.changed != nil {
+			this.changed(title)
+		}
+	}
+	return nil
+}
+
+func decodeText(value string) string {
+	if utf8.ValidString(value) {
+		return strings.TrimSpace(value)
+	}
+	str := make([]rune, 0, len(value))
+	for i := 0; i < len(value); i++ {
+		str = append(str, rune(value[i]))
+	}
+	return strings.TrimSpace(string(str))
+}
@@ -0,0 +1,123 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package radio
+
+import (
+	"bytes"
+	"io"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Mimetype for MPEG audio
+	MIMETYPE_MPEG = "audio/mpeg"
+
+	// Size of ID3 headers and tags
+	ID3V2_HEADER_SIZE = 10
+	ID3V1_TAG_SIZE    = 128
+
+	// Bytes searched for a VBR header in the first frame
+	MPEG_VBR_SEARCH = 64
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBAL VARIABLES
+
+var (
+	// Bitrates in kbit/s for MPEG-1 and MPEG-2 layers I, II and III,
+	// indexed by the bitrate index in the frame header
+	mpeg1Bitrates = [3][15]uint{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	}
+	mpeg2Bitrates = [3][15]uint{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// mpegAudio returns the offset and length of the audio frames in MPEG
+// audio data, without the ID3v2 header or ID3v1 tag, and the bitrate
+// of the first frame in kbit/s. Returns gopi.ErrBadParameter if the
+// data does not start with a frame, or the first frame is a VBR header
+// so the bitrate is not constant.
+func mpegAudio(r io.ReaderAt, size int64) (int64, int64, uint, error) {
+	start, end := int64(0), size
+
+	// Skip the ID3v2 header
+	header := make([]byte, ID3V2_HEADER_SIZE)
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return 0, 0, 0, err
+	} else if bytes.HasPrefix(header, []byte("ID3")) {
+		start = ID3V2_HEADER_SIZE + (int64(header[6]&0x7F)<<21 | int64(header[7]&0x7F)<<14 | int64(header[8]&0x7F)<<7 | int64(header[9]&0x7F))
+		if header[5]&0x10 != 0 {
+			// Footer
+			start += ID3V2_HEADER_SIZE
+		}
+	}
+
+	// Remove the ID3v1 tag
+	if end-start >= ID3V1_TAG_SIZE {
+		tag := make([]byte, 3)
+		if _, err := r.ReadAt(tag, end-ID3V1_TAG_SIZE); err != nil && err != io.EOF {
+			return 0, 0, 0, err
+		} else if string(tag) == "TAG" {
+			end -= ID3V1_TAG_SIZE
+		}
+	}
+
+	// Read the first frame
+	frame := make([]byte, MPEG_VBR_SEARCH)
+	if end <= start {
+		return 0, 0, 0, gopi.ErrBadParameter
+	} else if int64(len(frame)) > end-start {
+		frame = frame[:end-start]
+	}
+	if n, err := r.ReadAt(frame, start); err != nil && err != io.EOF {
+		return 0, 0, 0, err
+	} else {
+		frame = frame[:n]
+	}
+	if bitrate := mpegBitrate(frame); bitrate == 0 {
+		return 0, 0, 0, gopi.ErrBadParameter
+	} else if bytes.Contains(frame, []byte("Xing")) || bytes.Contains(frame, []byte("VBRI")) {
+		return 0, 0, 0, gopi.ErrBadParameter
+	} else {
+		return start, end - start, bitrate, nil
+	}
+}
+
+// mpegBitrate returns the bitrate in kbit/s from an MPEG audio frame
+// header, or zero if the data does not start with a frame header
+func mpegBitrate(frame []byte) uint {
+	if len(frame) < 4 || frame[0] != 0xFF || frame[1]&0xE0 != 0xE0 {
+		return 0
+	}
+	version, layer, index := (frame[1]>>3)&0x03, (frame[1]>>1)&0x03, frame[2]>>4
+	if version == 1 || layer == 0 || index == 0x0F {
+		return 0
+	} else if version == 3 {
+		// MPEG-1
+		return mpeg1Bitrates[3-layer][index]
+	} else {
+		// MPEG-2 and MPEG-2.5
+		return mpeg2Bitrates[3-layer][index]
+	}
+}
@@ -0,0 +1,252 @@
+package radio_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	radio "github.com/djthorpe/gopi-media/radio"
+)
+
+const RADIO_BROWSER = `[
+	{
+		"stationuuid": "96062a7b-0601-11e8-ae97-52543be04c81",
+		"name": " BBC Radio 6 Music ",
+		"url": "http://example.com/6music.pls",
+		"url_resolved": "http://example.com/6music.mp3",
+		"favicon": "http://example.com/6music.png",
+		"tags": "alternative, indie,,rock",
+		"codec": "MP3",
+		"bitrate": 128,
+		"countrycode": "GB"
+	},
+	{
+		"stationuuid": "",
+		"name": "No URL"
+	}
+]`
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST STATIONS
+
+func Test_radio_000(t *testing.T) {
+	t.Log("Test_radio_000")
+}
+
+func Test_radio_001(t *testing.T) {
+	stations, err := radio.ReadRadioBrowser(strings.NewReader(RADIO_BROWSER))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(stations) != 1 {
+		t.Fatal("Unexpected stations", stations)
+	}
+	s := stations[0]
+	if s.Id != "radio:96062a7b-0601-11e8-ae97-52543be04c81" || s.Name != "BBC Radio 6 Music" || s.URL != "http://example.com/6music.mp3" {
+		t.Error("Unexpected station", s)
+	} else if len(s.Tags) != 3 || s.Tags[1] != "indie" || s.Bitrate != 128 || s.Country != "GB" {
+		t.Error("Unexpected station", s)
+	}
+
+	item := radio.NewStation(s)
+	if item.Title() != s.Name || item.URL() != s.URL || item.Logo() != s.Logo || item.Type() != media.MEDIA_TYPE_AUDIO {
+		t.Error("Unexpected item", item)
+	} else if item.StringForKey(media.METADATA_KEY_GENRE) != "alternative, indie, rock" {
+		t.Error("Unexpected genre", item.StringForKey(media.METADATA_KEY_GENRE))
+	} else if station := radio.StationFor(item); station.Id != s.Id {
+		t.Error("Unexpected station", station)
+	}
+}
+
+func Test_radio_002(t *testing.T) {
+	stations := []radio.Station{
+		{Name: "One", URL: "http://example.com/one"},
+		{Name: "Empty"},
+	}
+	buf := new(bytes.Buffer)
+	if err := radio.WriteStations(buf, stations); err != nil {
+		t.Fatal(err)
+	} else if stations, err := radio.ReadStations(buf); err != nil {
+		t.Error(err)
+	} else if len(stations) != 1 || stations[0].Name != "One" {
+		t.Error("Unexpected stations", stations)
+	} else if stations[0].Id != radio.Identifier("http://example.com/one") {
+		t.Error("Unexpected identifier", stations[0].Id)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST METADATA
+
+func Test_radio_003(t *testing.T) {
+	values := radio.ParseMetadata([]byte("StreamTitle='It's Alive; Live';StreamUrl='';\x00\x00\x00"))
+	if values["StreamTitle"] != "It's Alive; Live" {
+		t.Error("Unexpected title", values)
+	} else if value, exists := values["StreamUrl"]; exists == false || value != "" {
+		t.Error("Unexpected url", values)
+	}
+	if values := radio.ParseMetadata([]byte("StreamTitle='Caf\xe9';")); values["StreamTitle"] != "Café" {
+		t.Error("Unexpected title", values)
+	}
+	if interval := radio.ParseInterval(" 16000 "); interval != 16000 {
+		t.Error("Unexpected interval", interval)
+	} else if interval := radio.ParseInterval("-1"); interval != 0 {
+		t.Error("Unexpected interval", interval)
+	}
+}
+
+func Test_radio_004(t *testing.T) {
+	// Stream with metadata every four bytes
+	block := func(title string) []byte {
+		meta := []byte("StreamTitle='" + title + "';")
+		meta = append(meta, make([]byte, 16-len(meta)%16)...)
+		return append([]byte{byte(len(meta) / 16)}, meta...)
+	}
+	stream := []byte("abcd")
+	stream = append(stream, block("One")...)
+	stream = append(stream, "efgh"...)
+	stream = append(stream, 0)
+	stream = append(stream, "ijkl"...)
+	stream = append(stream, block("Two")...)
+	stream = append(stream, "mn"...)
+
+	titles := []string{}
+	reader := radio.NewMetadataReader(bytes.NewReader(stream), 4, func(title string) {
+		titles = append(titles, title)
+	})
+	if data, err := ioutil.ReadAll(reader); err != nil {
+		t.Error(err)
+	} else if string(data) != "abcdefghijklmn" {
+		t.Error("Unexpected data", string(data))
+	} else if len(titles) != 2 || titles[0] != "One" || titles[1] != "Two" || reader.Title() != "Two" {
+		t.Error("Unexpected titles", titles)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST SOURCE
+
+func Test_radio_005(t *testing.T) {
+	var lock sync.Mutex
+	var titles []string
+	received := new(bytes.Buffer)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); r.Method == "PUT" && password != "hackme" {
+			// Reject the stream without reading it, as icecast does
+			if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+				conn.Write([]byte("HTTP/1.0 401 Unauthorized\r\n\r\n"))
+				conn.Close()
+			}
+		} else if user != "source" || password != "hackme" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		} else if r.Method == "PUT" && r.URL.Path == "/live" && r.Header.Get("Content-Type") == "audio/mpeg" {
+			data, _ := ioutil.ReadAll(r.Body)
+			lock.Lock()
+			received.Write(data)
+			lock.Unlock()
+		} else if r.URL.Path == "/admin/metadata" && r.URL.Query().Get("mount") == "/live" {
+			lock.Lock()
+			titles = append(titles, r.URL.Query().Get("song"))
+			lock.Unlock()
+		} else {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "radio")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := []media.MediaFile{
+		&mock.File{Path: filepath.Join(dir, "one.mp3"), Metadata: map[media.MetadataKey]string{media.METADATA_KEY_ARTIST: "Artist"}},
+		&mock.File{Path: filepath.Join(dir, "two.mp3")},
+	}
+	for _, file := range files {
+		if err := ioutil.WriteFile(file.Filename(), mpegFile(file.Title(), 0x90), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source, err := radio.NewSource(strings.Replace(server.URL, "http://", "http://hackme@", 1)+"/live", "audio/mpeg", 128)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.Name = "Test"
+	if err := source.Connect(); err != nil {
+		t.Fatal(err)
+	} else if err := source.Play(files, nil); err != nil {
+		t.Error(err)
+	} else if err := source.Close(); err != nil {
+		t.Error(err)
+	}
+	if received.String() != string(mpegFrame("one", 0x90))+string(mpegFrame("two", 0x90)) {
+		t.Error("Unexpected stream", received.String())
+	} else if len(titles) != 2 || titles[0] != "Artist - one" || titles[1] != "two" {
+		t.Error("Unexpected titles", titles)
+	}
+
+	// Files which do not match the source are not sent
+	mismatch := &mock.File{Path: filepath.Join(dir, "three.mp3")}
+	if err := ioutil.WriteFile(mismatch.Filename(), mpegFile("three", 0x50), 0644); err != nil {
+		t.Fatal(err)
+	} else if err := source.Connect(); err != nil {
+		t.Fatal(err)
+	} else if err := source.Play([]media.MediaFile{mismatch}, nil); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter for different bitrate, got", err)
+	} else if err := ioutil.WriteFile(mismatch.Filename(), []byte("three"), 0644); err != nil {
+		t.Fatal(err)
+	} else if err := source.Play([]media.MediaFile{mismatch}, nil); err != gopi.ErrBadParameter {
+		t.Error("Expected ErrBadParameter for data which is not MPEG audio, got", err)
+	} else if err := source.Close(); err != nil {
+		t.Error(err)
+	}
+	if len(titles) != 2 {
+		t.Error("Unexpected titles", titles)
+	}
+	if source, err := radio.NewSource(server.URL+"/live", "audio/ogg", 128); err != nil {
+		t.Fatal(err)
+	} else if err := source.Play(files, nil); err != gopi.ErrNotImplemented {
+		t.Error("Expected ErrNotImplemented for audio/ogg, got", err)
+	}
+
+	// Incorrect password
+	if source, err := radio.NewSource(server.URL+"/live", "audio/mpeg", 128); err != nil {
+		t.Fatal(err)
+	} else if err := source.SetTitle("title"); err == nil {
+		t.Error("Expected error for incorrect password")
+	} else if err := source.Connect(); err == nil {
+		t.Error("Expected error connecting with incorrect password")
+	} else if _, err := source.Write([]byte("data")); err == nil {
+		t.Error("Expected error writing when not connected")
+	}
+	if _, err := radio.NewSource(server.URL, "audio/mpeg", 128); err == nil {
+		t.Error("Expected error for missing mount point")
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// mpegFrame returns an MPEG-1 layer III frame header with a bitrate
+// index, followed by the data
+func mpegFrame(data string, bitrate byte) []byte {
+	return append([]byte{0xFF, 0xFB, bitrate, 0x00}, data...)
+}
+
+// mpegFile returns a frame with an ID3v2 header and an ID3v1 tag
+func mpegFile(data string, bitrate byte) []byte {
+	id3v2 := append([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 5}, "title"...)
+	id3v1 := append([]byte("TAG"), make([]byte, 125)...)
+	return append(append(id3v2, mpegFrame(data, bitrate)...), id3v1...)
+}
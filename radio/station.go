@@ -0,0 +1,236 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package radio
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Station is an internet radio station in a station list
+type Station struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	URL     string   `json:"url"`
+	Logo    string   `json:"logo,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Codec   string   `json:"codec,omitempty"`
+	Bitrate uint     `json:"bitrate,omitempty"`
+	Country string   `json:"country,omitempty"`
+}
+
+// station implements media.RadioStation for a station
+type station struct {
+	s Station
+}
+
+// radioBrowserStation is a station returned by the radio-browser.info API
+type radioBrowserStation struct {
+	Uuid        string `json:"stationuuid"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	URLResolved string `json:"url_resolved"`
+	Favicon     string `json:"favicon"`
+	Tags        string `json:"tags"`
+	Codec       string `json:"codec"`
+	Bitrate     uint   `json:"bitrate"`
+	CountryCode string `json:"countrycode"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STATION LISTS
+
+// ReadStationsFile reads a station list from a file
+func ReadStationsFile(filename string) ([]Station, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return ReadStations(fh)
+	}
+}
+
+// ReadStations reads a station list, which is a JSON array of stations.
+// Stations without a URL are ignored, and stations without an identifier
+// are given one derived from the URL.
+func ReadStations(r io.Reader) ([]Station, error) {
+	var list []Station
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+	stations := make([]Station, 0, len(list))
+	for _, s := range list {
+		if s.URL = strings.TrimSpace(s.URL); s.URL == "" {
+			continue
+		}
+		if s.Id == "" {
+			s.Id = Identifier(s.URL)
+		}
+		stations = append(stations, s)
+	}
+	return stations, nil
+}
+
+// WriteStations writes a station list which can be read with ReadStations
+func WriteStations(w io.Writer, stations []Station) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stations)
+}
+
+// ReadRadioBrowser reads stations from a radio-browser.info API
+// response, which is a JSON array of stations
+func ReadRadioBrowser(r io.Reader) ([]Station, error) {
+	var list []radioBrowserStation
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, err
+	}
+	stations := make([]Station, 0, len(list))
+	for _, s := range list {
+		station := Station{
+			Name:    strings.TrimSpace(s.Name),
+			URL:     strings.TrimSpace(s.URLResolved),
+			Logo:    strings.TrimSpace(s.Favicon),
+			Codec:   s.Codec,
+			Bitrate: s.Bitrate,
+			Country: s.CountryCode,
+		}
+		if station.URL == "" {
+			station.URL = strings.TrimSpace(s.URL)
+		}
+		if station.URL == "" {
+			continue
+		}
+		if s.Uuid != "" {
+			station.Id = "radio:" + s.Uuid
+		} else {
+			station.Id = Identifier(station.URL)
+		}
+		for _, tag := range strings.Split(s.Tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				station.Tags = append(station.Tags, tag)
+			}
+		}
+		stations = append(stations, station)
+	}
+	return stations, nil
+}
+
+// Identifier returns the identifier for a station which is derived
+// from the stream URL
+func Identifier(url string) string {
+	hash := sha1.Sum([]byte(url))
+	return "radio:" + hex.EncodeToString(hash[:8])
+}
+
+// NewStation returns a station as a media item
+func NewStation(s Station) media.RadioStation {
+	if s.Id == "" {
+		s.Id = Identifier(s.URL)
+	}
+	return &station{s}
+}
+
+// StationFor returns the station for a media item, which can be any
+// implementation of media.RadioStation
+func StationFor(item media.RadioStation) Station {
+	if item, ok := item.(*station); ok {
+		return item.s
+	}
+	s := Station{
+		Id:   item.Id(),
+		Name: item.StringForKey(media.METADATA_KEY_SERVICE_NAME),
+		URL:  item.URL(),
+		Logo: item.Logo(),
+	}
+	for _, tag := range strings.Split(item.StringForKey(media.METADATA_KEY_GENRE), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			s.Tags = append(s.Tags, tag)
+		}
+	}
+	return s
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RADIOSTATION INTERFACE IMPLEMENTATION
+
+func (this *station) Id() string {
+	return this.s.Id
+}
+
+func (this *station) Title() string {
+	if this.s.Name != "" {
+		return this.s.Name
+	} else {
+		return this.s.URL
+	}
+}
+
+func (this *station) Type() media.MediaType {
+	return media.MEDIA_TYPE_AUDIO
+}
+
+func (this *station) URL() string {
+	return this.s.URL
+}
+
+func (this *station) Logo() string {
+	return this.s.Logo
+}
+
+func (this *station) Keys() []media.MetadataKey {
+	keys := []media.MetadataKey{media.METADATA_KEY_STREAM_URL}
+	if this.s.Name != "" {
+		keys = append(keys, media.METADATA_KEY_SERVICE_NAME)
+	}
+	if len(this.s.Tags) > 0 {
+		keys = append(keys, media.METADATA_KEY_GENRE)
+	}
+	return keys
+}
+
+func (this *station) StringForKey(key media.MetadataKey) string {
+	switch key {
+	case media.METADATA_KEY_STREAM_URL:
+		return this.s.URL
+	case media.METADATA_KEY_SERVICE_NAME:
+		return this.s.Name
+	case media.METADATA_KEY_GENRE:
+		return strings.Join(this.s.Tags, ", ")
+	default:
+		return ""
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Station) String() string {
+	return fmt.Sprintf("<radio.Station>{ id=%v name=%v url=%v }", this.Id, strconv.Quote(this.Name), strconv.Quote(this.URL))
+}
+
+func (this *station) String() string {
+	return this.s.String()
+}
@@ -0,0 +1,120 @@
+/*
+	Go Language Raspberry Pi Interface
+	(c) Copyright David Thorpe 2019
+	All Rights Reserved
+	For Licensing and Usage information, please see LICENSE.md
+*/
+
+package media
+
+import (
+	// Frameworks
+	"github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type RemoteKey uint
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// Display is a TV connected over HDMI-CEC, which emits a RemoteEvent
+// when a key on the TV remote is pressed or released
+type Display interface {
+	gopi.Driver
+	gopi.Publisher
+
+	// Turn the TV on and switch it to this input, for when playback
+	// starts
+	PowerOn() error
+
+	// Put the TV into standby
+	Standby() error
+}
+
+// RemoteEvent is emitted when a key on a remote is pressed or released
+type RemoteEvent interface {
+	gopi.Event
+
+	// Return the key
+	Key() RemoteKey
+
+	// Return true if the key was pressed, false if it was released
+	Pressed() bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	REMOTE_KEY_NONE RemoteKey = iota
+	REMOTE_KEY_SELECT
+	REMOTE_KEY_UP
+	REMOTE_KEY_DOWN
+	REMOTE_KEY_LEFT
+	REMOTE_KEY_RIGHT
+	REMOTE_KEY_MENU
+	REMOTE_KEY_BACK
+	REMOTE_KEY_PLAY
+	REMOTE_KEY_PAUSE
+	REMOTE_KEY_PLAYPAUSE
+	REMOTE_KEY_STOP
+	REMOTE_KEY_REWIND
+	REMOTE_KEY_FASTFORWARD
+	REMOTE_KEY_NEXT
+	REMOTE_KEY_PREVIOUS
+	REMOTE_KEY_CHANNELUP
+	REMOTE_KEY_CHANNELDOWN
+	REMOTE_KEY_INFO
+	REMOTE_KEY_MAX = REMOTE_KEY_INFO
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (k RemoteKey) String() string {
+	switch k {
+	case REMOTE_KEY_NONE:
+		return "REMOTE_KEY_NONE"
+	case REMOTE_KEY_SELECT:
+		return "REMOTE_KEY_SELECT"
+	case REMOTE_KEY_UP:
+		return "REMOTE_KEY_UP"
+	case REMOTE_KEY_DOWN:
+		return "REMOTE_KEY_DOWN"
+	case REMOTE_KEY_LEFT:
+		return "REMOTE_KEY_LEFT"
+	case REMOTE_KEY_RIGHT:
+		return "REMOTE_KEY_RIGHT"
+	case REMOTE_KEY_MENU:
+		return "REMOTE_KEY_MENU"
+	case REMOTE_KEY_BACK:
+		return "REMOTE_KEY_BACK"
+	case REMOTE_KEY_PLAY:
+		return "REMOTE_KEY_PLAY"
+	case REMOTE_KEY_PAUSE:
+		return "REMOTE_KEY_PAUSE"
+	case REMOTE_KEY_PLAYPAUSE:
+		return "REMOTE_KEY_PLAYPAUSE"
+	case REMOTE_KEY_STOP:
+		return "REMOTE_KEY_STOP"
+	case REMOTE_KEY_REWIND:
+		return "REMOTE_KEY_REWIND"
+	case REMOTE_KEY_FASTFORWARD:
+		return "REMOTE_KEY_FASTFORWARD"
+	case REMOTE_KEY_NEXT:
+		return "REMOTE_KEY_NEXT"
+	case REMOTE_KEY_PREVIOUS:
+		return "REMOTE_KEY_PREVIOUS"
+	case REMOTE_KEY_CHANNELUP:
+		return "REMOTE_KEY_CHANNELUP"
+	case REMOTE_KEY_CHANNELDOWN:
+		return "REMOTE_KEY_CHANNELDOWN"
+	case REMOTE_KEY_INFO:
+		return "REMOTE_KEY_INFO"
+	default:
+		return "[?? Invalid RemoteKey value]"
+	}
+}
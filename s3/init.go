@@ -0,0 +1,54 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package s3
+
+import (
+	"net/url"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	source "github.com/djthorpe/gopi-media/source"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+// Register S3 sources with URLs such as
+// "s3://key:secret@host/bucket/prefix?region=eu-west-1", where "s3" uses
+// HTTPS and "s3+http" uses HTTP
+func init() {
+	source.Register("s3", open)
+	source.Register("s3+http", open)
+}
+
+func open(u *url.URL) (media.Source, error) {
+	client := Client{}
+	if u.Host == "" {
+		return nil, gopi.ErrBadParameter
+	} else if u.Scheme == "s3+http" {
+		client.Endpoint = "http://" + u.Host
+	} else {
+		client.Endpoint = "https://" + u.Host
+	}
+	parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+	if client.Bucket = parts[0]; client.Bucket == "" {
+		return nil, gopi.ErrBadParameter
+	} else if len(parts) > 1 {
+		client.Prefix = parts[1]
+	}
+	if u.User != nil {
+		client.AccessKey = u.User.Username()
+		client.SecretKey, _ = u.User.Password()
+	}
+	client.Region = u.Query().Get("region")
+	return client, nil
+}
@@ -0,0 +1,325 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package s3
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Client reads objects from a bucket on Amazon S3 or a compatible
+// service such as MinIO. Paths are object keys within the prefix, with
+// forward slashes separating folders.
+type Client struct {
+	// Endpoint is the URL of the service, for example
+	// "https://s3.eu-west-1.amazonaws.com" or "http://minio:9000"
+	Endpoint string
+
+	// Region is used for signing requests, and is "us-east-1" when empty
+	Region string
+
+	// Bucket, and an optional Prefix for keys within the bucket
+	Bucket, Prefix string
+
+	// AccessKey and SecretKey sign requests, which are anonymous when
+	// AccessKey is empty
+	AccessKey, SecretKey string
+
+	// VirtualHost addresses the bucket as part of the hostname rather
+	// than the path
+	VirtualHost bool
+
+	// Client makes the requests, or util.DefaultClient when nil
+	Client *http.Client
+}
+
+// fileInfo implements os.FileInfo for objects and folders
+type fileInfo struct {
+	name    string
+	size    int64
+	modtime time.Time
+	dir     bool
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+	CommonPrefixes []struct {
+		Prefix string `xml:"Prefix"`
+	} `xml:"CommonPrefixes"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_REGION = "us-east-1"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Stat returns information about an object, or about a folder when
+// there are objects with the path as a prefix. It returns
+// gopi.ErrNotFound if there are neither.
+func (this Client) Stat(name string) (os.FileInfo, error) {
+	key := this.key(name)
+	base := path.Base("/" + key)
+	if key != "" {
+		if response, err := this.do("HEAD", key, nil); err != nil && err != gopi.ErrNotFound {
+			return nil, err
+		} else if err == nil {
+			response.Body.Close()
+			info := &fileInfo{name: base, size: response.ContentLength}
+			if modtime, err := http.ParseTime(response.Header.Get("Last-Modified")); err == nil {
+				info.modtime = modtime
+			}
+			return info, nil
+		}
+	}
+
+	// Check for a folder
+	if result, err := this.list(folder(key), "", 1); err != nil {
+		return nil, err
+	} else if len(result.Contents) == 0 && len(result.CommonPrefixes) == 0 {
+		return nil, gopi.ErrNotFound
+	} else {
+		return &fileInfo{name: base, dir: true}, nil
+	}
+}
+
+// ReadDir returns the objects and folders within a folder, sorted by
+// name. It returns gopi.ErrNotFound if the folder is empty.
+func (this Client) ReadDir(name string) ([]os.FileInfo, error) {
+	prefix := folder(this.key(name))
+	infos := make([]os.FileInfo, 0)
+	token := ""
+	for {
+		result, err := this.list(prefix, token, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range result.CommonPrefixes {
+			if name := strings.TrimSuffix(strings.TrimPrefix(p.Prefix, prefix), "/"); name != "" {
+				infos = append(infos, &fileInfo{name: name, dir: true})
+			}
+		}
+		for _, object := range result.Contents {
+			name := strings.TrimPrefix(object.Key, prefix)
+			if name == "" || strings.HasSuffix(name, "/") {
+				// Ignore folder placeholder objects
+				continue
+			}
+			info := &fileInfo{name: name, size: object.Size}
+			if modtime, err := time.Parse(time.RFC3339, object.LastModified); err == nil {
+				info.modtime = modtime
+			}
+			infos = append(infos, info)
+		}
+		if result.IsTruncated == false || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	if len(infos) == 0 {
+		return nil, gopi.ErrNotFound
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// Open returns an object which is read with signed range requests, or
+// gopi.ErrNotFound if it does not exist
+func (this Client) Open(name string) (media.SourceFile, error) {
+	info, err := this.Stat(name)
+	if err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		return nil, gopi.ErrBadParameter
+	}
+	key := this.key(name)
+	return util.NewRemoteFile(this.Client, info.Size(), func() (*http.Request, error) {
+		return this.request("GET", key, nil)
+	}), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Client) String() string {
+	return fmt.Sprintf("<s3.Client>{ endpoint=%v region=%v bucket=%v prefix=%v }",
+		strconv.Quote(this.Endpoint), strconv.Quote(this.region()), strconv.Quote(this.Bucket), strconv.Quote(this.Prefix))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FILEINFO IMPLEMENTATION
+
+func (this *fileInfo) Name() string       { return this.name }
+func (this *fileInfo) Size() int64        { return this.size }
+func (this *fileInfo) ModTime() time.Time { return this.modtime }
+func (this *fileInfo) IsDir() bool        { return this.dir }
+func (this *fileInfo) Sys() interface{}   { return nil }
+
+func (this *fileInfo) Mode() os.FileMode {
+	if this.dir {
+		return os.ModeDir | 0555
+	} else {
+		return 0444
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this Client) region() string {
+	if this.Region == "" {
+		return DEFAULT_REGION
+	} else {
+		return this.Region
+	}
+}
+
+// key returns the object key for a path
+func (this Client) key(name string) string {
+	key := strings.Trim(path.Clean("/"+name), "/")
+	if prefix := strings.Trim(this.Prefix, "/"); prefix == "" {
+		return key
+	} else if key == "" {
+		return prefix
+	} else {
+		return prefix + "/" + key
+	}
+}
+
+// url returns the URL for an object key and query
+func (this Client) url(key string, query url.Values) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSuffix(this.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	} else if this.Bucket == "" || u.Host == "" {
+		return nil, gopi.ErrBadParameter
+	}
+	if this.VirtualHost {
+		u.Host = this.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	} else {
+		u.Path = u.Path + "/" + this.Bucket + "/" + key
+	}
+	u.RawQuery = query.Encode()
+	return u, nil
+}
+
+// request returns a signed request for an object key
+func (this Client) request(method, key string, query url.Values) (*http.Request, error) {
+	u, err := this.url(key, query)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := this.Sign(request, time.Now()); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// do makes a request and returns the response for a successful status
+func (this Client) do(method, key string, query url.Values) (*http.Response, error) {
+	client := this.Client
+	if client == nil {
+		client = util.DefaultClient
+	}
+	request, err := this.request(method, key, query)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+
+	// HEAD responses have no body, so only the status is known
+	var e errorResponse
+	xml.NewDecoder(response.Body).Decode(&e)
+	switch {
+	case response.StatusCode == http.StatusNotFound, e.Code == "NoSuchKey", e.Code == "NoSuchBucket":
+		return nil, gopi.ErrNotFound
+	case e.Code != "":
+		return nil, fmt.Errorf("%v: %v", e.Code, e.Message)
+	default:
+		return nil, fmt.Errorf("%v", response.Status)
+	}
+}
+
+// list returns one page of objects and folders for a prefix, limited
+// to a number of keys when not zero
+func (this Client) list(prefix, token string, max uint) (*listBucketResult, error) {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("delimiter", "/")
+	query.Set("prefix", prefix)
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+	if max > 0 {
+		query.Set("max-keys", fmt.Sprint(max))
+	}
+	response, err := this.do("GET", "", query)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	result := new(listBucketResult)
+	if err := xml.NewDecoder(response.Body).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// folder returns a key as a prefix for listing a folder
+func folder(key string) string {
+	if key == "" {
+		return ""
+	} else {
+		return key + "/"
+	}
+}
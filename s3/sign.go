@@ -0,0 +1,145 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	algorithm = "AWS4-HMAC-SHA256"
+	service   = "s3"
+
+	// emptyHash is the SHA256 hash of an empty request body
+	emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// SIGN
+
+// Sign adds an AWS Signature Version 4 authorization header to a
+// request without a body, signing the host, any Range header and any
+// x-amz headers. Headers added after signing, such as the Range header
+// for reads, are not signed. Requests are not signed when there is no
+// access key.
+func (this Client) Sign(request *http.Request, now time.Time) error {
+	if this.AccessKey == "" {
+		return nil
+	} else if request.URL == nil || request.URL.Host == "" {
+		return gopi.ErrBadParameter
+	}
+
+	now = now.UTC()
+	date := now.Format("20060102")
+	request.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	request.Header.Set("X-Amz-Content-Sha256", emptyHash)
+
+	// Canonical headers are lowercase and sorted by name
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		if name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonical := make([]string, 0, len(names))
+	for _, name := range names {
+		canonical = append(canonical, name+":"+headers[name]+"\n")
+	}
+	signed := strings.Join(names, ";")
+
+	creq := strings.Join([]string{
+		request.Method,
+		escape(request.URL.EscapedPath(), false),
+		canonicalQuery(request.URL.Query()),
+		strings.Join(canonical, ""),
+		signed,
+		emptyHash,
+	}, "\n")
+	scope := date + "/" + this.region() + "/" + service + "/aws4_request"
+	sts := algorithm + "\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hash(creq)
+
+	key := mac([]byte("AWS4"+this.SecretKey), date)
+	key = mac(key, this.region())
+	key = mac(key, service)
+	key = mac(key, "aws4_request")
+	signature := hex.EncodeToString(mac(key, sts))
+
+	request.Header.Set("Authorization", algorithm+" Credential="+this.AccessKey+"/"+scope+", SignedHeaders="+signed+", Signature="+signature)
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func mac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hash(data string) string {
+	h := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(h[:])
+}
+
+// canonicalQuery returns query parameters sorted by name and value
+func canonicalQuery(query map[string][]string) string {
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, escape(name, true)+"="+escape(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// escape percent-encodes all but unreserved characters, and slashes
+// unless encoding a query parameter. Existing escapes in paths are
+// decoded first so they are not encoded twice.
+func escape(value string, query bool) string {
+	if query == false {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && query == false:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
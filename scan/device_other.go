@@ -0,0 +1,26 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package scan
+
+import (
+	"os"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// fileIdFor returns false as files cannot be identified on this platform,
+// so loops and mount boundaries are not detected
+func fileIdFor(info os.FileInfo) (fileId, bool) {
+	return fileId{}, false
+}
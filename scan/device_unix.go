@@ -0,0 +1,30 @@
+//go:build linux || darwin
+// +build linux darwin
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package scan
+
+import (
+	"os"
+	"syscall"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// fileIdFor returns the device and inode of a file
+func fileIdFor(info os.FileInfo) (fileId, bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return fileId{uint64(stat.Dev), uint64(stat.Ino)}, true
+	} else {
+		return fileId{}, false
+	}
+}
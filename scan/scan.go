@@ -0,0 +1,244 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	source "github.com/djthorpe/gopi-media/source"
+	errors "github.com/djthorpe/gopi/util/errors"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Options determine which files are returned when walking a folder
+type Options struct {
+	// Exclude are the names of files and folders which are skipped, as
+	// shell globs such as "*.part" or regular expressions between
+	// slashes such as "/^sample[-.]/"
+	Exclude []string
+
+	// Hidden returns files and folders with names starting with a dot
+	Hidden bool
+
+	// Symlinks follows symbolic links to files and folders. Folders
+	// which have already been walked are skipped, so links back to a
+	// parent folder do not loop.
+	Symlinks bool
+
+	// SameDevice does not walk into folders on a different filesystem
+	// to the root, such as mounted drives and network shares
+	SameDevice bool
+}
+
+// WalkFunc is called for each media file found when walking a folder.
+// Returning an error stops the walk.
+type WalkFunc func(path string, info os.FileInfo) error
+
+type walker struct {
+	ctx     context.Context
+	src     media.Source
+	globs   []string
+	exps    []*regexp.Regexp
+	opts    Options
+	fn      WalkFunc
+	errs    errors.CompoundError
+	device  uint64
+	visited map[fileId]bool
+}
+
+// fileId identifies a file by device and inode
+type fileId struct {
+	device, inode uint64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Folders containing a file with this name are skipped, along with
+	// the folders within them
+	NOMEDIA = ".nomedia"
+)
+
+var (
+	// DefaultExclude skips NAS metadata folders, recycle bins and
+	// incomplete downloads
+	DefaultExclude = []string{"@eaDir", "#recycle", "$RECYCLE.BIN", "lost+found", "*.part", "*.partial", "*.crdownload", "*.!qB", "*.tmp"}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// WALK
+
+// Walk calls a function for each non-empty regular file within a root
+// folder, in lexical order, skipping excluded and hidden names and
+// folders marked with a .nomedia file. Symbolic links are skipped unless
+// followed. Folders which cannot be read are skipped and returned as
+// errors when the walk completes. It returns gopi.ErrBadParameter if an
+// exclude pattern is invalid, and the context error if the context is
+// cancelled before the walk completes.
+func Walk(ctx context.Context, root string, opts Options, fn WalkFunc) error {
+	return WalkSource(ctx, source.Local{Root: root}, ".", opts, func(name string, info os.FileInfo) error {
+		return fn(filepath.Join(root, filepath.FromSlash(name)), info)
+	})
+}
+
+// WalkSource walks a folder within a source in the same way as Walk,
+// calling the function with paths within the source. Symbolic links and
+// devices are only detected for local sources.
+func WalkSource(ctx context.Context, src media.Source, root string, opts Options, fn WalkFunc) error {
+	this := &walker{ctx: ctx, src: src, opts: opts, fn: fn, visited: make(map[fileId]bool)}
+	if err := this.compile(opts.Exclude); err != nil {
+		return err
+	}
+	if root = strings.Trim(path.Clean("/"+root), "/"); root == "" {
+		root = "."
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	info, err := src.Stat(root)
+	if err != nil {
+		return err
+	}
+	if id, ok := fileIdFor(info); ok {
+		this.device = id.device
+	}
+	if info.IsDir() {
+		if err := this.dir(root, info); err != nil {
+			return err
+		}
+	} else if err := this.file(root, info); err != nil {
+		return err
+	}
+	return this.errs.ErrorOrSelf()
+}
+
+// Excluded returns true if a name matches any of the exclude patterns.
+// It returns false if any pattern is invalid.
+func Excluded(name string, patterns []string) bool {
+	this := new(walker)
+	if err := this.compile(patterns); err != nil {
+		return false
+	}
+	return this.excluded(name)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (o Options) String() string {
+	return fmt.Sprintf("<scan.Options>{ exclude=%v hidden=%v symlinks=%v same_device=%v }", o.Exclude, o.Hidden, o.Symlinks, o.SameDevice)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *walker) compile(patterns []string) error {
+	for _, pattern := range patterns {
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			if exp, err := regexp.Compile(pattern[1 : len(pattern)-1]); err != nil {
+				return gopi.ErrBadParameter
+			} else {
+				this.exps = append(this.exps, exp)
+			}
+		} else if _, err := filepath.Match(pattern, ""); err != nil || pattern == "" {
+			return gopi.ErrBadParameter
+		} else {
+			this.globs = append(this.globs, pattern)
+		}
+	}
+	return nil
+}
+
+func (this *walker) excluded(name string) bool {
+	for _, glob := range this.globs {
+		if match, _ := filepath.Match(glob, name); match {
+			return true
+		}
+	}
+	for _, exp := range this.exps {
+		if exp.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (this *walker) skip(name string) bool {
+	if this.opts.Hidden == false && strings.HasPrefix(name, ".") {
+		return true
+	}
+	return this.excluded(name)
+}
+
+func (this *walker) dir(name string, info os.FileInfo) error {
+	// Skip folders on other filesystems and folders already walked
+	if id, ok := fileIdFor(info); ok {
+		if this.opts.SameDevice && id.device != this.device {
+			return nil
+		} else if this.visited[id] {
+			return nil
+		} else {
+			this.visited[id] = true
+		}
+	}
+	if _, err := this.src.Stat(path.Join(name, NOMEDIA)); err == nil {
+		return nil
+	}
+	infos, err := this.src.ReadDir(name)
+	if err != nil {
+		this.errs.Add(err)
+		return nil
+	}
+	for _, info := range infos {
+		if err := this.ctx.Err(); err != nil {
+			return err
+		} else if this.skip(info.Name()) {
+			continue
+		}
+		child := path.Join(name, info.Name())
+		if info.Mode()&os.ModeSymlink != 0 {
+			if this.opts.Symlinks == false {
+				continue
+			} else if target, err := this.src.Stat(child); err != nil {
+				this.errs.Add(err)
+				continue
+			} else {
+				info = target
+			}
+		}
+		if info.IsDir() {
+			if err := this.dir(child, info); err != nil {
+				return err
+			}
+		} else if err := this.file(child, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *walker) file(name string, info os.FileInfo) error {
+	if info.Mode().IsRegular() == false || info.Size() == 0 {
+		return nil
+	}
+	return this.fn(name, info)
+}
@@ -0,0 +1,258 @@
+package scan_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	scan "github.com/djthorpe/gopi-media/scan"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST WALK
+
+func Test_scan_000(t *testing.T) {
+	t.Log("Test_scan_000")
+}
+
+func Test_scan_001(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"music/a.mp3":             "a",
+		"music/b.mp3.part":        "b",
+		"music/empty.mp3":         "",
+		"music/.hidden.mp3":       "c",
+		"music/@eaDir/a.mp3.jpg":  "d",
+		"movies/sample-film.mp4":  "e",
+		"movies/film.mp4":         "f",
+		"downloads/.nomedia":      "",
+		"downloads/film.mp4":      "g",
+		"downloads/inner/tv.mkv":  "h",
+		".trash/music/z.mp3":      "i",
+		"music/Album/01 Song.m4a": "j",
+	})
+	defer os.RemoveAll(dir)
+
+	files := walk(t, dir, scan.Options{Exclude: append(scan.DefaultExclude, "/^sample-/")})
+	if files != "movies/film.mp4,music/Album/01 Song.m4a,music/a.mp3" {
+		t.Error("Unexpected files", files)
+	}
+	files = walk(t, dir, scan.Options{Hidden: true})
+	if files != ".trash/music/z.mp3,movies/film.mp4,movies/sample-film.mp4,music/.hidden.mp3,music/@eaDir/a.mp3.jpg,music/Album/01 Song.m4a,music/a.mp3,music/b.mp3.part" {
+		t.Error("Unexpected files", files)
+	}
+
+	// A file can be the root
+	if files := walk(t, filepath.Join(dir, "movies", "film.mp4"), scan.Options{}); files != "." {
+		t.Error("Unexpected files", files)
+	}
+}
+
+func Test_scan_002(t *testing.T) {
+	for _, pattern := range []string{"[", "/(/", ""} {
+		if err := scan.Walk(context.Background(), os.TempDir(), scan.Options{Exclude: []string{pattern}}, nil); err != gopi.ErrBadParameter {
+			t.Error("Expected ErrBadParameter for", pattern, err)
+		}
+	}
+	if scan.Excluded("film.mp4.part", scan.DefaultExclude) == false {
+		t.Error("Expected excluded")
+	} else if scan.Excluded("film.mp4", scan.DefaultExclude) {
+		t.Error("Unexpected excluded")
+	}
+}
+
+func Test_scan_003(t *testing.T) {
+	dir := tree(t, map[string]string{
+		"media/music/a.mp3":  "a",
+		"elsewhere/film.mp4": "b",
+		"elsewhere/b.mp3":    "c",
+	})
+	defer os.RemoveAll(dir)
+
+	// A link back to the root, a link to another folder, a link to a
+	// file and a broken link
+	root := filepath.Join(dir, "media")
+	for name, target := range map[string]string{
+		"media/music/loop": root,
+		"media/other":      filepath.Join(dir, "elsewhere"),
+		"media/film.mp4":   filepath.Join(dir, "elsewhere", "film.mp4"),
+		"media/broken.mp4": filepath.Join(dir, "missing.mp4"),
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			t.Skip("Symlinks not supported:", err)
+		}
+	}
+
+	if files := walk(t, root, scan.Options{}); files != "music/a.mp3" {
+		t.Error("Unexpected files", files)
+	}
+
+	// The broken link is returned as an error after the walk
+	files := make([]string, 0)
+	err := scan.Walk(context.Background(), root, scan.Options{Symlinks: true}, func(path string, info os.FileInfo) error {
+		rel, _ := filepath.Rel(root, path)
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error for broken link")
+	} else if strings.Join(files, ",") != "film.mp4,music/a.mp3,other/b.mp3,other/film.mp4" {
+		t.Error("Unexpected files", files)
+	}
+
+	// All files are on the same device
+	if files := walk(t, filepath.Join(dir, "elsewhere"), scan.Options{SameDevice: true}); files != "b.mp3,film.mp4" {
+		t.Error("Unexpected files", files)
+	}
+}
+
+func Test_scan_004(t *testing.T) {
+	src := memory{
+		"music/a.mp3":          "a",
+		"tv/.nomedia":          "",
+		"tv/episode.mkv":       "b",
+		"movies/film.mp4":      "c",
+		"movies/film.mp4.part": "d",
+	}
+	files := make([]string, 0)
+	if err := scan.WalkSource(context.Background(), src, "/", scan.Options{Exclude: scan.DefaultExclude}, func(path string, info os.FileInfo) error {
+		files = append(files, path)
+		return nil
+	}); err != nil {
+		t.Error(err)
+	} else if strings.Join(files, ",") != "movies/film.mp4,music/a.mp3" {
+		t.Error("Unexpected files", files)
+	}
+}
+
+func Test_scan_005(t *testing.T) {
+	src := memory{
+		"music/a.mp3": "a",
+		"music/b.mp3": "b",
+		"music/c.mp3": "c",
+	}
+
+	// Cancelling the context stops the walk
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	files := make([]string, 0)
+	if err := scan.WalkSource(ctx, src, "/", scan.Options{}, func(path string, info os.FileInfo) error {
+		files = append(files, path)
+		cancel()
+		return nil
+	}); err != context.Canceled {
+		t.Error("Expected context.Canceled, got", err)
+	} else if strings.Join(files, ",") != "music/a.mp3" {
+		t.Error("Unexpected files", files)
+	}
+
+	// A cancelled context does not start the walk
+	if err := scan.WalkSource(ctx, src, "/", scan.Options{}, nil); err != context.Canceled {
+		t.Error("Expected context.Canceled, got", err)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// memory is a source of files in memory, with folders implied by paths
+type memory map[string]string
+
+type memoryInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (this memory) Stat(name string) (os.FileInfo, error) {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	if data, exists := this[name]; exists {
+		return &memoryInfo{path.Base(name), int64(len(data)), false}, nil
+	}
+	for key := range this {
+		if name == "" || strings.HasPrefix(key, name+"/") {
+			return &memoryInfo{path.Base("/" + name), 0, true}, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+func (this memory) ReadDir(name string) ([]os.FileInfo, error) {
+	prefix := strings.Trim(path.Clean("/"+name), "/")
+	if prefix != "" {
+		prefix = prefix + "/"
+	}
+	names := make(map[string]bool)
+	for key := range this {
+		if strings.HasPrefix(key, prefix) {
+			names[strings.SplitN(key[len(prefix):], "/", 2)[0]] = true
+		}
+	}
+	infos := make([]os.FileInfo, 0, len(names))
+	for name := range names {
+		if info, err := this.Stat(prefix + name); err == nil {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+func (this memory) Open(name string) (media.SourceFile, error) {
+	return nil, gopi.ErrNotImplemented
+}
+
+func (this *memoryInfo) Name() string       { return this.name }
+func (this *memoryInfo) Size() int64        { return this.size }
+func (this *memoryInfo) ModTime() time.Time { return time.Time{} }
+func (this *memoryInfo) IsDir() bool        { return this.dir }
+func (this *memoryInfo) Sys() interface{}   { return nil }
+
+func (this *memoryInfo) Mode() os.FileMode {
+	if this.dir {
+		return os.ModeDir | 0555
+	} else {
+		return 0444
+	}
+}
+
+func tree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		} else if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func walk(t *testing.T, root string, opts scan.Options) string {
+	t.Helper()
+	files := make([]string, 0)
+	if err := scan.Walk(context.Background(), root, opts, func(path string, info os.FileInfo) error {
+		if rel, err := filepath.Rel(root, path); err != nil {
+			return err
+		} else {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	}); err != nil {
+		t.Error(err)
+	}
+	return strings.Join(files, ",")
+}
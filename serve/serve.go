@@ -0,0 +1,195 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package serve
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	source "github.com/djthorpe/gopi-media/source"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Handler serves media files by identifier, with byte range requests
+// and conditional requests, so files are addressed without exposing
+// their paths. The request path is the identifier, so the handler is
+// usually wrapped with http.StripPrefix. The zero value serves no
+// files and is safe for concurrent use.
+type Handler struct {
+	lock  sync.RWMutex
+	items map[string]item
+}
+
+type item struct {
+	src      media.Source
+	name     string
+	mimetype string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_MIMETYPE = "application/octet-stream"
+)
+
+var (
+	// Types which are missing from the system mimetype tables
+	mimetypes = map[string]string{
+		".mp4":  "video/mp4",
+		".m4v":  "video/x-m4v",
+		".mov":  "video/quicktime",
+		".mkv":  "video/x-matroska",
+		".ts":   "video/mp2t",
+		".m2ts": "video/mp2t",
+		".m2v":  "video/mpeg",
+		".vob":  "video/mpeg",
+		".mp3":  "audio/mpeg",
+		".aac":  "audio/aac",
+		".m4a":  "audio/mp4",
+		".m4b":  "audio/mp4",
+		".m4r":  "audio/mp4",
+		".flac": "audio/flac",
+		".wav":  "audio/wav",
+		".jpg":  "image/jpeg",
+		".jpeg": "image/jpeg",
+		".tif":  "image/tiff",
+		".tiff": "image/tiff",
+		".srt":  "application/x-subrip",
+		".vtt":  "text/vtt",
+		".cbz":  "application/vnd.comicbook+zip",
+		".cbr":  "application/vnd.comicbook-rar",
+		".epub": "application/epub+zip",
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// ITEMS
+
+// Add makes a local file available by its identifier, replacing any
+// file with the same identifier
+func (this *Handler) Add(file media.MediaFile) error {
+	if file == nil || file.Filename() == "" {
+		return gopi.ErrBadParameter
+	}
+	return this.AddSource(file, source.Local{Root: file.Filename()}, ".")
+}
+
+// AddSource makes a file available by its identifier when it is read
+// from a source, such as a network share or cloud storage bucket
+func (this *Handler) AddSource(file media.MediaFile, src media.Source, name string) error {
+	if file == nil || file.Id() == "" || src == nil || name == "" {
+		return gopi.ErrBadParameter
+	}
+	mimetype := MimeTypeFor(name)
+	if local, ok := src.(source.Local); ok {
+		mimetype = MimeTypeFor(local.Path(name))
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.items == nil {
+		this.items = make(map[string]item)
+	}
+	this.items[file.Id()] = item{src, name, mimetype}
+	return nil
+}
+
+// Remove makes a file unavailable, returning gopi.ErrNotFound if
+// there is no file with the identifier
+func (this *Handler) Remove(id string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if _, exists := this.items[id]; exists == false {
+		return gopi.ErrNotFound
+	}
+	delete(this.items, id)
+	return nil
+}
+
+// MimeTypeFor returns the mimetype for a filename
+func MimeTypeFor(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if mimetype, exists := mimetypes[ext]; exists {
+		return mimetype
+	} else if mimetype := mime.TypeByExtension(ext); mimetype != "" {
+		return mimetype
+	} else {
+		return DEFAULT_MIMETYPE
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HTTP HANDLER
+
+// ServeHTTP serves the file for the identifier in the request path. The
+// ETag changes when the file is modified, so clients revalidate cached
+// ranges.
+func (this *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(req.URL.Path, "/")
+	this.lock.RLock()
+	item, exists := this.items[id]
+	this.lock.RUnlock()
+	if exists == false {
+		http.NotFound(w, req)
+		return
+	}
+
+	stat, err := item.src.Stat(item.name)
+	if os.IsNotExist(err) || err == gopi.ErrNotFound || (err == nil && stat.Mode().IsRegular() == false) {
+		http.NotFound(w, req)
+		return
+	} else if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	fh, err := item.src.Open(item.name)
+	if os.IsNotExist(err) || err == gopi.ErrNotFound {
+		http.NotFound(w, req)
+		return
+	} else if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer fh.Close()
+
+	w.Header().Set("Content-Type", item.mimetype)
+	w.Header().Set("ETag", fmt.Sprintf("\"%x-%x\"", stat.ModTime().UnixNano(), stat.Size()))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	// ServeContent handles range, If-Range, If-None-Match and
+	// If-Modified-Since requests. The name is only used when there
+	// is no Content-Type, so the path is not exposed.
+	http.ServeContent(w, req, "", stat.ModTime(), fh)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Handler) String() string {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return fmt.Sprintf("<serve.Handler>{ items=%v }", len(this.items))
+}
@@ -0,0 +1,172 @@
+package serve_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	serve "github.com/djthorpe/gopi-media/serve"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST HANDLER
+
+func Test_serve_000(t *testing.T) {
+	t.Log("Test_serve_000")
+}
+
+func Test_serve_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "serve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "movie.mkv")
+	if err := ioutil.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := new(serve.Handler)
+	if err := handler.Add(&mock.File{Path: path, Identifier: "abc"}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.StripPrefix("/item", handler))
+	defer server.Close()
+
+	// Full request
+	resp, err := http.Get(server.URL + "/item/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "video/x-matroska" {
+		t.Error("Unexpected response", resp.Status, resp.Header)
+	} else if etag == "" || resp.Header.Get("Last-Modified") == "" || resp.Header.Get("Accept-Ranges") != "bytes" {
+		t.Error("Missing headers", resp.Header)
+	}
+
+	// Range request
+	req, _ := http.NewRequest("GET", server.URL+"/item/abc", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	if resp, err := http.DefaultClient.Do(req); err != nil {
+		t.Error(err)
+	} else {
+		data, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusPartialContent || string(data) != "234" || resp.Header.Get("Content-Range") != "bytes 2-4/10" {
+			t.Error("Unexpected range response", resp.Status, string(data))
+		}
+	}
+
+	// Conditional request
+	req, _ = http.NewRequest("GET", server.URL+"/item/abc", nil)
+	req.Header.Set("If-None-Match", etag)
+	if resp, err := http.DefaultClient.Do(req); err != nil {
+		t.Error(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusNotModified {
+		t.Error("Unexpected conditional response", resp.Status)
+	}
+
+	// Unknown identifier and method
+	if resp, err := http.Get(server.URL + "/item/missing"); err != nil {
+		t.Error(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusNotFound {
+		t.Error("Unexpected response", resp.Status)
+	}
+	if resp, err := http.Post(server.URL+"/item/abc", "text/plain", nil); err != nil {
+		t.Error(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Error("Unexpected response", resp.Status)
+	}
+	if err := handler.Remove("abc"); err != nil {
+		t.Error(err)
+	} else if err := handler.Remove("abc"); err == nil {
+		t.Error("Expected error removing missing item")
+	}
+}
+
+func Test_serve_002(t *testing.T) {
+	handler := new(serve.Handler)
+	src := memory{"music/a.mp3": "0123456789"}
+	if err := handler.AddSource(&mock.File{Path: "a.mp3", Identifier: "abc"}, src, "music/a.mp3"); err != nil {
+		t.Fatal(err)
+	} else if err := handler.AddSource(&mock.File{Path: "b.mp3", Identifier: "def"}, src, "music/b.mp3"); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Range request for a file in a source
+	req, _ := http.NewRequest("GET", server.URL+"/abc", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	if resp, err := http.DefaultClient.Do(req); err != nil {
+		t.Error(err)
+	} else {
+		data, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusPartialContent || string(data) != "234" || resp.Header.Get("Content-Type") != "audio/mpeg" {
+			t.Error("Unexpected range response", resp.Status, string(data), resp.Header)
+		}
+	}
+
+	// Missing file in a source
+	if resp, err := http.Get(server.URL + "/def"); err != nil {
+		t.Error(err)
+	} else if resp.Body.Close(); resp.StatusCode != http.StatusNotFound {
+		t.Error("Unexpected response", resp.Status)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// memory is a source of files held in memory
+type memory map[string]string
+
+type memoryFile struct {
+	*bytes.Reader
+}
+
+type memoryInfo struct {
+	name string
+	size int64
+}
+
+func (this memory) Stat(name string) (os.FileInfo, error) {
+	if data, exists := this[name]; exists == false {
+		return nil, gopi.ErrNotFound
+	} else {
+		return &memoryInfo{filepath.Base(name), int64(len(data))}, nil
+	}
+}
+
+func (this memory) ReadDir(name string) ([]os.FileInfo, error) {
+	return nil, gopi.ErrNotImplemented
+}
+
+func (this memory) Open(name string) (media.SourceFile, error) {
+	if data, exists := this[name]; exists == false {
+		return nil, gopi.ErrNotFound
+	} else {
+		return memoryFile{bytes.NewReader([]byte(data))}, nil
+	}
+}
+
+func (memoryFile) Close() error { return nil }
+
+func (this *memoryInfo) Name() string       { return this.name }
+func (this *memoryInfo) Size() int64        { return this.size }
+func (this *memoryInfo) Mode() os.FileMode  { return 0644 }
+func (this *memoryInfo) ModTime() time.Time { return time.Time{} }
+func (this *memoryInfo) IsDir() bool        { return false }
+func (this *memoryInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,52 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package media
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Source is a tree of files, such as a local folder, a network share or
+// a cloud storage bucket. Paths are relative to the root of the source
+// and use forward slashes.
+type Source interface {
+	// Return information about a file or folder, following links
+	Stat(path string) (os.FileInfo, error)
+
+	// Return the files and folders within a folder sorted by name,
+	// without following links
+	ReadDir(path string) ([]os.FileInfo, error)
+
+	// Open a file for reading
+	Open(path string) (SourceFile, error)
+}
+
+// SourceFile is a file opened from a source, which can be read
+// sequentially or at any offset
+type SourceFile interface {
+	io.ReadSeeker
+	io.ReaderAt
+	io.Closer
+}
+
+// SourceMedia is implemented by drivers which can open media files
+// from any source rather than only from local paths
+type SourceMedia interface {
+	Media
+
+	// Open a media file from a source, returning the context error if
+	// the context is cancelled or its deadline passes first
+	OpenSource(context.Context, Source, string) (MediaFile, error)
+}
@@ -0,0 +1,62 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package source
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Local is a source for a folder on local disk, or a single file when
+// the root is a file
+type Local struct {
+	Root string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+func (this Local) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(this.Path(name))
+}
+
+func (this Local) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(this.Path(name))
+}
+
+func (this Local) Open(name string) (media.SourceFile, error) {
+	if file, err := os.Open(this.Path(name)); err != nil {
+		return nil, err
+	} else {
+		return file, nil
+	}
+}
+
+// Path returns the local filename for a path within the source
+func (this Local) Path(name string) string {
+	return filepath.Join(this.Root, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Local) String() string {
+	return fmt.Sprintf("<source.Local>{ root=%v }", strconv.Quote(this.Root))
+}
@@ -0,0 +1,112 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// OpenFunc returns a source for a URL
+type OpenFunc func(*url.URL) (media.Source, error)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	lock    sync.Mutex
+	schemes = map[string]OpenFunc{
+		"file": openLocal,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// REGISTER AND OPEN
+
+// Register makes a source available with a URL scheme, replacing any
+// existing source for the scheme. It is usually called from the init
+// function of the package implementing the source.
+func Register(scheme string, fn OpenFunc) {
+	lock.Lock()
+	defer lock.Unlock()
+	schemes[strings.ToLower(scheme)] = fn
+}
+
+// Schemes returns the registered URL schemes, sorted by name
+func Schemes() []string {
+	lock.Lock()
+	defer lock.Unlock()
+	result := make([]string, 0, len(schemes))
+	for scheme := range schemes {
+		result = append(result, scheme)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Open returns a source for a URL, or a local folder when the name is
+// a path rather than a URL. It returns gopi.ErrNotFound if the URL
+// scheme has not been registered.
+func Open(name string) (media.Source, error) {
+	if u, err := url.Parse(name); err != nil || len(u.Scheme) < 2 {
+		// Local paths, including Windows paths with a drive letter
+		return Local{Root: name}, nil
+	} else {
+		lock.Lock()
+		fn, exists := schemes[strings.ToLower(u.Scheme)]
+		lock.Unlock()
+		if exists == false {
+			return nil, gopi.ErrNotFound
+		}
+		return fn(u)
+	}
+}
+
+// OpenMedia opens a media file from a source with a driver, in the
+// same way as util.OpenContext. Drivers which implement
+// media.SourceMedia can open files from any source, and other drivers
+// can only open files from a local source.
+func OpenMedia(ctx context.Context, driver media.Media, src media.Source, name string) (media.MediaFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	} else if driver_, ok := driver.(media.SourceMedia); ok {
+		return driver_.OpenSource(ctx, src, name)
+	} else if local, ok := src.(Local); ok {
+		return util.OpenContext(ctx, driver, local.Path(name))
+	} else {
+		return nil, gopi.ErrNotImplemented
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func openLocal(u *url.URL) (media.Source, error) {
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, gopi.ErrBadParameter
+	} else if u.Path == "" {
+		return nil, fmt.Errorf("Missing path: %v", u)
+	} else {
+		return Local{Root: filepath.FromSlash(u.Path)}, nil
+	}
+}
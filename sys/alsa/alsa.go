@@ -0,0 +1,269 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package alsa
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	al "github.com/djthorpe/gopi-media/alsa"
+	event "github.com/djthorpe/gopi/util/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Config struct {
+	// Card and device numbers for the output, or the name of the
+	// output, for example "Headphones", "HDMI" or "USB", which is used
+	// instead of the numbers when set
+	Card, Device uint
+	Name         string
+
+	// Initial format, which is changed to the nearest supported format
+	Format media.AudioFormat
+
+	// Size of the device buffer
+	Buffer time.Duration
+}
+
+type sink struct {
+	event.Publisher
+	lock sync.Mutex
+
+	log     gopi.Logger
+	device  al.Device
+	buffer  time.Duration
+	pcm     *al.PCM
+	pending []byte
+}
+
+type underrun struct {
+	source gopi.Driver
+	format media.AudioFormat
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_RATE     = 48000
+	DEFAULT_CHANNELS = 2
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
+	logger.Debug("<alsa.Open>{ config=%+v }", config)
+
+	this := new(sink)
+	this.log = logger
+	this.buffer = config.Buffer
+
+	// Select the device
+	if devices, err := al.Devices(); err != nil {
+		return nil, err
+	} else if config.Name != "" {
+		if device, err := al.DeviceForName(devices, config.Name); err != nil {
+			return nil, err
+		} else {
+			this.device = device
+		}
+	} else {
+		this.device = al.Device{Card: config.Card, Device: config.Device}
+		for _, device := range devices {
+			if device.Card == config.Card && device.Device == config.Device {
+				this.device = device
+			}
+		}
+	}
+
+	// Open the device and set the format
+	format := config.Format
+	if format.Format == media.SAMPLE_FORMAT_NONE {
+		format.Format = media.SAMPLE_FORMAT_S16
+	}
+	if format.Rate == 0 {
+		format.Rate = DEFAULT_RATE
+	}
+	if format.Channels == 0 {
+		format.Channels = DEFAULT_CHANNELS
+	}
+	if pcm, err := al.OpenPCM(this.device.Card, this.device.Device); err != nil {
+		return nil, err
+	} else if _, err := pcm.SetFormat(format, this.buffer); err != nil {
+		pcm.Close()
+		return nil, err
+	} else {
+		this.pcm = pcm
+	}
+
+	this.log.Debug("Opened %v", this.pcm)
+
+	// Success
+	return this, nil
+}
+
+func (this *sink) Close() error {
+	this.log.Debug("<alsa.Close>{ }")
+
+	// Stop any blocked write
+	this.pcm.Stop()
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Close subscriber channels
+	this.Publisher.Close()
+
+	// Release resources
+	err := this.pcm.Close()
+	this.pending = nil
+
+	// Return success
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *sink) String() string {
+	return fmt.Sprintf("<alsa>{ card=%v device=%v name=%v format=%v }", this.device.Card, this.device.Device, strconv.Quote(this.device.Name), this.pcm.Format())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIO SINK INTERFACE IMPLEMENTATION
+
+func (this *sink) SetFormat(format media.AudioFormat) (media.AudioFormat, error) {
+	this.log.Debug2("<alsa.SetFormat>{ format=%v }", format)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Audio in the old format is discarded
+	this.pending = this.pending[:0]
+	if err := this.pcm.Stop(); err != nil {
+		return media.AudioFormat{}, err
+	}
+	return this.pcm.SetFormat(format, this.buffer)
+}
+
+func (this *sink) Format() media.AudioFormat {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.pcm.Format()
+}
+
+func (this *sink) Write(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Complete a partial frame from the last write
+	n := len(data)
+	size := int(this.pcm.Format().BytesPerFrame())
+	if len(this.pending) > 0 {
+		m := size - len(this.pending)
+		if m > len(data) {
+			m = len(data)
+		}
+		this.pending = append(this.pending, data[:m]...)
+		data = data[m:]
+		if len(this.pending) < size {
+			return n, nil
+		} else if err := this.write(this.pending); err != nil {
+			return 0, err
+		}
+		this.pending = this.pending[:0]
+	}
+
+	// Write whole frames and keep the remainder
+	whole := len(data) - len(data)%size
+	if err := this.write(data[:whole]); err != nil {
+		return n - len(data), err
+	}
+	this.pending = append(this.pending, data[whole:]...)
+
+	// Success
+	return n, nil
+}
+
+func (this *sink) Drain() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// A partial frame is discarded
+	this.pending = this.pending[:0]
+	if err := this.pcm.Drain(); err != nil {
+		return err
+	}
+	return this.pcm.Prepare()
+}
+
+func (this *sink) Stop() error {
+	// Stop without the lock so a blocked write returns
+	if err := this.pcm.Stop(); err != nil {
+		return err
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.pending = this.pending[:0]
+	return this.pcm.Prepare()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIO EVENT IMPLEMENTATION
+
+func (this *underrun) Source() gopi.Driver {
+	return this.source
+}
+
+func (this *underrun) Name() string {
+	return "AudioEvent"
+}
+
+func (this *underrun) Format() media.AudioFormat {
+	return this.format
+}
+
+func (this *underrun) String() string {
+	return fmt.Sprintf("<alsa.Event>{ underrun format=%v }", this.format)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// write writes whole frames, restarting playback and emitting an event
+// when the device runs out of audio
+func (this *sink) write(data []byte) error {
+	for len(data) > 0 {
+		n, err := this.pcm.Write(data)
+		if al.IsUnderrun(err) {
+			this.log.Debug("Underrun")
+			this.Emit(&underrun{this, this.pcm.Format()})
+			if err := this.pcm.Prepare(); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
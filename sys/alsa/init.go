@@ -0,0 +1,52 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package alsa
+
+import (
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	gopi.RegisterModule(gopi.Module{
+		Name: "alsa",
+		Type: gopi.MODULE_TYPE_OTHER,
+		Config: func(config *gopi.AppConfig) {
+			config.AppFlags.FlagUint("alsa.card", 0, "Sound card number")
+			config.AppFlags.FlagUint("alsa.device", 0, "PCM device number")
+			config.AppFlags.FlagString("alsa.name", "", "Output name, for example Headphones, HDMI or USB")
+			config.AppFlags.FlagUint("alsa.rate", DEFAULT_RATE, "Sample rate")
+			config.AppFlags.FlagDuration("alsa.buffer", 500*time.Millisecond, "Size of the device buffer")
+		},
+		New: func(app *gopi.AppInstance) (gopi.Driver, error) {
+			card, _ := app.AppFlags.GetUint("alsa.card")
+			device, _ := app.AppFlags.GetUint("alsa.device")
+			name, _ := app.AppFlags.GetString("alsa.name")
+			rate, _ := app.AppFlags.GetUint("alsa.rate")
+			buffer, _ := app.AppFlags.GetDuration("alsa.buffer")
+			return gopi.Open(Config{
+				Card:   card,
+				Device: device,
+				Name:   name,
+				Format: media.AudioFormat{Format: media.SAMPLE_FORMAT_S16, Rate: rate, Channels: DEFAULT_CHANNELS},
+				Buffer: buffer,
+			}, app.Logger)
+		},
+	})
+}
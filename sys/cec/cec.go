@@ -0,0 +1,209 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package cec
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	cc "github.com/djthorpe/gopi-media/cec"
+	event "github.com/djthorpe/gopi/util/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Config struct {
+	// Adapter number
+	Device uint
+
+	// Name shown by the TV for the input
+	Name string
+}
+
+type display struct {
+	event.Publisher
+
+	log    gopi.Logger
+	name   string
+	device *cc.Device
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+type remote struct {
+	source  gopi.Driver
+	key     media.RemoteKey
+	pressed bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_NAME = "gopi-media"
+
+	// Interval for checking whether the driver is closed while
+	// receiving messages
+	RECEIVE_TIMEOUT = time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
+	logger.Debug("<cec.Open>{ config=%+v }", config)
+
+	this := new(display)
+	this.log = logger
+	this.name = config.Name
+	if this.name == "" {
+		this.name = DEFAULT_NAME
+	}
+
+	if device, err := cc.OpenDevice(config.Device, this.name); err != nil {
+		return nil, err
+	} else {
+		this.device = device
+	}
+
+	// Receive messages in the background
+	this.stop = make(chan struct{})
+	this.done = make(chan struct{})
+	go this.receive()
+
+	// Success
+	return this, nil
+}
+
+func (this *display) Close() error {
+	this.log.Debug("<cec.Close>{ }")
+
+	// Stop receiving messages
+	close(this.stop)
+	<-this.done
+
+	// Close subscriber channels
+	this.Publisher.Close()
+
+	// Release resources
+	return this.device.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *display) String() string {
+	return fmt.Sprintf("<cec>{ device=%v name=%v }", this.device, strconv.Quote(this.name))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// DISPLAY INTERFACE IMPLEMENTATION
+
+func (this *display) PowerOn() error {
+	this.log.Debug2("<cec.PowerOn>{ }")
+
+	addr, err := this.device.LogicalAddress()
+	if err != nil {
+		return err
+	}
+	physical, err := this.device.PhysicalAddress()
+	if err != nil {
+		return err
+	}
+
+	// Turn the TV on, then make this the active source so the TV
+	// switches to the input
+	if msg, err := cc.NewMessage(addr, cc.ADDR_TV, cc.OP_IMAGE_VIEW_ON); err != nil {
+		return err
+	} else if err := this.device.Transmit(msg); err != nil {
+		return err
+	}
+	return this.device.Transmit(cc.ActiveSource(addr, physical))
+}
+
+func (this *display) Standby() error {
+	this.log.Debug2("<cec.Standby>{ }")
+
+	if addr, err := this.device.LogicalAddress(); err != nil {
+		return err
+	} else if msg, err := cc.NewMessage(addr, cc.ADDR_TV, cc.OP_STANDBY); err != nil {
+		return err
+	} else {
+		return this.device.Transmit(msg)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// REMOTE EVENT IMPLEMENTATION
+
+func (this *remote) Source() gopi.Driver {
+	return this.source
+}
+
+func (this *remote) Name() string {
+	return "RemoteEvent"
+}
+
+func (this *remote) Key() media.RemoteKey {
+	return this.key
+}
+
+func (this *remote) Pressed() bool {
+	return this.pressed
+}
+
+func (this *remote) String() string {
+	return fmt.Sprintf("<cec.Event>{ key=%v pressed=%v }", this.key, this.pressed)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// receive emits events for key messages until the driver is closed. The
+// release message has no key, so it is reported for the last key pressed.
+func (this *display) receive() {
+	defer close(this.done)
+	last := media.REMOTE_KEY_NONE
+	for {
+		select {
+		case <-this.stop:
+			return
+		default:
+		}
+		msg, err := this.device.Receive(RECEIVE_TIMEOUT)
+		if err == gopi.ErrDeadlineExceeded {
+			continue
+		} else if err != nil {
+			this.log.Warn("Receive: %v", err)
+			select {
+			case <-this.stop:
+				return
+			case <-time.After(RECEIVE_TIMEOUT):
+				continue
+			}
+		}
+		this.log.Debug2("Received %v", msg)
+		if key, pressed := msg.Key(); pressed && key != media.REMOTE_KEY_NONE {
+			last = key
+			this.Emit(&remote{this, key, true})
+		} else if opcode, _ := msg.Opcode(); opcode == cc.OP_USER_CONTROL_RELEASED && last != media.REMOTE_KEY_NONE {
+			this.Emit(&remote{this, last, false})
+			last = media.REMOTE_KEY_NONE
+		}
+	}
+}
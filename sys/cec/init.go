@@ -0,0 +1,40 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package cec
+
+import (
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	gopi.RegisterModule(gopi.Module{
+		Name: "cec",
+		Type: gopi.MODULE_TYPE_OTHER,
+		Config: func(config *gopi.AppConfig) {
+			config.AppFlags.FlagUint("cec.device", 0, "CEC adapter number")
+			config.AppFlags.FlagString("cec.name", DEFAULT_NAME, "Name shown by the TV for the input")
+		},
+		New: func(app *gopi.AppInstance) (gopi.Driver, error) {
+			device, _ := app.AppFlags.GetUint("cec.device")
+			name, _ := app.AppFlags.GetString("cec.name")
+			return gopi.Open(Config{
+				Device: device,
+				Name:   name,
+			}, app.Logger)
+		},
+	})
+}
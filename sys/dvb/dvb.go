@@ -0,0 +1,502 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package dvb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	dv "github.com/djthorpe/gopi-media/dvb"
+	epg "github.com/djthorpe/gopi-media/epg"
+	errors "github.com/djthorpe/gopi/util/errors"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Config struct {
+	// Adapter and frontend numbers for the device. The demux with
+	// the same number as the frontend is used to read tables
+	Adapter  uint
+	Frontend uint
+
+	// Timeout for locking and for reading each table
+	Timeout time.Duration
+
+	// Multiplexes which are searched when recording a service which
+	// has not been seen on a tuned multiplex
+	Multiplexes []media.TuneParams
+}
+
+type tuner struct {
+	log         gopi.Logger
+	adapter     uint
+	device      uint
+	timeout     time.Duration
+	multiplexes []media.TuneParams
+	frontend    *dv.Frontend
+
+	// The lock is held while tuning and reading from the demux, and
+	// seen is the multiplex each service was last seen on
+	lock   sync.Mutex
+	demux  *dv.Demux
+	tuned  bool
+	params media.TuneParams
+	seen   map[string]media.TuneParams
+}
+
+type service struct {
+	id      string
+	pmt     uint16
+	service dv.Service
+	streams []media.MediaStream
+}
+
+type stream struct {
+	stream dv.Stream
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_TIMEOUT = 5 * time.Second
+
+	// Size of transport stream reads, which is a multiple of the
+	// packet size
+	RECORD_BUFFER_SIZE = 188 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
+	logger.Debug("<dvb.Open>{ config=%+v }", config)
+
+	this := new(tuner)
+	this.log = logger
+	this.adapter = config.Adapter
+	this.device = config.Frontend
+	this.timeout = config.Timeout
+	if this.timeout == 0 {
+		this.timeout = DEFAULT_TIMEOUT
+	}
+	this.multiplexes = config.Multiplexes
+	this.seen = make(map[string]media.TuneParams)
+
+	if frontend, err := dv.OpenFrontend(config.Adapter, config.Frontend); err != nil {
+		return nil, err
+	} else if demux, err := dv.OpenDemux(config.Adapter, config.Frontend); err != nil {
+		frontend.Close()
+		return nil, err
+	} else {
+		this.frontend = frontend
+		this.demux = demux
+	}
+
+	// Success
+	return this, nil
+}
+
+func (this *tuner) Close() error {
+	this.log.Debug("<dvb.Close>{ }")
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	var err errors.CompoundError
+
+	err.Add(this.demux.Close())
+	err.Add(this.frontend.Close())
+
+	// Return success
+	return err.ErrorOrSelf()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *tuner) String() string {
+	return fmt.Sprintf("<dvb>{ timeout=%v multiplexes=%v }", this.timeout, len(this.multiplexes))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TUNER INTERFACE IMPLEMENTATION
+
+func (this *tuner) Tune(params media.TuneParams) error {
+	this.log.Debug2("<dvb.Tune>{ params=%v }", params)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.tune(params)
+}
+
+func (this *tuner) Services() ([]media.TunerService, error) {
+	this.log.Debug2("<dvb.Services>{ }")
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if services, err := this.services(); err != nil {
+		return nil, err
+	} else {
+		result := make([]media.TunerService, len(services))
+		for i, service := range services {
+			result[i] = service
+		}
+		return result, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RECORDER INTERFACE IMPLEMENTATION
+
+// Record writes the transport stream for a service, tuning to the
+// multiplex the service was last seen on, or searching the configured
+// multiplexes, when it is not on the tuned multiplex
+func (this *tuner) Record(id string, w io.Writer, stop <-chan struct{}) error {
+	this.log.Debug2("<dvb.Record>{ service=%v }", id)
+
+	// Find the service and tap the program association table, program
+	// map and streams
+	item, demuxes, err := this.tap(id)
+	for _, demux := range demuxes {
+		defer demux.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Copy the transport stream until stopped, closing the device
+	// to interrupt any read in progress
+	dvr, err := dv.OpenDVR(this.adapter, this.device)
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+		case <-done:
+		}
+		dvr.Close()
+	}()
+	buf := make([]byte, RECORD_BUFFER_SIZE)
+	for {
+		n, err := dvr.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-stop:
+			return nil
+		default:
+			if err == syscall.EOVERFLOW || isOverflow(err) {
+				this.log.Warn("%v: Buffer overflow", item.Title())
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// EPG SOURCE IMPLEMENTATION
+
+// ReadGuide reads event information tables from the tuned multiplex into
+// the guide, until no new sections are received within the timeout
+func (this *tuner) ReadGuide(guide *epg.Guide, timeout time.Duration) error {
+	this.log.Debug2("<dvb.ReadGuide>{ timeout=%v }", timeout)
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	// Table identifiers 0x40 to 0x5F, which are filtered to the
+	// event information tables for the actual transport stream
+	if err := this.demux.Filter(dv.PID_EIT, 0x40, 0xE0, timeout); err != nil {
+		return err
+	}
+	defer this.demux.Stop()
+
+	// Read sections until no new sections are received
+	seen := make(map[string]bool)
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); {
+		section, err := this.demux.Next()
+		if err == gopi.ErrDeadlineExceeded {
+			break
+		} else if err != nil {
+			return err
+		} else if section.IsEventTable() == false {
+			continue
+		}
+		key := fmt.Sprint(section.TableId, section.Extension, section.Version, section.Number)
+		if seen[key] {
+			continue
+		} else if err := guide.AddSection(section); err != nil {
+			this.log.Warn("%v: %v", section, err)
+		}
+		seen[key] = true
+		deadline = time.Now().Add(timeout)
+	}
+
+	// Success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TUNE AND READ SERVICES
+
+// tune tunes the frontend and waits for it to lock, with the lock held
+func (this *tuner) tune(params media.TuneParams) error {
+	this.tuned = false
+	if err := this.frontend.Tune(params); err != nil {
+		return err
+	} else if err := this.frontend.WaitForLock(this.timeout); err != nil {
+		return err
+	} else {
+		this.tuned = true
+		this.params = params
+		return nil
+	}
+}
+
+// tap finds a service and opens a demux for each table and stream to
+// record. The demuxes are returned to be closed by the caller even
+// when an error is returned.
+func (this *tuner) tap(id string) (*service, []*dv.Demux, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	item, err := this.find(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	pids := []uint16{dv.PID_PAT, item.pmt}
+	for _, s := range item.streams {
+		pids = append(pids, s.(*stream).stream.Pid)
+	}
+	demuxes := make([]*dv.Demux, 0, len(pids))
+	for _, pid := range pids {
+		demux, err := dv.OpenDemux(this.adapter, this.device)
+		if err != nil {
+			return nil, demuxes, err
+		}
+		demuxes = append(demuxes, demux)
+		if err := demux.Tap(pid); err != nil {
+			return nil, demuxes, err
+		}
+	}
+	return item, demuxes, nil
+}
+
+// find returns a service from the tuned multiplex, retuning to the
+// multiplex it was last seen on or searching the configured multiplexes
+// when it is not found. It returns gopi.ErrNotFound if the service is
+// not found on any multiplex.
+func (this *tuner) find(id string) (*service, error) {
+	if item := this.lookup(id); item != nil {
+		return item, nil
+	}
+	multiplexes := make([]media.TuneParams, 0, len(this.multiplexes)+1)
+	if params, exists := this.seen[id]; exists {
+		multiplexes = append(multiplexes, params)
+	}
+	multiplexes = append(multiplexes, this.multiplexes...)
+	searched := map[media.TuneParams]bool{}
+	if this.tuned {
+		searched[this.params] = true
+	}
+	for _, params := range multiplexes {
+		if searched[params] {
+			continue
+		}
+		searched[params] = true
+		if err := this.tune(params); err != nil {
+			this.log.Warn("%v: %v", params, err)
+		} else if item := this.lookup(id); item != nil {
+			return item, nil
+		}
+	}
+	return nil, gopi.ErrNotFound
+}
+
+// lookup returns a service with a program map on the tuned multiplex,
+// or nil if it is not found
+func (this *tuner) lookup(id string) *service {
+	if services, err := this.services(); err != nil {
+		this.log.Debug("%v: %v", id, err)
+	} else {
+		for _, item := range services {
+			if item.id == id && item.pmt != 0 {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+// services reads the services on the tuned multiplex, with the lock held
+func (this *tuner) services() ([]*service, error) {
+	// Read the program association table
+	pat, err := this.demux.Table(dv.PID_PAT, dv.TABLE_PAT, this.timeout)
+	if err != nil {
+		return nil, err
+	}
+	programs := make(map[uint16]uint16)
+	for _, section := range pat {
+		if entries, err := section.Programs(); err != nil {
+			return nil, err
+		} else {
+			for _, program := range entries {
+				programs[program.Number] = program.Pid
+			}
+		}
+	}
+
+	// Read the service description table, which is not carried by
+	// all multiplexes
+	services := make([]dv.Service, 0, len(programs))
+	if sdt, err := this.demux.Table(dv.PID_SDT, dv.TABLE_SDT_ACTUAL, this.timeout); err == gopi.ErrDeadlineExceeded {
+		this.log.Warn("Service description table not received")
+		for number := range programs {
+			services = append(services, dv.Service{Id: number, TransportStreamId: pat[0].Extension})
+		}
+	} else if err != nil {
+		return nil, err
+	} else {
+		for _, section := range sdt {
+			if entries, err := section.Services(); err != nil {
+				return nil, err
+			} else {
+				services = append(services, entries...)
+			}
+		}
+	}
+
+	// Read the program map for each service
+	result := make([]*service, 0, len(services))
+	for _, s := range services {
+		item := &service{
+			id:      dv.Identifier(s.OriginalNetworkId, s.TransportStreamId, s.Id),
+			service: s,
+		}
+		if pid, exists := programs[s.Id]; exists == false {
+			this.log.Debug("Service 0x%04X: No program map", s.Id)
+		} else if pmt, err := this.demux.TableForExtension(pid, dv.TABLE_PMT, s.Id, this.timeout); err != nil {
+			this.log.Warn("Service 0x%04X: %v", s.Id, err)
+		} else {
+			item.pmt = pid
+			for _, section := range pmt {
+				if program, err := section.ProgramMap(); err != nil {
+					this.log.Warn("Service 0x%04X: %v", s.Id, err)
+				} else {
+					for _, es := range program.Streams {
+						item.streams = append(item.streams, &stream{es})
+					}
+				}
+			}
+		}
+		if this.tuned {
+			this.seen[item.id] = this.params
+		}
+		result = append(result, item)
+	}
+
+	// Success
+	return result, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// TUNERSERVICE INTERFACE IMPLEMENTATION
+
+func (this *service) Id() string {
+	return this.id
+}
+
+func (this *service) ServiceId() uint16 {
+	return this.service.Id
+}
+
+func (this *service) Title() string {
+	if this.service.Name != "" {
+		return this.service.Name
+	} else {
+		return fmt.Sprintf("Service %v", this.service.Id)
+	}
+}
+
+func (this *service) Type() media.MediaType {
+	return this.service.MediaType()
+}
+
+func (this *service) Keys() []media.MetadataKey {
+	keys := make([]media.MetadataKey, 0, 2)
+	if this.service.Name != "" {
+		keys = append(keys, media.METADATA_KEY_SERVICE_NAME)
+	}
+	if this.service.Provider != "" {
+		keys = append(keys, media.METADATA_KEY_SERVICE_PROVIDER)
+	}
+	return keys
+}
+
+func (this *service) StringForKey(key media.MetadataKey) string {
+	switch key {
+	case media.METADATA_KEY_SERVICE_NAME:
+		return this.service.Name
+	case media.METADATA_KEY_SERVICE_PROVIDER:
+		return this.service.Provider
+	default:
+		return ""
+	}
+}
+
+func (this *service) Streams() []media.MediaStream {
+	return this.streams
+}
+
+func (this *service) String() string {
+	return fmt.Sprintf("<dvb.Service>{ id=%v name=%q provider=%q type=%v streams=%v }", this.id, this.service.Name, this.service.Provider, this.Type(), this.streams)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIASTREAM INTERFACE IMPLEMENTATION
+
+func (this *stream) Type() media.MediaType {
+	return this.stream.MediaType()
+}
+
+func (this *stream) String() string {
+	return fmt.Sprintf("<dvb.Stream>{ pid=0x%04X type=%v }", this.stream.Pid, this.Type())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// isOverflow returns true if the DVR buffer overflowed, which loses
+// packets but does not stop the recording
+func isOverflow(err error) bool {
+	if err, ok := err.(*os.PathError); ok {
+		return err.Err == syscall.EOVERFLOW
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+//go:build linux
+// +build linux
+
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package dvb
+
+import (
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	gopi.RegisterModule(gopi.Module{
+		Name: "dvb",
+		Type: gopi.MODULE_TYPE_OTHER,
+		Config: func(config *gopi.AppConfig) {
+			config.AppFlags.FlagUint("dvb.adapter", 0, "DVB adapter number")
+			config.AppFlags.FlagUint("dvb.frontend", 0, "DVB frontend number")
+			config.AppFlags.FlagDuration("dvb.timeout", 5*time.Second, "Timeout for tuning and reading tables")
+		},
+		New: func(app *gopi.AppInstance) (gopi.Driver, error) {
+			adapter, _ := app.AppFlags.GetUint("dvb.adapter")
+			frontend, _ := app.AppFlags.GetUint("dvb.frontend")
+			timeout, _ := app.AppFlags.GetDuration("dvb.timeout")
+			return gopi.Open(Config{
+				Adapter:  adapter,
+				Frontend: frontend,
+				Timeout:  timeout,
+			}, app.Logger)
+		},
+	})
+}
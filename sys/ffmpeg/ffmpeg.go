@@ -10,17 +10,26 @@
 package ffmpeg
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	// Frameworks
 	gopi "github.com/djthorpe/gopi"
 	media "github.com/djthorpe/gopi-media"
+	book "github.com/djthorpe/gopi-media/book"
+	comic "github.com/djthorpe/gopi-media/comic"
+	exif "github.com/djthorpe/gopi-media/exif"
 	ff "github.com/djthorpe/gopi-media/ffmpeg"
+	nfo "github.com/djthorpe/gopi-media/nfo"
+	source "github.com/djthorpe/gopi-media/source"
+	util "github.com/djthorpe/gopi-media/util"
 	errors "github.com/djthorpe/gopi/util/errors"
 )
 
@@ -28,23 +37,47 @@ import (
 // TYPES
 
 type Config struct {
+	// Read metadata from Kodi-style NFO sidecar files
+	NFO bool
 }
 
 type ffmpeg struct {
 	log   gopi.Logger
+	nfo   bool
+	lock  sync.Mutex
 	files []*ffinput
 }
 
 type ffinput struct {
 	log  gopi.Logger
+	id   string
 	ctx  *ff.AVFormatContext
+	pb   *ff.AVIOContext
+	fh   io.Closer
 	keys map[media.MetadataKey]string
 }
 
+// openFunc opens a file for reading, returning gopi.ErrNotFound if
+// the file does not exist
+type openFunc func(string) (io.ReadCloser, error)
+
+// contextReader reads a file from a source, returning the context
+// error once the context is done so a probe can be abandoned
+type contextReader struct {
+	ctx context.Context
+	media.SourceFile
+}
+
 type ffstream struct {
 	ctx *ff.AVStream
 }
 
+type ffartwork struct {
+	t        media.ArtworkType
+	mimetype string
+	data     []byte
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // OPEN AND CLOSE
 
@@ -56,6 +89,7 @@ func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
 
 	this := new(ffmpeg)
 	this.log = logger
+	this.nfo = config.NFO
 	this.files = make([]*ffinput, 0)
 
 	// Success
@@ -65,6 +99,9 @@ func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
 func (this *ffmpeg) Close() error {
 	this.log.Debug("<ffmpeg.Close>{ }")
 
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
 	var err errors.CompoundError
 	for _, file := range this.files {
 		if file != nil {
@@ -87,44 +124,153 @@ func (this *ffmpeg) Close() error {
 // STRINGIFY
 
 func (this *ffmpeg) String() string {
-	return fmt.Sprintf("<ffmpeg>{ }")
+	return fmt.Sprintf("<ffmpeg>{ nfo=%v }", this.nfo)
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // MEDIA INTERFACE IMPLEMENTATION
 
 func (this *ffmpeg) Open(filename string) (media.MediaFile, error) {
+	return this.OpenContext(context.Background(), filename)
+}
+
+// OpenContext opens a media file, abandoning the probe when the
+// context is cancelled or its deadline passes
+func (this *ffmpeg) OpenContext(ctx context.Context, filename string) (media.MediaFile, error) {
 	this.log.Debug2("<ffmpeg.Open>{ filename=%v }", strconv.Quote(filename))
 
-	if stat, err := os.Stat(filename); os.IsNotExist(err) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	} else if stat, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else if stat.Mode().IsRegular() == false {
+		return nil, gopi.ErrBadParameter
+	} else if t := this.TypeFor(filename); t == media.MEDIA_TYPE_COMIC {
+		// Comic book archives and books are read without ffmpeg
+		if file, err := comic.Open(filename); err != nil {
+			return nil, err
+		} else {
+			return file, nil
+		}
+	} else if t == media.MEDIA_TYPE_EBOOK || t == media.MEDIA_TYPE_BOOKLET {
+		if file, err := book.Open(filename); err != nil {
+			return nil, err
+		} else {
+			return file, nil
+		}
+	} else if file, err := NewInput(ctx, filename, this.log); err != nil {
+		return nil, err
+	} else {
+		return this.add(file, openFile), nil
+	}
+}
+
+// OpenSource opens a media file from a source. Files in local sources
+// are opened by filename, and other files are read through the source.
+func (this *ffmpeg) OpenSource(ctx context.Context, src media.Source, name string) (media.MediaFile, error) {
+	if local, ok := src.(source.Local); ok {
+		return this.OpenContext(ctx, local.Path(name))
+	}
+	this.log.Debug2("<ffmpeg.OpenSource>{ src=%v name=%v }", src, strconv.Quote(name))
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	} else if stat, err := src.Stat(name); os.IsNotExist(err) {
 		return nil, gopi.ErrNotFound
 	} else if err != nil {
 		return nil, err
 	} else if stat.Mode().IsRegular() == false {
 		return nil, gopi.ErrBadParameter
-	} else if file, err := NewInput(filename, this.log); err != nil {
+	} else if t := this.TypeFor(name); t == media.MEDIA_TYPE_COMIC || t == media.MEDIA_TYPE_EBOOK || t == media.MEDIA_TYPE_BOOKLET {
+		// Comic book archives and books are read without ffmpeg
+		fh, err := src.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer fh.Close()
+		if t == media.MEDIA_TYPE_COMIC {
+			if file, err := comic.OpenReader(fh, stat.Size(), name); err != nil {
+				return nil, err
+			} else {
+				return file, nil
+			}
+		} else if file, err := book.OpenReader(fh, stat.Size(), name); err != nil {
+			return nil, err
+		} else {
+			return file, nil
+		}
+	} else if file, err := NewSourceInput(ctx, src, name, stat, this.log); err != nil {
 		return nil, err
 	} else {
-		this.files = append(this.files, file)
-		return file, nil
+		return this.add(file, openSource(src)), nil
 	}
 }
 
 func (this *ffmpeg) Destroy(file media.MediaFile) error {
 	this.log.Debug2("<ffmpeg.Destroy>{ file=%v }", file)
-	// TODO
-	return gopi.ErrNotImplemented
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	for i, input := range this.files {
+		if media.MediaFile(input) == file {
+			this.files = append(this.files[:i], this.files[i+1:]...)
+			return input.Destroy()
+		}
+	}
+
+	// Comic book archives and books hold no resources
+	switch file.(type) {
+	case *comic.File, *book.File:
+		return nil
+	default:
+		return gopi.ErrNotFound
+	}
+}
+
+// add merges metadata from EXIF data and sidecar files, and adds the
+// file to the open files
+func (this *ffmpeg) add(file *ffinput, open openFunc) *ffinput {
+	filename := file.Filename()
+	if this.TypeFor(filename) == media.MEDIA_TYPE_IMAGE {
+		file.readExif(open, filename)
+	}
+	if this.nfo {
+		file.readSidecars(open, filename)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.files = append(this.files, file)
+	return file
 }
 
 func (this *ffmpeg) TypeFor(filename string) media.MediaType {
+	return typeFor(filename)
+}
+
+// typeFor returns the media type for a filename extension
+func typeFor(filename string) media.MediaType {
 	ext := strings.ToLower(filepath.Ext(filename))
 	switch ext {
-	case ".mp4", ".m4v", ".mov", ".m2v", ".vob":
+	case ".mp4", ".m4v", ".mov", ".m2v", ".vob", ".ts", ".m2ts":
 		return media.MEDIA_TYPE_MOVIE
-	case ".mp3", ".aac", ".m4a":
+	case ".mp3", ".aac", ".m4a", ".wav":
 		return media.MEDIA_TYPE_MUSIC
 	case ".m4r":
 		return media.MEDIA_TYPE_RINGTONE
+	case ".m4b":
+		return media.MEDIA_TYPE_AUDIOBOOK
+	case ".jpg", ".jpeg", ".tif", ".tiff":
+		return media.MEDIA_TYPE_IMAGE
+	case ".cbz", ".cbr":
+		return media.MEDIA_TYPE_COMIC
+	case ".epub":
+		return media.MEDIA_TYPE_EBOOK
+	case ".pdf":
+		return media.MEDIA_TYPE_BOOKLET
 	default:
 		return media.MEDIA_TYPE_NONE
 	}
@@ -133,43 +279,200 @@ func (this *ffmpeg) TypeFor(filename string) media.MediaType {
 ////////////////////////////////////////////////////////////////////////////////
 // MEDIAFILE INTERFACE IMPLEMENTATION
 
-func NewInput(filename string, log gopi.Logger) (*ffinput, error) {
+// NewInput opens and probes a file, abandoning the probe with the
+// context error when the context is cancelled
+func NewInput(ctx context.Context, filename string, log gopi.Logger) (*ffinput, error) {
 	if stat, err := os.Stat(filename); os.IsNotExist(err) {
 		return nil, gopi.ErrNotFound
 	} else if err != nil {
 		return nil, err
-	} else if ctx := ff.NewAVFormatContext(); ctx == nil {
+	} else if id, err := util.ItemId(filename); err != nil {
+		return nil, err
+	} else if input := ff.NewAVFormatContext(); input == nil {
 		return nil, gopi.ErrAppError
-	} else if err := ctx.OpenInput(filename, nil); err != nil {
-		ctx.Free()
+	} else if err := openInput(ctx, input, filename); err != nil {
 		return nil, err
 	} else {
-		dict := ctx.Metadata()
-		this := new(ffinput)
-		this.log = log
-		this.ctx = ctx
-		this.keys = make(map[media.MetadataKey]string, dict.Count()+10)
-
-		// Set the file attributes
-		this.keys[media.METADATA_KEY_FILENAME] = filename
-		this.keys[media.METADATA_KEY_FILESIZE] = fmt.Sprint(stat.Size())
-		this.keys[media.METADATA_KEY_EXTENSION] = filepath.Ext(filename)
-		this.keys[media.METADATA_KEY_MODIFIED] = stat.ModTime().Format(time.RFC3339)
-
-		// Read the metadata
-		for _, entry := range dict.Entries() {
-			entry_key := entry.Key()
-			if strings.HasPrefix(entry_key, "iTun") || entry_key == "Encoding Params" {
-				// We ignore any iTunes-specific metadata
-				this.log.Debug2("Ignoring metadata entry: %v", entry)
-			} else if key := MetadataKeyFor(entry_key); key != media.METADATA_KEY_NONE {
-				this.keys[key] = entry.Value()
-			} else {
+		return newInput(input, filename, id, stat, log), nil
+	}
+}
+
+// NewSourceInput opens and probes a file from a source, which is read
+// through an AVIOContext until the input is destroyed
+func NewSourceInput(ctx context.Context, src media.Source, name string, stat os.FileInfo, log gopi.Logger) (*ffinput, error) {
+	fh, err := src.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r := &contextReader{ctx, fh}
+	if id, err := util.ItemIdForReader(fh, stat.Size(), name); err != nil {
+		fh.Close()
+		return nil, err
+	} else if _, err := fh.Seek(0, io.SeekStart); err != nil {
+		fh.Close()
+		return nil, err
+	} else if pb := ff.NewAVIOContext(r); pb == nil {
+		fh.Close()
+		return nil, gopi.ErrAppError
+	} else if input := ff.NewAVFormatContext(); input == nil {
+		pb.Free()
+		fh.Close()
+		return nil, gopi.ErrAppError
+	} else if err := openSourceInput(ctx, input, pb, name); err != nil {
+		pb.Free()
+		fh.Close()
+		return nil, err
+	} else {
+		// Reads after the probe are no longer abandoned
+		r.ctx = context.Background()
+		this := newInput(input, name, id, stat, log)
+		this.pb = pb
+		this.fh = fh
+		return this, nil
+	}
+}
+
+func newInput(input *ff.AVFormatContext, filename, id string, stat os.FileInfo, log gopi.Logger) *ffinput {
+	dict := input.Metadata()
+	this := new(ffinput)
+	this.log = log
+	this.id = id
+	this.ctx = input
+	this.keys = make(map[media.MetadataKey]string, dict.Count()+10)
+
+	// Set the file attributes
+	this.keys[media.METADATA_KEY_FILENAME] = filename
+	this.keys[media.METADATA_KEY_FILESIZE] = fmt.Sprint(stat.Size())
+	this.keys[media.METADATA_KEY_EXTENSION] = filepath.Ext(filename)
+	this.keys[media.METADATA_KEY_MODIFIED] = stat.ModTime().Format(time.RFC3339)
+
+	// Read the metadata
+	for _, entry := range dict.Entries() {
+		entry_key := entry.Key()
+		if entry_key == "iTunSMPB" {
+			// Encoder delay and padding for gapless playback
+			if gapless, err := util.ParseSMPB(entry.Value()); err != nil {
 				this.log.Warn("Ignoring metadata entry: %v", entry)
+			} else {
+				for k, v := range gapless.Keys() {
+					this.keys[k] = v
+				}
 			}
+		} else if key, value, err := util.ReplayGainTag(entry_key, entry.Value()); err != nil {
+			this.log.Warn("Ignoring metadata entry: %v", entry)
+		} else if key != media.METADATA_KEY_NONE {
+			// Track and album gain, converted from R128 for Opus
+			this.keys[key] = value
+		} else if strings.HasPrefix(entry_key, "iTun") || entry_key == "Encoding Params" {
+			// We ignore any iTunes-specific metadata
+			this.log.Debug2("Ignoring metadata entry: %v", entry)
+		} else if key := MetadataKeyFor(entry_key); key != media.METADATA_KEY_NONE {
+			this.keys[key] = entry.Value()
+		} else {
+			this.log.Warn("Ignoring metadata entry: %v", entry)
 		}
+	}
 
-		return this, nil
+	// Flag video with a HDR stream
+	for _, stream := range this.Streams() {
+		if util.IsHDR(stream) {
+			this.keys[media.METADATA_KEY_HDR] = "1"
+		}
+	}
+
+	return this
+}
+
+// openInput opens the input with an interrupt for the context. The
+// input is freed when it cannot be opened
+func openInput(ctx context.Context, input *ff.AVFormatContext, filename string) error {
+	if ctx.Done() == nil {
+		return input.OpenInput(filename, nil)
+	} else if err := input.OpenInputWithInterrupt(filename, nil, func() bool {
+		return ctx.Err() != nil
+	}); err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else {
+		return err
+	}
+}
+
+// openSourceInput opens the input from an AVIOContext. The input is
+// freed when it cannot be opened
+func openSourceInput(ctx context.Context, input *ff.AVFormatContext, pb *ff.AVIOContext, name string) error {
+	input.SetIO(pb)
+	return openInput(ctx, input, name)
+}
+
+// openFile opens a local file
+func openFile(filename string) (io.ReadCloser, error) {
+	if fh, err := os.Open(filename); os.IsNotExist(err) {
+		return nil, gopi.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	} else {
+		return fh, nil
+	}
+}
+
+// openSource returns a function which opens files from a source
+func openSource(src media.Source) openFunc {
+	return func(name string) (io.ReadCloser, error) {
+		if fh, err := src.Open(name); os.IsNotExist(err) {
+			return nil, gopi.ErrNotFound
+		} else if err != nil {
+			return nil, err
+		} else {
+			return fh, nil
+		}
+	}
+}
+
+// readExif merges camera and location metadata from image files
+func (this *ffinput) readExif(open openFunc, filename string) {
+	fh, err := open(filename)
+	if err == gopi.ErrNotFound {
+		return
+	} else if err != nil {
+		this.log.Warn("%v: %v", filename, err)
+		return
+	}
+	defer fh.Close()
+	if data, err := exif.Read(fh); err == gopi.ErrNotFound {
+		return
+	} else if err != nil {
+		this.log.Warn("%v: %v", filename, err)
+	} else {
+		this.log.Debug2("Merging metadata from %v", data)
+		for k, v := range data.Keys() {
+			this.keys[k] = v
+		}
+	}
+}
+
+// readSidecars merges metadata from NFO files, which takes precedence
+// over the embedded metadata
+func (this *ffinput) readSidecars(open openFunc, filename string) {
+	for _, path := range nfo.SidecarsFor(filename) {
+		if doc, err := readSidecar(open, path); err == gopi.ErrNotFound {
+			continue
+		} else if err != nil {
+			this.log.Warn("%v: %v", path, err)
+		} else {
+			this.log.Debug2("Merging metadata from %v", path)
+			for k, v := range doc.Keys() {
+				this.keys[k] = v
+			}
+		}
+	}
+}
+
+func readSidecar(open openFunc, path string) (*nfo.Document, error) {
+	if fh, err := open(path); err != nil {
+		return nil, err
+	} else {
+		defer fh.Close()
+		return nfo.Read(fh)
 	}
 }
 
@@ -184,6 +487,15 @@ func (this *ffinput) Destroy() error {
 		this.ctx.CloseInput()
 		this.ctx = nil
 		this.keys = nil
+		if this.pb != nil {
+			this.pb.Free()
+			this.pb = nil
+		}
+		if this.fh != nil {
+			err := this.fh.Close()
+			this.fh = nil
+			return err
+		}
 		return nil
 	}
 }
@@ -198,7 +510,7 @@ func (this *ffinput) String() string {
 			metadata_value := strconv.Quote(v)
 			metadata += fmt.Sprintf("%v=%v ", metadata_key, metadata_value)
 		}
-		return fmt.Sprintf("<ffinput>{ filename=%v metadata={%v} streams=%v }", strconv.Quote(this.Filename()), strings.TrimSpace(metadata), this.Streams())
+		return fmt.Sprintf("<ffinput>{ id=%v filename=%v metadata={%v} streams=%v }", this.id, strconv.Quote(this.Filename()), strings.TrimSpace(metadata), this.Streams())
 	}
 }
 
@@ -221,30 +533,67 @@ func (this *ffinput) Streams() []media.MediaStream {
 	return streams
 }
 
+func (this *ffinput) Artwork() []media.MediaArtwork {
+	if this.ctx == nil {
+		return nil
+	}
+	artwork := make([]media.MediaArtwork, 0)
+	for _, stream := range this.ctx.Streams() {
+		if data := stream.AttachedPicture(); data != nil {
+			artwork = append(artwork, NewArtwork(stream, data))
+		}
+	}
+	return artwork
+}
+
+func (this *contextReader) Read(buf []byte) (int, error) {
+	if err := this.ctx.Err(); err != nil {
+		return 0, err
+	} else {
+		return this.SourceFile.Read(buf)
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // MEDIAITEM INTERFACE IMPLEMENTATION
 
+func (this *ffinput) Id() string {
+	return this.id
+}
+
 func (this *ffinput) Keys() []media.MetadataKey {
 	if this.ctx == nil {
 		return nil
-	} else {
-		return nil
 	}
+	keys := make([]media.MetadataKey, 0, len(this.keys))
+	for k := range this.keys {
+		keys = append(keys, k)
+	}
+	return keys
 }
 
-func (this *ffinput) StringForKey(media.MetadataKey) string {
-	this.log.Warn("TODO: StringForKey")
-	return ""
+func (this *ffinput) StringForKey(key media.MetadataKey) string {
+	if value, exists := this.keys[key]; exists {
+		return value
+	} else {
+		return ""
+	}
 }
 
 func (this *ffinput) Title() string {
-	// TODO
-	return "TODO"
+	if title, exists := this.keys[media.METADATA_KEY_TITLE]; exists && title != "" {
+		return title
+	} else {
+		filename := filepath.Base(this.Filename())
+		return strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
 }
 
 func (this *ffinput) Type() media.MediaType {
-	// TODO
-	return media.MEDIA_TYPE_NONE
+	if this.ctx == nil {
+		return media.MEDIA_TYPE_NONE
+	}
+	return util.ClassifyType(this, typeFor(this.Filename()))
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -258,14 +607,152 @@ func NewStream(ctx *ff.AVStream) media.MediaStream {
 }
 
 func (this *ffstream) Type() media.MediaType {
-	// TODO
-	return media.MEDIA_TYPE_NONE
+	switch this.ctx.CodecType() {
+	case ff.AVMEDIA_TYPE_VIDEO:
+		if this.ctx.AttachedPicture() != nil {
+			return media.MEDIA_TYPE_IMAGE
+		}
+		return media.MEDIA_TYPE_VIDEO
+	case ff.AVMEDIA_TYPE_AUDIO:
+		return media.MEDIA_TYPE_AUDIO
+	case ff.AVMEDIA_TYPE_SUBTITLE:
+		return media.MEDIA_TYPE_SUBTITLE
+	case ff.AVMEDIA_TYPE_DATA:
+		return media.MEDIA_TYPE_DATA
+	case ff.AVMEDIA_TYPE_ATTACHMENT:
+		return media.MEDIA_TYPE_ATTACHMENT
+	default:
+		return media.MEDIA_TYPE_NONE
+	}
+}
+
+func (this *ffstream) Width() uint {
+	return uint(this.ctx.Width())
+}
+
+func (this *ffstream) Height() uint {
+	return uint(this.ctx.Height())
+}
+
+func (this *ffstream) FieldOrder() media.FieldOrder {
+	if this.ctx.CodecType() != ff.AVMEDIA_TYPE_VIDEO {
+		return media.FIELD_ORDER_UNKNOWN
+	}
+	switch this.ctx.FieldOrder() {
+	case ff.AV_FIELD_PROGRESSIVE:
+		return media.FIELD_ORDER_PROGRESSIVE
+	case ff.AV_FIELD_TT, ff.AV_FIELD_BT:
+		return media.FIELD_ORDER_TOP_FIRST
+	case ff.AV_FIELD_BB, ff.AV_FIELD_TB:
+		return media.FIELD_ORDER_BOTTOM_FIRST
+	default:
+		return media.FIELD_ORDER_UNKNOWN
+	}
+}
+
+func (this *ffstream) ColorPrimaries() media.ColorPrimaries {
+	if this.ctx.CodecType() != ff.AVMEDIA_TYPE_VIDEO {
+		return media.COLOR_PRIMARIES_UNKNOWN
+	}
+	switch this.ctx.ColorPrimaries() {
+	case ff.AVCOL_PRI_BT470BG, ff.AVCOL_PRI_SMPTE170M:
+		return media.COLOR_PRIMARIES_BT601
+	case ff.AVCOL_PRI_BT709:
+		return media.COLOR_PRIMARIES_BT709
+	case ff.AVCOL_PRI_BT2020:
+		return media.COLOR_PRIMARIES_BT2020
+	case ff.AVCOL_PRI_SMPTE431, ff.AVCOL_PRI_SMPTE432:
+		return media.COLOR_PRIMARIES_P3
+	default:
+		return media.COLOR_PRIMARIES_UNKNOWN
+	}
+}
+
+func (this *ffstream) ColorTransfer() media.ColorTransfer {
+	if this.ctx.CodecType() != ff.AVMEDIA_TYPE_VIDEO {
+		return media.COLOR_TRANSFER_UNKNOWN
+	}
+	switch this.ctx.ColorTransfer() {
+	case ff.AVCOL_TRC_BT709, ff.AVCOL_TRC_GAMMA22, ff.AVCOL_TRC_GAMMA28, ff.AVCOL_TRC_SMPTE170M, ff.AVCOL_TRC_IEC61966_2_1, ff.AVCOL_TRC_BT2020_10, ff.AVCOL_TRC_BT2020_12:
+		return media.COLOR_TRANSFER_SDR
+	case ff.AVCOL_TRC_SMPTE2084:
+		return media.COLOR_TRANSFER_PQ
+	case ff.AVCOL_TRC_ARIB_STD_B67:
+		return media.COLOR_TRANSFER_HLG
+	default:
+		return media.COLOR_TRANSFER_UNKNOWN
+	}
+}
+
+func (this *ffstream) BitDepth() uint {
+	return uint(this.ctx.BitDepth())
+}
+
+func (this *ffstream) String() string {
+	if this.Type() == media.MEDIA_TYPE_VIDEO {
+		return fmt.Sprintf("<ffstream>{ type=%v index=%v size=%vx%v field_order=%v primaries=%v transfer=%v bit_depth=%v }", this.Type(), this.ctx.Index(), this.Width(), this.Height(), this.FieldOrder(), this.ColorPrimaries(), this.ColorTransfer(), this.BitDepth())
+	}
+	return fmt.Sprintf("<ffstream>{ type=%v index=%v }", this.Type(), this.ctx.Index())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIAARTWORK INTERFACE IMPLEMENTATION
+
+func NewArtwork(stream *ff.AVStream, data []byte) media.MediaArtwork {
+	this := new(ffartwork)
+	this.data = data
+
+	// The picture type is stored in the comment by the ID3v2 and FLAC
+	// demuxers. Other containers don't store a type, so it's assumed
+	// the picture is the front cover
+	this.t = media.ARTWORK_TYPE_FRONT_COVER
+	if entry := stream.Metadata().Get("comment", nil, ff.AV_DICT_NONE); entry != nil {
+		this.t = ArtworkTypeFor(entry.Value())
+	}
+
+	// Set the mimetype from the codec
+	switch stream.CodecId() {
+	case ff.AV_CODEC_ID_MJPEG:
+		this.mimetype = "image/jpeg"
+	case ff.AV_CODEC_ID_PNG:
+		this.mimetype = "image/png"
+	case ff.AV_CODEC_ID_BMP:
+		this.mimetype = "image/bmp"
+	case ff.AV_CODEC_ID_GIF:
+		this.mimetype = "image/gif"
+	case ff.AV_CODEC_ID_WEBP:
+		this.mimetype = "image/webp"
+	default:
+		this.mimetype = "application/octet-stream"
+	}
+
+	return this
+}
+
+func (this *ffartwork) Type() media.ArtworkType {
+	return this.t
+}
+
+func (this *ffartwork) MimeType() string {
+	return this.mimetype
+}
+
+func (this *ffartwork) Data() []byte {
+	return this.data
+}
+
+func (this *ffartwork) String() string {
+	return fmt.Sprintf("<ffartwork>{ type=%v mimetype=%v size=%v }", this.t, this.mimetype, len(this.data))
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // CONVERT FFMPEG KEYS
 
 func MetadataKeyFor(key string) media.MetadataKey {
+	// ID3v2 lyrics are suffixed with the language, such as "lyrics-eng"
+	if strings.HasPrefix(key, "lyrics-") {
+		return media.METADATA_KEY_LYRICS
+	}
 	switch key {
 	case "major_brand":
 		return media.METADATA_KEY_BRAND_MAJOR
@@ -345,7 +832,74 @@ func MetadataKeyFor(key string) media.MetadataKey {
 		return media.METADATA_KEY_SYNOPSIS
 	case "grouping":
 		return media.METADATA_KEY_GROUPING
+	case "MusicBrainz Album Id", "MUSICBRAINZ_ALBUMID":
+		return media.METADATA_KEY_MUSICBRAINZ_ALBUM_ID
+	case "rating", "ITUNESADVISORY":
+		return media.METADATA_KEY_ADVISORY
+	case "MusicBrainz Album Type", "MUSICBRAINZ_ALBUMTYPE", "RELEASETYPE":
+		return media.METADATA_KEY_RELEASE_TYPE
+	case "MusicBrainz Album Status", "MUSICBRAINZ_ALBUMSTATUS", "RELEASESTATUS":
+		return media.METADATA_KEY_RELEASE_STATUS
+	case "lyrics", "LYRICS", "UNSYNCEDLYRICS":
+		return media.METADATA_KEY_LYRICS
+	case "narrator", "NARRATOR", "NARRATEDBY":
+		return media.METADATA_KEY_NARRATOR
+	case "series", "SERIES":
+		return media.METADATA_KEY_SERIES
+	case "series-part", "SERIES-PART", "SERIESPART", "SERIES_INDEX":
+		return media.METADATA_KEY_SERIES_INDEX
 	default:
 		return media.METADATA_KEY_NONE
 	}
 }
+
+// ArtworkTypeFor returns the picture type from the name used by the
+// ID3v2 and FLAC demuxers
+func ArtworkTypeFor(name string) media.ArtworkType {
+	switch name {
+	case "Other":
+		return media.ARTWORK_TYPE_OTHER
+	case "32x32 pixels 'file icon'":
+		return media.ARTWORK_TYPE_FILE_ICON
+	case "Other file icon":
+		return media.ARTWORK_TYPE_OTHER_ICON
+	case "Cover (front)":
+		return media.ARTWORK_TYPE_FRONT_COVER
+	case "Cover (back)":
+		return media.ARTWORK_TYPE_BACK_COVER
+	case "Leaflet page":
+		return media.ARTWORK_TYPE_LEAFLET
+	case "Media (e.g. label side of CD)":
+		return media.ARTWORK_TYPE_MEDIA
+	case "Lead artist/lead performer/soloist":
+		return media.ARTWORK_TYPE_LEAD_ARTIST
+	case "Artist/performer":
+		return media.ARTWORK_TYPE_ARTIST
+	case "Conductor":
+		return media.ARTWORK_TYPE_CONDUCTOR
+	case "Band/Orchestra":
+		return media.ARTWORK_TYPE_BAND
+	case "Composer":
+		return media.ARTWORK_TYPE_COMPOSER
+	case "Lyricist/text writer":
+		return media.ARTWORK_TYPE_LYRICIST
+	case "Recording Location":
+		return media.ARTWORK_TYPE_RECORDING_LOCATION
+	case "During recording":
+		return media.ARTWORK_TYPE_DURING_RECORDING
+	case "During performance":
+		return media.ARTWORK_TYPE_DURING_PERFORMANCE
+	case "Movie/video screen capture":
+		return media.ARTWORK_TYPE_SCREEN_CAPTURE
+	case "A bright coloured fish":
+		return media.ARTWORK_TYPE_FISH
+	case "Illustration":
+		return media.ARTWORK_TYPE_ILLUSTRATION
+	case "Band/artist logotype":
+		return media.ARTWORK_TYPE_BAND_LOGO
+	case "Publisher/Studio logotype":
+		return media.ARTWORK_TYPE_PUBLISHER_LOGO
+	default:
+		return media.ARTWORK_TYPE_OTHER
+	}
+}
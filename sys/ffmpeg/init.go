@@ -21,8 +21,14 @@ func init() {
 	gopi.RegisterModule(gopi.Module{
 		Name: "ffmpeg",
 		Type: gopi.MODULE_TYPE_OTHER,
+		Config: func(config *gopi.AppConfig) {
+			config.AppFlags.FlagBool("ffmpeg.nfo", true, "Read metadata from NFO sidecar files")
+		},
 		New: func(app *gopi.AppInstance) (gopi.Driver, error) {
-			return gopi.Open(Config{}, app.Logger)
+			nfo, _ := app.AppFlags.GetBool("ffmpeg.nfo")
+			return gopi.Open(Config{
+				NFO: nfo,
+			}, app.Logger)
 		},
 	})
 }
@@ -0,0 +1,45 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package network
+
+import (
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	gopi.RegisterModule(gopi.Module{
+		Name: "network",
+		Type: gopi.MODULE_TYPE_OTHER,
+		Config: func(config *gopi.AppConfig) {
+			config.AppFlags.FlagBool("network.offline", false, "Turn off scrapers, artwork fetch and podcast refresh")
+			config.AppFlags.FlagString("network.windows", "", "Times of day for background downloads, for example 01:00-07:00")
+			config.AppFlags.FlagUint("network.rate", 0, "Maximum rate for background downloads in KB/s")
+		},
+		New: func(app *gopi.AppInstance) (gopi.Driver, error) {
+			offline, _ := app.AppFlags.GetBool("network.offline")
+			value, _ := app.AppFlags.GetString("network.windows")
+			rate, _ := app.AppFlags.GetUint("network.rate")
+			if windows, err := util.ParseWindows(value); err != nil {
+				return nil, err
+			} else {
+				return gopi.Open(Config{
+					Offline: offline,
+					Windows: windows,
+					Rate:    rate * 1024,
+				}, app.Logger)
+			}
+		},
+	})
+}
@@ -0,0 +1,82 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package network
+
+import (
+	"fmt"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Config sets the switch and schedule for background network tasks,
+// which use util.BackgroundClient when they are not passed a client
+type Config struct {
+	// Offline turns off background network tasks
+	Offline bool
+
+	// Windows are the times of day when background requests can be
+	// made, or any time when empty
+	Windows []util.Window
+
+	// Rate limits background downloads in bytes per second, or there
+	// is no limit when zero
+	Rate uint
+}
+
+type network struct {
+	log       gopi.Logger
+	network   *util.Network
+	transport *util.Transport
+	windows   []util.Window
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
+	logger.Debug("<network.Open>{ config=%+v }", config)
+
+	this := new(network)
+	this.log = logger
+	this.network = util.DefaultNetwork
+	this.transport = util.BackgroundTransport
+	this.windows = config.Windows
+
+	this.network.SetOffline(config.Offline)
+	this.transport.SetWindows(config.Windows)
+	this.transport.Limiter.SetRate(config.Rate)
+
+	// Success
+	return this, nil
+}
+
+func (this *network) Close() error {
+	this.log.Debug("<network.Close>{ }")
+
+	// Remove the schedule and turn the network back on
+	this.transport.SetWindows(nil)
+	this.transport.Limiter.SetRate(0)
+	this.network.SetOffline(false)
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *network) String() string {
+	return fmt.Sprintf("<network>{ offline=%v windows=%v limiter=%v }", this.network.Offline(), this.windows, this.transport.Limiter)
+}
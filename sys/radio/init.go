@@ -0,0 +1,39 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package radio
+
+import (
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// INIT
+
+func init() {
+	gopi.RegisterModule(gopi.Module{
+		Name: "radio",
+		Type: gopi.MODULE_TYPE_OTHER,
+		Config: func(config *gopi.AppConfig) {
+			config.AppFlags.FlagString("radio.stations", "", "Path to station list")
+			config.AppFlags.FlagDuration("radio.timeout", 10*time.Second, "Timeout for connecting to a station")
+		},
+		New: func(app *gopi.AppInstance) (gopi.Driver, error) {
+			path, _ := app.AppFlags.GetString("radio.stations")
+			timeout, _ := app.AppFlags.GetDuration("radio.timeout")
+			return gopi.Open(Config{
+				Path:    path,
+				Timeout: timeout,
+			}, app.Logger)
+		},
+	})
+}
@@ -0,0 +1,270 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package radio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	rd "github.com/djthorpe/gopi-media/radio"
+	event "github.com/djthorpe/gopi/util/event"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+type Config struct {
+	// Path to the station list, which is created when a station is
+	// added or removed. When empty, the list is not saved.
+	Path string
+
+	// Timeout for connecting to a station
+	Timeout time.Duration
+}
+
+type radio struct {
+	event.Publisher
+	lock sync.Mutex
+
+	log      gopi.Logger
+	path     string
+	client   *http.Client
+	stations []rd.Station
+}
+
+type nowplaying struct {
+	source  gopi.Driver
+	station media.RadioStation
+	title   string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	DEFAULT_TIMEOUT = 10 * time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+func (config Config) Open(logger gopi.Logger) (gopi.Driver, error) {
+	logger.Debug("<radio.Open>{ config=%+v }", config)
+
+	this := new(radio)
+	this.log = logger
+	this.path = config.Path
+
+	// Set a timeout for connecting and for the response headers, but
+	// not for reading the stream
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+	this.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           (&net.Dialer{Timeout: timeout}).DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+		},
+	}
+
+	// Read the station list
+	if this.path != "" {
+		if stations, err := rd.ReadStationsFile(this.path); err == gopi.ErrNotFound {
+			this.stations = make([]rd.Station, 0)
+		} else if err != nil {
+			return nil, err
+		} else {
+			this.stations = stations
+		}
+	}
+
+	// Success
+	return this, nil
+}
+
+func (this *radio) Close() error {
+	this.log.Debug("<radio.Close>{ }")
+
+	// Close subscriber channels
+	this.Publisher.Close()
+
+	// Release resources
+	this.stations = nil
+
+	// Return success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *radio) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return fmt.Sprintf("<radio>{ path=%v stations=%v }", strconv.Quote(this.path), len(this.stations))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STATIONS
+
+func (this *radio) Stations() []media.RadioStation {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	stations := make([]media.RadioStation, len(this.stations))
+	for i, station := range this.stations {
+		stations[i] = rd.NewStation(station)
+	}
+	return stations
+}
+
+func (this *radio) AddStation(item media.RadioStation) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if item == nil || item.URL() == "" {
+		return gopi.ErrBadParameter
+	}
+	station := rd.StationFor(item)
+	for i, other := range this.stations {
+		if other.Id == station.Id {
+			this.stations[i] = station
+			return this.save()
+		}
+	}
+	this.stations = append(this.stations, station)
+	return this.save()
+}
+
+func (this *radio) RemoveStation(item media.RadioStation) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if item == nil {
+		return gopi.ErrBadParameter
+	}
+	for i, other := range this.stations {
+		if other.Id == item.Id() {
+			this.stations = append(this.stations[:i], this.stations[i+1:]...)
+			return this.save()
+		}
+	}
+	return gopi.ErrNotFound
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STREAM
+
+// Stream writes the audio for a station with the metadata removed,
+// emitting a media.RadioEvent whenever the title changes
+func (this *radio) Stream(station media.RadioStation, w io.Writer, stop <-chan struct{}) error {
+	if station == nil || station.URL() == "" {
+		return gopi.ErrBadParameter
+	}
+
+	// Cancel the request when stop is closed
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequest("GET", station.URL(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(rd.ICY_REQUEST_HEADER, "1")
+	resp, err := this.client.Do(req.WithContext(ctx))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%v: %v", station.URL(), resp.Status)
+	}
+
+	interval := rd.ParseInterval(resp.Header.Get(rd.ICY_INTERVAL_HEADER))
+	this.log.Debug("<radio.Stream>{ station=%v content_type=%v interval=%v }", station, resp.Header.Get("Content-Type"), interval)
+	reader := rd.NewMetadataReader(resp.Body, interval, func(title string) {
+		this.Emit(&nowplaying{this, station, title})
+	})
+	if _, err := io.Copy(w, reader); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	// Success
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RADIOEVENT INTERFACE IMPLEMENTATION
+
+func (this *nowplaying) Source() gopi.Driver {
+	return this.source
+}
+
+func (this *nowplaying) Name() string {
+	return "RadioEvent"
+}
+
+func (this *nowplaying) Station() media.RadioStation {
+	return this.station
+}
+
+func (this *nowplaying) NowPlaying() string {
+	return this.title
+}
+
+func (this *nowplaying) String() string {
+	return fmt.Sprintf("<radio.Event>{ station=%v now_playing=%v }", this.station.Id(), strconv.Quote(this.title))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// save writes the station list, replacing the file once the list
+// has been written
+func (this *radio) save() error {
+	if this.path == "" {
+		return nil
+	}
+	temp := this.path + ".tmp"
+	if fh, err := os.Create(temp); err != nil {
+		return err
+	} else if err := rd.WriteStations(fh, this.stations); err != nil {
+		fh.Close()
+		os.Remove(temp)
+		return err
+	} else if err := fh.Close(); err != nil {
+		os.Remove(temp)
+		return err
+	}
+	return os.Rename(temp, this.path)
+}
@@ -0,0 +1,302 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package timeshift
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Buffer is a circular on-disk buffer for a live stream, which keeps
+// the most recent data written to it so that readers can pause and
+// rewind. Offsets are counted from the start of the stream, so they
+// remain valid as the buffer wraps around.
+type Buffer struct {
+	lock   sync.Mutex
+	cond   *sync.Cond
+	fh     *os.File
+	path   string
+	size   int64
+	head   int64
+	marks  []mark
+	closed bool
+}
+
+// Reader reads from a buffer, waiting for data to be written when it
+// reaches the live position
+type Reader struct {
+	buffer *Buffer
+	offset int64
+}
+
+// mark records the time at which data at an offset was written
+type mark struct {
+	offset int64
+	at     time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Minimum interval between time marks
+	MARK_INTERVAL = time.Second
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN AND CLOSE
+
+// New creates a buffer of a fixed size in bytes, backed by a file
+// which is removed when the buffer is closed
+func New(path string, size int64) (*Buffer, error) {
+	if size <= 0 || path == "" {
+		return nil, gopi.ErrBadParameter
+	}
+	fh, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	this := &Buffer{
+		fh:    fh,
+		path:  path,
+		size:  size,
+		marks: make([]mark, 0),
+	}
+	this.cond = sync.NewCond(&this.lock)
+	return this, nil
+}
+
+// Close releases the buffer and removes the file. Any readers
+// waiting for data return io.EOF.
+func (this *Buffer) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.closed {
+		return nil
+	}
+	this.closed = true
+	this.cond.Broadcast()
+	if err := this.fh.Close(); err != nil {
+		return err
+	}
+	return os.Remove(this.path)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// WRITE
+
+// Write appends data at the live position, overwriting the oldest
+// data once the buffer is full
+func (this *Buffer) Write(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.closed {
+		return 0, os.ErrClosed
+	}
+
+	// Mark the time for this offset
+	now := time.Now()
+	if len(this.marks) == 0 || now.Sub(this.marks[len(this.marks)-1].at) >= MARK_INTERVAL {
+		this.marks = append(this.marks, mark{this.head, now})
+	}
+
+	// Only the tail of a write larger than the buffer is kept
+	written := len(data)
+	if int64(len(data)) > this.size {
+		this.head += int64(len(data)) - this.size
+		data = data[int64(len(data))-this.size:]
+	}
+	for len(data) > 0 {
+		pos := this.head % this.size
+		n := int64(len(data))
+		if pos+n > this.size {
+			n = this.size - pos
+		}
+		if _, err := this.fh.WriteAt(data[:n], pos); err != nil {
+			return written - len(data), err
+		}
+		this.head += n
+		data = data[n:]
+	}
+
+	// Discard marks for overwritten data, keeping the mark
+	// which covers the start of the buffer
+	start, _ := this.rangeLocked()
+	for len(this.marks) > 1 && this.marks[1].offset <= start {
+		this.marks = this.marks[1:]
+	}
+
+	// Wake readers
+	this.cond.Broadcast()
+
+	// Success
+	return written, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// RANGE
+
+// Range returns the offsets of the oldest and live positions
+func (this *Buffer) Range() (int64, int64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.rangeLocked()
+}
+
+// TimeRange returns the times at which the oldest data in the buffer and
+// the most recent time mark were written, or zero times if the buffer
+// is empty
+func (this *Buffer) TimeRange() (time.Time, time.Time) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if len(this.marks) == 0 {
+		return time.Time{}, time.Time{}
+	}
+	return this.marks[0].at, this.marks[len(this.marks)-1].at
+}
+
+// OffsetAt returns the offset of the data written at a time, which is
+// the oldest position for earlier times and the live position for
+// later times
+func (this *Buffer) OffsetAt(at time.Time) int64 {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	start, end := this.rangeLocked()
+	offset := start
+	for _, mark := range this.marks {
+		if mark.at.After(at) {
+			break
+		}
+		offset = mark.offset
+	}
+	if len(this.marks) > 0 && at.After(this.marks[len(this.marks)-1].at.Add(MARK_INTERVAL)) {
+		offset = end
+	}
+	if offset < start {
+		offset = start
+	}
+	return offset
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// READ
+
+// NewReader returns a reader starting at an offset, which is moved into
+// the buffered range
+func (this *Buffer) NewReader(offset int64) *Reader {
+	reader := &Reader{buffer: this}
+	reader.Seek(offset, io.SeekStart)
+	return reader
+}
+
+// Read reads data from the current offset, waiting at the live position
+// until data is written or the buffer is closed. When a reader falls
+// behind the oldest data, it continues from the oldest position.
+func (this *Reader) Read(data []byte) (int, error) {
+	b := this.buffer
+	for {
+		b.lock.Lock()
+		for this.offset >= b.head && b.closed == false {
+			b.cond.Wait()
+		}
+		if b.closed {
+			b.lock.Unlock()
+			return 0, io.EOF
+		}
+		if start, _ := b.rangeLocked(); this.offset < start {
+			this.offset = start
+		}
+		offset, pos := this.offset, this.offset%b.size
+		n := int64(len(data))
+		if offset+n > b.head {
+			n = b.head - offset
+		}
+		if pos+n > b.size {
+			n = b.size - pos
+		}
+		b.lock.Unlock()
+
+		if _, err := b.fh.ReadAt(data[:n], pos); err != nil {
+			return 0, err
+		}
+
+		// Read again if the data was overwritten during the read
+		b.lock.Lock()
+		start, _ := b.rangeLocked()
+		if offset >= start {
+			this.offset = offset + n
+		}
+		b.lock.Unlock()
+		if offset >= start {
+			return int(n), nil
+		}
+	}
+}
+
+// Seek sets the offset for the next read, which is moved into the
+// buffered range. The offset is relative to the start of the stream
+// for io.SeekStart and to the live position for io.SeekEnd.
+func (this *Reader) Seek(offset int64, whence int) (int64, error) {
+	start, end := this.buffer.Range()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += this.offset
+	case io.SeekEnd:
+		offset += end
+	default:
+		return 0, gopi.ErrBadParameter
+	}
+	if offset < start {
+		offset = start
+	} else if offset > end {
+		offset = end
+	}
+	this.offset = offset
+	return offset, nil
+}
+
+// Offset returns the offset of the next read
+func (this *Reader) Offset() int64 {
+	return this.offset
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Buffer) String() string {
+	start, end := this.Range()
+	return fmt.Sprintf("<timeshift.Buffer>{ path=%v size=%v range={ %v,%v } }", strconv.Quote(this.path), this.size, start, end)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Buffer) rangeLocked() (int64, int64) {
+	if this.head > this.size {
+		return this.head - this.size, this.head
+	} else {
+		return 0, this.head
+	}
+}
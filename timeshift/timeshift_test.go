@@ -0,0 +1,130 @@
+package timeshift_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	// Frameworks
+	timeshift "github.com/djthorpe/gopi-media/timeshift"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST BUFFER
+
+func Test_timeshift_000(t *testing.T) {
+	t.Log("Test_timeshift_000")
+}
+
+func Test_timeshift_001(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timeshift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	buffer, err := timeshift.New(filepath.Join(dir, "buffer"), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer buffer.Close()
+
+	// Write 250 bytes into a 100 byte buffer
+	data := make([]byte, 250)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for i := 0; i < len(data); i += 30 {
+		end := i + 30
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := buffer.Write(data[i:end]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if start, end := buffer.Range(); start != 150 || end != 250 {
+		t.Error("Unexpected range", start, end)
+	}
+
+	// Readers before the start of the buffer begin at the oldest data
+	reader := buffer.NewReader(0)
+	if reader.Offset() != 150 {
+		t.Error("Unexpected offset", reader.Offset())
+	}
+	result := make([]byte, 0, 100)
+	buf := make([]byte, 40)
+	for reader.Offset() < 250 {
+		if n, err := reader.Read(buf); err != nil {
+			t.Fatal(err)
+		} else {
+			result = append(result, buf[:n]...)
+		}
+	}
+	if bytes.Equal(result, data[150:]) == false {
+		t.Error("Unexpected data", result)
+	}
+
+	// Seek relative to the live position
+	if offset, err := reader.Seek(-10, io.SeekEnd); err != nil {
+		t.Error(err)
+	} else if offset != 240 {
+		t.Error("Unexpected offset", offset)
+	} else if n, err := reader.Read(buf); err != nil {
+		t.Error(err)
+	} else if bytes.Equal(buf[:n], data[240:]) == false {
+		t.Error("Unexpected data", buf[:n])
+	}
+}
+
+func Test_timeshift_002(t *testing.T) {
+	dir, err := ioutil.TempDir("", "timeshift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	buffer, err := timeshift.New(filepath.Join(dir, "buffer"), 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A reader at the live position waits for data
+	reader := buffer.NewReader(0)
+	done := make(chan []byte)
+	go func() {
+		buf := make([]byte, 10)
+		n, _ := reader.Read(buf)
+		done <- buf[:n]
+	}()
+	time.Sleep(10 * time.Millisecond)
+	buffer.Write([]byte("live"))
+	if data := <-done; string(data) != "live" {
+		t.Error("Unexpected data", string(data))
+	}
+	if offset := buffer.OffsetAt(time.Now().Add(-time.Hour)); offset != 0 {
+		t.Error("Unexpected offset", offset)
+	}
+	if from, to := buffer.TimeRange(); from.IsZero() || to.Before(from) {
+		t.Error("Unexpected time range", from, to)
+	}
+
+	// Closing the buffer releases waiting readers
+	go func() {
+		buf := make([]byte, 10)
+		_, err := reader.Read(buf)
+		if err != io.EOF {
+			t.Error("Expected EOF, got", err)
+		}
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := buffer.Close(); err != nil {
+		t.Error(err)
+	}
+	<-done
+}
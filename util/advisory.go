@@ -0,0 +1,60 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"strconv"
+	"strings"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONTENT ADVISORY
+
+// AdvisoryFor returns the content advisory of an item from
+// METADATA_KEY_ADVISORY, which holds the iTunes rating value or the
+// words "explicit" or "clean". Older files use 4 for explicit content.
+func AdvisoryFor(item media.MediaItem) media.ContentAdvisory {
+	value := strings.ToLower(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_ADVISORY)))
+	switch value {
+	case "explicit":
+		return media.CONTENT_ADVISORY_EXPLICIT
+	case "clean":
+		return media.CONTENT_ADVISORY_CLEAN
+	}
+	if n, err := strconv.ParseUint(value, 10, 32); err != nil {
+		return media.CONTENT_ADVISORY_NONE
+	} else if n == 4 {
+		return media.CONTENT_ADVISORY_EXPLICIT
+	} else if media.ContentAdvisory(n) > media.CONTENT_ADVISORY_MAX {
+		return media.CONTENT_ADVISORY_NONE
+	} else {
+		return media.ContentAdvisory(n)
+	}
+}
+
+// IsExplicit returns true if an item is marked as explicit
+func IsExplicit(item media.MediaItem) bool {
+	return AdvisoryFor(item) == media.CONTENT_ADVISORY_EXPLICIT
+}
+
+// FilterExplicit returns the items which are not marked as explicit,
+// so that explicit tracks can be skipped
+func FilterExplicit(items []media.MediaItem) []media.MediaItem {
+	result := make([]media.MediaItem, 0, len(items))
+	for _, item := range items {
+		if IsExplicit(item) == false {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CONTENT ADVISORY
+
+func Test_advisory_000(t *testing.T) {
+	t.Log("Test_advisory_000")
+}
+
+func Test_advisory_001(t *testing.T) {
+	tests := map[string]media.ContentAdvisory{
+		"":         media.CONTENT_ADVISORY_NONE,
+		"0":        media.CONTENT_ADVISORY_NONE,
+		"1":        media.CONTENT_ADVISORY_EXPLICIT,
+		"2":        media.CONTENT_ADVISORY_CLEAN,
+		"4":        media.CONTENT_ADVISORY_EXPLICIT,
+		"7":        media.CONTENT_ADVISORY_NONE,
+		"Explicit": media.CONTENT_ADVISORY_EXPLICIT,
+		" clean ":  media.CONTENT_ADVISORY_CLEAN,
+		"other":    media.CONTENT_ADVISORY_NONE,
+	}
+	for value, expected := range tests {
+		item := &mock.File{Path: "track.m4a", Metadata: map[media.MetadataKey]string{media.METADATA_KEY_ADVISORY: value}}
+		if advisory := util.AdvisoryFor(item); advisory != expected {
+			t.Error("Unexpected advisory for", value, advisory)
+		}
+	}
+}
+
+func Test_advisory_002(t *testing.T) {
+	items := []media.MediaItem{
+		&mock.File{Path: "a.m4a", Metadata: map[media.MetadataKey]string{media.METADATA_KEY_ADVISORY: "1"}},
+		&mock.File{Path: "b.m4a", Metadata: map[media.MetadataKey]string{media.METADATA_KEY_ADVISORY: "2"}},
+		&mock.File{Path: "c.m4a", Metadata: map[media.MetadataKey]string{}},
+	}
+	if clean := util.FilterExplicit(items); len(clean) != 2 || clean[0] != items[1] || clean[1] != items[2] {
+		t.Error("Unexpected items", clean)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Audiobook is a logical book made from one or more parts, such as the
+// chapters of a book ripped to a folder of MP3 files
+type Audiobook struct {
+	Title       string
+	Author      string
+	Narrator    string
+	Series      string
+	SeriesIndex float64
+
+	// Parts in order of disc and track
+	Parts []media.MediaItem
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Genres which are used for audiobooks, in lowercase
+	audiobookGenres = map[string]bool{
+		"audiobook": true, "audiobooks": true, "audio book": true, "audio books": true,
+		"hörbuch": true, "hörbücher": true, "livre audio": true, "audiolibro": true,
+	}
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// AUDIOBOOKS
+
+// IsAudiobook returns true if an audio item is part of an audiobook,
+// because it has a narrator or an audiobook genre
+func IsAudiobook(item media.MediaItem) bool {
+	if item.StringForKey(media.METADATA_KEY_NARRATOR) != "" {
+		return true
+	}
+	for _, genre := range strings.FieldsFunc(item.StringForKey(media.METADATA_KEY_GENRE), func(r rune) bool { return r == ',' || r == ';' || r == '/' }) {
+		if audiobookGenres[strings.ToLower(strings.TrimSpace(genre))] {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupAudiobooks returns the audiobook items grouped into books by
+// title and author. The title is the album, or the title for books in
+// a single file. Books are sorted by author, series and title.
+func GroupAudiobooks(items []media.MediaItem) []*Audiobook {
+	books := make([]*Audiobook, 0)
+	keys := make(map[string]*Audiobook)
+	for _, item := range items {
+		if item.Type() != media.MEDIA_TYPE_AUDIOBOOK {
+			continue
+		}
+		title := firstOf(item, media.METADATA_KEY_ALBUM, media.METADATA_KEY_TITLE)
+		if title == "" {
+			title = item.Title()
+		}
+		author := firstOf(item, media.METADATA_KEY_AUTHOR, media.METADATA_KEY_ALBUM_ARTIST, media.METADATA_KEY_ARTIST)
+		key := normalizeMatch(author) + "\x00" + normalizeMatch(title)
+		book, exists := keys[key]
+		if exists == false {
+			book = &Audiobook{Title: title, Author: author}
+			keys[key] = book
+			books = append(books, book)
+		}
+		if book.Narrator == "" {
+			book.Narrator = item.StringForKey(media.METADATA_KEY_NARRATOR)
+		}
+		if book.Series == "" {
+			book.Series = item.StringForKey(media.METADATA_KEY_SERIES)
+			book.SeriesIndex, _ = strconv.ParseFloat(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_SERIES_INDEX)), 64)
+		}
+		book.Parts = append(book.Parts, item)
+	}
+	for _, book := range books {
+		parts := book.Parts
+		sort.SliceStable(parts, func(i, j int) bool {
+			if di, dj := numberFor(parts[i], media.METADATA_KEY_DISC), numberFor(parts[j], media.METADATA_KEY_DISC); di != dj {
+				return di < dj
+			} else {
+				return numberFor(parts[i], media.METADATA_KEY_TRACK) < numberFor(parts[j], media.METADATA_KEY_TRACK)
+			}
+		})
+	}
+	sort.SliceStable(books, func(i, j int) bool {
+		a, b := books[i], books[j]
+		switch {
+		case a.Author != b.Author:
+			return a.Author < b.Author
+		case a.Series != b.Series:
+			return a.Series < b.Series
+		case a.SeriesIndex != b.SeriesIndex:
+			return a.SeriesIndex < b.SeriesIndex
+		default:
+			return a.Title < b.Title
+		}
+	})
+	return books
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Audiobook) String() string {
+	parts := fmt.Sprintf(" title=%v", strconv.Quote(this.Title))
+	if this.Author != "" {
+		parts += fmt.Sprintf(" author=%v", strconv.Quote(this.Author))
+	}
+	if this.Narrator != "" {
+		parts += fmt.Sprintf(" narrator=%v", strconv.Quote(this.Narrator))
+	}
+	if this.Series != "" {
+		parts += fmt.Sprintf(" series=%v index=%v", strconv.Quote(this.Series), this.SeriesIndex)
+	}
+	return fmt.Sprintf("<util.Audiobook>{%v parts=%v }", parts, len(this.Parts))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// firstOf returns the first non-empty value for a list of keys
+func firstOf(item media.MediaItem, keys ...media.MetadataKey) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(item.StringForKey(key)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// numberFor returns the number for a track or disc, which may be
+// followed by the total such as "3/12"
+func numberFor(item media.MediaItem, key media.MetadataKey) uint64 {
+	value := strings.TrimSpace(item.StringForKey(key))
+	if i := strings.Index(value, "/"); i >= 0 {
+		value = value[:i]
+	}
+	number, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+	return number
+}
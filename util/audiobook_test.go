@@ -0,0 +1,85 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST AUDIOBOOKS
+
+func Test_audiobook_000(t *testing.T) {
+	t.Log("Test_audiobook_000")
+}
+
+func Test_audiobook_001(t *testing.T) {
+	tests := []struct {
+		keys     map[media.MetadataKey]string
+		expected media.MediaType
+	}{
+		{map[media.MetadataKey]string{}, media.MEDIA_TYPE_MUSIC},
+		{map[media.MetadataKey]string{media.METADATA_KEY_GENRE: "Rock"}, media.MEDIA_TYPE_MUSIC},
+		{map[media.MetadataKey]string{media.METADATA_KEY_GENRE: "Fiction; Audiobook"}, media.MEDIA_TYPE_AUDIOBOOK},
+		{map[media.MetadataKey]string{media.METADATA_KEY_GENRE: "Hörbuch"}, media.MEDIA_TYPE_AUDIOBOOK},
+		{map[media.MetadataKey]string{media.METADATA_KEY_NARRATOR: "Reader"}, media.MEDIA_TYPE_AUDIOBOOK},
+		{map[media.MetadataKey]string{media.METADATA_KEY_NARRATOR: "Reader", media.METADATA_KEY_MEDIA_TYPE: "1"}, media.MEDIA_TYPE_MUSIC},
+	}
+	for _, test := range tests {
+		if mediatype := util.ClassifyType(&mock.File{Path: "01.mp3", Metadata: test.keys}, media.MEDIA_TYPE_MUSIC); mediatype != test.expected {
+			t.Error("Unexpected type for", test.keys, mediatype)
+		}
+	}
+}
+
+func Test_audiobook_002(t *testing.T) {
+	part := func(path, album, artist, disc, track string) *mock.File {
+		return &mock.File{Path: path, MediaType: media.MEDIA_TYPE_AUDIOBOOK, Metadata: map[media.MetadataKey]string{
+			media.METADATA_KEY_ALBUM:  album,
+			media.METADATA_KEY_ARTIST: artist,
+			media.METADATA_KEY_DISC:   disc,
+			media.METADATA_KEY_TRACK:  track,
+		}}
+	}
+	second := part("b/02.mp3", "Second Book", "Author", "1", "2/2")
+	second.Metadata[media.METADATA_KEY_SERIES] = "Series"
+	second.Metadata[media.METADATA_KEY_SERIES_INDEX] = "2"
+	second.Metadata[media.METADATA_KEY_NARRATOR] = "Reader"
+	single := &mock.File{Path: "First Book.m4b", MediaType: media.MEDIA_TYPE_AUDIOBOOK, Metadata: map[media.MetadataKey]string{
+		media.METADATA_KEY_TITLE:        "First Book",
+		media.METADATA_KEY_AUTHOR:       "Author",
+		media.METADATA_KEY_SERIES:       "Series",
+		media.METADATA_KEY_SERIES_INDEX: "1",
+	}}
+	items := []media.MediaItem{
+		part("a/2-01.mp3", "Other Book", "Another Author", "2", "1"),
+		second,
+		part("a/1-02.mp3", "Other Book", "Another Author", "1", "2"),
+		&mock.File{Path: "song.mp3", MediaType: media.MEDIA_TYPE_MUSIC},
+		part("b/01.mp3", "Second Book", "Author", "1", "1/2"),
+		part("a/1-01.mp3", "other book", "Another Author", "1", "1"),
+		single,
+	}
+	books := util.GroupAudiobooks(items)
+	if len(books) != 3 {
+		t.Fatal("Unexpected books", books)
+	}
+	if books[0].Author != "Another Author" || len(books[0].Parts) != 3 {
+		t.Error("Unexpected book", books[0])
+	} else {
+		for i, path := range []string{"a/1-01.mp3", "a/1-02.mp3", "a/2-01.mp3"} {
+			if books[0].Parts[i].(*mock.File).Path != path {
+				t.Error("Unexpected part", i, books[0].Parts[i])
+			}
+		}
+	}
+	if books[1].Title != "First Book" || len(books[1].Parts) != 1 || books[1].SeriesIndex != 1 {
+		t.Error("Unexpected book", books[1])
+	}
+	if books[2].Title != "Second Book" || books[2].Narrator != "Reader" || books[2].SeriesIndex != 2 || len(books[2].Parts) != 2 || books[2].Parts[0].(*mock.File).Path != "b/01.mp3" {
+		t.Error("Unexpected book", books[2])
+	}
+}
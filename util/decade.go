@@ -0,0 +1,83 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Decade is the first year of a decade, for example 1980
+type Decade uint
+
+////////////////////////////////////////////////////////////////////////////////
+// DECADES
+
+// YearFor returns the year of an item from METADATA_KEY_YEAR, which is
+// a year or an ISO date, or false if the item has no year
+func YearFor(item media.MediaItem) (uint, bool) {
+	value := strings.TrimSpace(item.StringForKey(media.METADATA_KEY_YEAR))
+	if len(value) < 4 {
+		return 0, false
+	} else if year, err := strconv.ParseUint(value[0:4], 10, 32); err != nil || year == 0 {
+		return 0, false
+	} else {
+		return uint(year), true
+	}
+}
+
+// DecadeFor returns the decade of an item, or false if the item has
+// no year
+func DecadeFor(item media.MediaItem) (Decade, bool) {
+	if year, ok := YearFor(item); ok == false {
+		return 0, false
+	} else {
+		return Decade(year - year%10), true
+	}
+}
+
+// GroupByDecade returns items grouped by decade and the decades in
+// order, so that browsing can offer shelves such as "80s" and "90s".
+// Items without a year are not included.
+func GroupByDecade(items []media.MediaItem) (map[Decade][]media.MediaItem, []Decade) {
+	groups := make(map[Decade][]media.MediaItem)
+	decades := make([]Decade, 0)
+	for _, item := range items {
+		if decade, ok := DecadeFor(item); ok {
+			if _, exists := groups[decade]; exists == false {
+				decades = append(decades, decade)
+			}
+			groups[decade] = append(groups[decade], item)
+		}
+	}
+	sort.Slice(decades, func(i, j int) bool { return decades[i] < decades[j] })
+	return groups, decades
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+// String returns the name of a decade, such as "80s" for the decades
+// from 1910 to 1990, or the full year such as "2010s" for others
+func (d Decade) String() string {
+	if d >= 1910 && d < 2000 {
+		return fmt.Sprintf("%02ds", d%100)
+	} else {
+		return fmt.Sprintf("%ds", d)
+	}
+}
@@ -0,0 +1,64 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST DECADES
+
+func Test_decade_000(t *testing.T) {
+	t.Log("Test_decade_000")
+}
+
+func dated(title, year string) media.MediaItem {
+	return &mock.File{Path: title, Metadata: map[media.MetadataKey]string{media.METADATA_KEY_YEAR: year}}
+}
+
+func Test_decade_001(t *testing.T) {
+	tests := map[string]uint{
+		"1985":                 1980,
+		"1999-03-31":           1990,
+		"2000-01-01T00:00:00Z": 2000,
+		"2019":                 2010,
+	}
+	for year, expected := range tests {
+		if decade, ok := util.DecadeFor(dated("a", year)); ok == false || uint(decade) != expected {
+			t.Error("Unexpected decade for", year, decade)
+		}
+	}
+	for _, year := range []string{"", "85", "abcd", "0000"} {
+		if _, ok := util.DecadeFor(dated("a", year)); ok {
+			t.Error("Unexpected decade for", year)
+		}
+	}
+	if name := util.Decade(1980).String(); name != "80s" {
+		t.Error("Unexpected name", name)
+	} else if name := util.Decade(1900).String(); name != "1900s" {
+		t.Error("Unexpected name", name)
+	} else if name := util.Decade(2010).String(); name != "2010s" {
+		t.Error("Unexpected name", name)
+	}
+}
+
+func Test_decade_002(t *testing.T) {
+	items := []media.MediaItem{
+		dated("a", "1999"),
+		dated("b", "1985-06-01"),
+		dated("c", ""),
+		dated("d", "1991"),
+	}
+	groups, decades := util.GroupByDecade(items)
+	if len(decades) != 2 || decades[0] != 1980 || decades[1] != 1990 {
+		t.Error("Unexpected decades", decades)
+	} else if len(groups[1990]) != 2 || groups[1990][0] != items[0] || groups[1990][1] != items[3] {
+		t.Error("Unexpected group", groups[1990])
+	} else if len(groups[1980]) != 1 {
+		t.Error("Unexpected group", groups[1980])
+	}
+}
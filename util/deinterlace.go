@@ -0,0 +1,42 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// DEINTERLACE
+
+// IsInterlaced returns true if a stream is video which signals that it
+// is interlaced
+func IsInterlaced(stream media.MediaStream) bool {
+	if video, ok := stream.(media.VideoStream); ok {
+		return video.FieldOrder().Interlaced()
+	}
+	return false
+}
+
+// DeinterlaceFor returns the deinterlace mode for a stream. When the
+// mode is DEINTERLACE_AUTO, interlaced video is deinterlaced by MMAL and
+// other streams are not deinterlaced, including video which doesn't
+// signal a field order.
+func DeinterlaceFor(stream media.MediaStream, mode media.DeinterlaceMode) media.DeinterlaceMode {
+	switch {
+	case mode != media.DEINTERLACE_AUTO && mode <= media.DEINTERLACE_MAX:
+		return mode
+	case IsInterlaced(stream):
+		return media.DEINTERLACE_MMAL
+	default:
+		return media.DEINTERLACE_NONE
+	}
+}
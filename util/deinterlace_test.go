@@ -0,0 +1,58 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST DEINTERLACE
+
+func Test_deinterlace_000(t *testing.T) {
+	t.Log("Test_deinterlace_000")
+}
+
+func Test_deinterlace_001(t *testing.T) {
+	for _, test := range []struct {
+		stream   media.MediaStream
+		mode     media.DeinterlaceMode
+		expected media.DeinterlaceMode
+	}{
+		{&video{720, 576, media.FIELD_ORDER_TOP_FIRST, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_AUTO, media.DEINTERLACE_MMAL},
+		{&video{1920, 1080, media.FIELD_ORDER_BOTTOM_FIRST, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_AUTO, media.DEINTERLACE_MMAL},
+		{&video{1920, 1080, media.FIELD_ORDER_PROGRESSIVE, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_AUTO, media.DEINTERLACE_NONE},
+		{&video{1920, 1080, media.FIELD_ORDER_UNKNOWN, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_AUTO, media.DEINTERLACE_NONE},
+		{&video{720, 576, media.FIELD_ORDER_TOP_FIRST, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_BOB, media.DEINTERLACE_BOB},
+		{&video{720, 576, media.FIELD_ORDER_PROGRESSIVE, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_WEAVE, media.DEINTERLACE_WEAVE},
+		{&video{720, 576, media.FIELD_ORDER_TOP_FIRST, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_NONE, media.DEINTERLACE_NONE},
+		{&audio{}, media.DEINTERLACE_AUTO, media.DEINTERLACE_NONE},
+		{&video{720, 576, media.FIELD_ORDER_TOP_FIRST, media.COLOR_TRANSFER_SDR}, media.DEINTERLACE_MAX + 1, media.DEINTERLACE_MMAL},
+	} {
+		if mode := util.DeinterlaceFor(test.stream, test.mode); mode != test.expected {
+			t.Error("Unexpected mode for", test.stream, test.mode, mode)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+type video struct {
+	width, height uint
+	order         media.FieldOrder
+	transfer      media.ColorTransfer
+}
+
+type audio struct{}
+
+func (this *video) Type() media.MediaType                { return media.MEDIA_TYPE_VIDEO }
+func (this *video) Width() uint                          { return this.width }
+func (this *video) Height() uint                         { return this.height }
+func (this *video) FieldOrder() media.FieldOrder         { return this.order }
+func (this *video) ColorPrimaries() media.ColorPrimaries { return media.COLOR_PRIMARIES_BT2020 }
+func (this *video) ColorTransfer() media.ColorTransfer   { return this.transfer }
+func (this *video) BitDepth() uint                       { return 10 }
+func (this *audio) Type() media.MediaType                { return media.MEDIA_TYPE_AUDIO }
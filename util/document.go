@@ -0,0 +1,145 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Document describes a file which is read without ffmpeg, such as a
+// comic book archive or a book. Title is empty to use the filename, and
+// CoverType is empty when there is no cover image.
+type Document struct {
+	Type      media.MediaType
+	Title     string
+	Keys      map[media.MetadataKey]string
+	CoverType string
+	Cover     []byte
+}
+
+// DocumentFile implements media.MediaFile for a document, with the
+// cover image as artwork. It has no streams, as documents are not
+// decoded.
+type DocumentFile struct {
+	doc      Document
+	filename string
+	id       string
+}
+
+type documentArtwork struct {
+	mimetype string
+	data     []byte
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewDocumentFile returns a media file for a document read from a file
+func NewDocumentFile(filename string, doc Document) (*DocumentFile, error) {
+	if id, err := ItemId(filename); err != nil {
+		return nil, err
+	} else {
+		return &DocumentFile{doc, filename, id}, nil
+	}
+}
+
+// NewDocumentFileForReader returns a media file for a document of a
+// known size, such as a file opened from a source
+func NewDocumentFileForReader(r io.ReaderAt, size int64, filename string, doc Document) (*DocumentFile, error) {
+	if id, err := ItemIdForReader(r, size, filename); err != nil {
+		return nil, err
+	} else {
+		return &DocumentFile{doc, filename, id}, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *DocumentFile) String() string {
+	return fmt.Sprintf("<util.DocumentFile>{ filename=%v type=%v title=%v }", strconv.Quote(this.filename), this.doc.Type, strconv.Quote(this.Title()))
+}
+
+func (this *documentArtwork) String() string {
+	return fmt.Sprintf("<util.DocumentArtwork>{ mimetype=%v size=%v }", this.mimetype, len(this.data))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIAFILE INTERFACE IMPLEMENTATION
+
+func (this *DocumentFile) Id() string {
+	return this.id
+}
+
+func (this *DocumentFile) Title() string {
+	if this.doc.Title != "" {
+		return this.doc.Title
+	} else {
+		filename := filepath.Base(this.filename)
+		return strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+}
+
+func (this *DocumentFile) Type() media.MediaType {
+	return this.doc.Type
+}
+
+func (this *DocumentFile) Keys() []media.MetadataKey {
+	keys := make([]media.MetadataKey, 0, len(this.doc.Keys))
+	for k := range this.doc.Keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func (this *DocumentFile) StringForKey(key media.MetadataKey) string {
+	return this.doc.Keys[key]
+}
+
+func (this *DocumentFile) Filename() string {
+	return this.filename
+}
+
+func (this *DocumentFile) Streams() []media.MediaStream {
+	return nil
+}
+
+func (this *DocumentFile) Artwork() []media.MediaArtwork {
+	if this.doc.CoverType == "" {
+		return nil
+	} else {
+		return []media.MediaArtwork{&documentArtwork{this.doc.CoverType, this.doc.Cover}}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MEDIAARTWORK INTERFACE IMPLEMENTATION
+
+func (this *documentArtwork) Type() media.ArtworkType {
+	return media.ARTWORK_TYPE_FRONT_COVER
+}
+
+func (this *documentArtwork) MimeType() string {
+	return this.mimetype
+}
+
+func (this *documentArtwork) Data() []byte {
+	return this.data
+}
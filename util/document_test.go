@@ -0,0 +1,46 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST DOCUMENT
+
+func Test_document_000(t *testing.T) {
+	t.Log("Test_document_000")
+}
+
+func Test_document_001(t *testing.T) {
+	r := strings.NewReader("document")
+	doc := util.Document{
+		Type:      media.MEDIA_TYPE_EBOOK,
+		Keys:      map[media.MetadataKey]string{media.METADATA_KEY_AUTHOR: "Author"},
+		CoverType: "image/png",
+		Cover:     []byte("cover"),
+	}
+	if file, err := util.NewDocumentFileForReader(r, r.Size(), "remote/Example.epub", doc); err != nil {
+		t.Error(err)
+	} else if file.Id() == "" || file.Title() != "Example" || file.Type() != media.MEDIA_TYPE_EBOOK {
+		t.Error("Unexpected file", file)
+	} else if len(file.Keys()) != 1 || file.StringForKey(media.METADATA_KEY_AUTHOR) != "Author" {
+		t.Error("Unexpected keys", file.Keys())
+	} else if artwork := file.Artwork(); len(artwork) != 1 || artwork[0].MimeType() != "image/png" || string(artwork[0].Data()) != "cover" {
+		t.Error("Unexpected artwork", artwork)
+	} else if file.Streams() != nil {
+		t.Error("Unexpected streams", file.Streams())
+	}
+
+	// A document without a cover has no artwork
+	doc.Title, doc.CoverType, doc.Cover = "Title", "", nil
+	if file, err := util.NewDocumentFileForReader(r, r.Size(), "remote/Example.epub", doc); err != nil {
+		t.Error(err)
+	} else if file.Title() != "Title" || file.Artwork() != nil {
+		t.Error("Unexpected file", file)
+	}
+}
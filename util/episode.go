@@ -0,0 +1,87 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// EPISODE ORDER
+
+// EpisodeOrderFor returns the order chosen for a show with
+// METADATA_KEY_EPISODE_ORDER, or aired order when none is chosen
+func EpisodeOrderFor(show media.MediaItem) media.EpisodeOrder {
+	if order, ok := uintForKey(show, media.METADATA_KEY_EPISODE_ORDER); ok && media.EpisodeOrder(order) <= media.EPISODE_ORDER_MAX {
+		return media.EpisodeOrder(order)
+	} else {
+		return media.EPISODE_ORDER_AIRED
+	}
+}
+
+// EpisodeNumber returns the season and episode number of an episode in
+// an order, falling back to aired order when the episode has no number
+// in the order. Absolute order has no seasons, so the season is zero.
+// Returns false if the episode has no number.
+func EpisodeNumber(episode media.MediaItem, order media.EpisodeOrder) (uint, uint, bool) {
+	switch order {
+	case media.EPISODE_ORDER_DVD:
+		if n, ok := uintForKey(episode, media.METADATA_KEY_DVD_EPISODE); ok {
+			season, _ := uintForKey(episode, media.METADATA_KEY_DVD_SEASON)
+			return season, n, true
+		}
+	case media.EPISODE_ORDER_ABSOLUTE:
+		if n, ok := uintForKey(episode, media.METADATA_KEY_ABSOLUTE_EPISODE); ok {
+			return 0, n, true
+		}
+	}
+	if n, ok := uintForKey(episode, media.METADATA_KEY_EPISODE_SORT); ok {
+		season, _ := uintForKey(episode, media.METADATA_KEY_SEASON)
+		return season, n, true
+	}
+	return 0, 0, false
+}
+
+// SortEpisodes sorts episodes by their number in an order, with
+// episodes which have no number sorted by title after the others
+func SortEpisodes(episodes []media.MediaItem, order media.EpisodeOrder) {
+	sort.SliceStable(episodes, func(i, j int) bool {
+		si, ei, oki := EpisodeNumber(episodes[i], order)
+		sj, ej, okj := EpisodeNumber(episodes[j], order)
+		switch {
+		case oki != okj:
+			return oki
+		case oki == false:
+			return strings.ToLower(episodes[i].Title()) < strings.ToLower(episodes[j].Title())
+		case si != sj:
+			return si < sj
+		default:
+			return ei < ej
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func uintForKey(item media.MediaItem, key media.MetadataKey) (uint, bool) {
+	if value := strings.TrimSpace(item.StringForKey(key)); value == "" {
+		return 0, false
+	} else if n, err := strconv.ParseUint(value, 10, 32); err != nil {
+		return 0, false
+	} else {
+		return uint(n), true
+	}
+}
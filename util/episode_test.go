@@ -0,0 +1,88 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST EPISODES
+
+func Test_episode_000(t *testing.T) {
+	t.Log("Test_episode_000")
+}
+
+func episode(title, season, number, dvdSeason, dvdNumber, absolute string) media.MediaItem {
+	keys := map[media.MetadataKey]string{media.METADATA_KEY_TITLE: title}
+	for key, value := range map[media.MetadataKey]string{
+		media.METADATA_KEY_SEASON:           season,
+		media.METADATA_KEY_EPISODE_SORT:     number,
+		media.METADATA_KEY_DVD_SEASON:       dvdSeason,
+		media.METADATA_KEY_DVD_EPISODE:      dvdNumber,
+		media.METADATA_KEY_ABSOLUTE_EPISODE: absolute,
+	} {
+		if value != "" {
+			keys[key] = value
+		}
+	}
+	return &mock.File{Path: title + ".mkv", MediaType: media.MEDIA_TYPE_TVEPISODE, Metadata: keys}
+}
+
+func titles(items []media.MediaItem) string {
+	result := ""
+	for _, item := range items {
+		result += item.Title()
+	}
+	return result
+}
+
+func Test_episode_001(t *testing.T) {
+	show := &mock.File{Path: "show", MediaType: media.MEDIA_TYPE_TVSHOW, Metadata: map[media.MetadataKey]string{}}
+	if order := util.EpisodeOrderFor(show); order != media.EPISODE_ORDER_AIRED {
+		t.Error("Unexpected order", order)
+	}
+	show.Metadata[media.METADATA_KEY_EPISODE_ORDER] = "2"
+	if order := util.EpisodeOrderFor(show); order != media.EPISODE_ORDER_ABSOLUTE {
+		t.Error("Unexpected order", order)
+	}
+	show.Metadata[media.METADATA_KEY_EPISODE_ORDER] = "99"
+	if order := util.EpisodeOrderFor(show); order != media.EPISODE_ORDER_AIRED {
+		t.Error("Unexpected order", order)
+	}
+}
+
+func Test_episode_002(t *testing.T) {
+	// Episodes which were aired out of DVD order, with absolute numbers
+	// across seasons
+	episodes := []media.MediaItem{
+		episode("D", "2", "1", "1", "4", "4"),
+		episode("A", "1", "2", "1", "1", "1"),
+		episode("C", "1", "3", "1", "3", "3"),
+		episode("B", "1", "1", "1", "2", "2"),
+		episode("F", "", "", "", "", ""),
+		episode("E", "2", "2", "", "", ""),
+	}
+	util.SortEpisodes(episodes, media.EPISODE_ORDER_AIRED)
+	if order := titles(episodes); order != "BACDEF" {
+		t.Error("Unexpected aired order", order)
+	}
+	util.SortEpisodes(episodes, media.EPISODE_ORDER_DVD)
+	if order := titles(episodes); order != "ABCDEF" {
+		t.Error("Unexpected DVD order", order)
+	}
+
+	// Without an absolute number, the aired number is used
+	if season, n, ok := util.EpisodeNumber(episodes[4], media.EPISODE_ORDER_ABSOLUTE); ok == false || season != 2 || n != 2 {
+		t.Error("Unexpected number", season, n, ok)
+	}
+	if season, n, ok := util.EpisodeNumber(episodes[3], media.EPISODE_ORDER_ABSOLUTE); ok == false || season != 0 || n != 4 {
+		t.Error("Unexpected number", season, n, ok)
+	}
+	if _, _, ok := util.EpisodeNumber(episodes[5], media.EPISODE_ORDER_AIRED); ok {
+		t.Error("Expected no number")
+	}
+}
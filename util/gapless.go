@@ -0,0 +1,108 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Gapless is the number of silent samples added by the encoder at the
+// start and end of a track, which are removed for gapless playback.
+// Samples is the length of the original audio, or zero if unknown.
+type Gapless struct {
+	Delay, Padding, Samples uint64
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GAPLESS
+
+// ParseSMPB parses the iTunSMPB value written by iTunes and other AAC
+// encoders, which is a list of hexadecimal numbers such as
+// " 00000000 00000840 000001CA 00000000003F31F6 ..."
+func ParseSMPB(value string) (Gapless, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 4 {
+		return Gapless{}, gopi.ErrBadParameter
+	}
+	numbers := make([]uint64, 3)
+	for i := range numbers {
+		if n, err := strconv.ParseUint(fields[i+1], 16, 64); err != nil {
+			return Gapless{}, gopi.ErrBadParameter
+		} else {
+			numbers[i] = n
+		}
+	}
+	return Gapless{Delay: numbers[0], Padding: numbers[1], Samples: numbers[2]}, nil
+}
+
+// GaplessFor returns the encoder delay and padding for an item, or
+// false if the item is not marked for gapless playback or has no
+// delay or padding
+func GaplessFor(item media.MediaItem) (Gapless, bool) {
+	if flag := strings.TrimSpace(item.StringForKey(media.METADATA_KEY_GAPLESS_PLAYBACK)); flag == "0" || strings.EqualFold(flag, "false") {
+		return Gapless{}, false
+	}
+	g := Gapless{}
+	g.Delay, _ = strconv.ParseUint(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_ENCODER_DELAY)), 10, 64)
+	g.Padding, _ = strconv.ParseUint(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_ENCODER_PADDING)), 10, 64)
+	g.Samples, _ = strconv.ParseUint(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_SAMPLE_COUNT)), 10, 64)
+	if g.Delay == 0 && g.Padding == 0 {
+		return Gapless{}, false
+	}
+	return g, true
+}
+
+// Trim returns the range of decoded samples to play from a track with
+// a number of samples, removing the delay and padding
+func (g Gapless) Trim(samples uint64) (uint64, uint64) {
+	start, end := g.Delay, samples
+	if g.Samples > 0 {
+		// The padding is only used when the length is unknown
+		if start+g.Samples < end {
+			end = start + g.Samples
+		}
+	} else if g.Padding < end {
+		end -= g.Padding
+	} else {
+		end = 0
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// Keys returns the metadata for the delay, padding and length
+func (g Gapless) Keys() map[media.MetadataKey]string {
+	keys := map[media.MetadataKey]string{
+		media.METADATA_KEY_ENCODER_DELAY:   fmt.Sprint(g.Delay),
+		media.METADATA_KEY_ENCODER_PADDING: fmt.Sprint(g.Padding),
+	}
+	if g.Samples > 0 {
+		keys[media.METADATA_KEY_SAMPLE_COUNT] = fmt.Sprint(g.Samples)
+	}
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (g Gapless) String() string {
+	return fmt.Sprintf("<util.Gapless>{ delay=%v padding=%v samples=%v }", g.Delay, g.Padding, g.Samples)
+}
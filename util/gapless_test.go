@@ -0,0 +1,65 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST GAPLESS
+
+func Test_gapless_000(t *testing.T) {
+	t.Log("Test_gapless_000")
+}
+
+func Test_gapless_001(t *testing.T) {
+	g, err := util.ParseSMPB(" 00000000 00000840 000001CA 00000000003F31F6 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000")
+	if err != nil {
+		t.Fatal(err)
+	} else if g.Delay != 2112 || g.Padding != 458 || g.Samples != 4141558 {
+		t.Error("Unexpected value", g)
+	}
+	for _, value := range []string{"", "00000000 00000840", "00000000 0000084G 000001CA 00000000003F31F6"} {
+		if _, err := util.ParseSMPB(value); err != gopi.ErrBadParameter {
+			t.Error("Expected ErrBadParameter for", value, err)
+		}
+	}
+
+	// Trim to the original length, or remove the padding when the
+	// length is unknown
+	if start, end := g.Trim(2112 + 4141558 + 458); start != 2112 || end != 2112+4141558 {
+		t.Error("Unexpected trim", start, end)
+	}
+	if start, end := (util.Gapless{Delay: 100, Padding: 50}).Trim(1000); start != 100 || end != 950 {
+		t.Error("Unexpected trim", start, end)
+	}
+	if start, end := (util.Gapless{Delay: 100, Padding: 50}).Trim(120); start != 70 || end != 70 {
+		t.Error("Unexpected trim", start, end)
+	}
+
+	// The padding is not removed when the length is known, even when
+	// the decoder returns fewer samples than expected
+	if start, end := (util.Gapless{Delay: 100, Padding: 50, Samples: 900}).Trim(980); start != 100 || end != 980 {
+		t.Error("Unexpected trim", start, end)
+	}
+}
+
+func Test_gapless_002(t *testing.T) {
+	keys := util.Gapless{Delay: 2112, Padding: 458, Samples: 4141558}.Keys()
+	file := &mock.File{Path: "track.m4a", Metadata: keys}
+	if g, ok := util.GaplessFor(file); ok == false || g.Delay != 2112 || g.Padding != 458 || g.Samples != 4141558 {
+		t.Error("Unexpected value", g, ok)
+	}
+	keys[media.METADATA_KEY_GAPLESS_PLAYBACK] = "0"
+	if _, ok := util.GaplessFor(file); ok {
+		t.Error("Expected no gapless playback")
+	}
+	if _, ok := util.GaplessFor(&mock.File{Path: "track.mp3"}); ok {
+		t.Error("Expected no gapless playback")
+	}
+}
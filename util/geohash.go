@@ -0,0 +1,157 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"math"
+	"strings"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Default number of characters in a geohash, which is a cell of
+	// around 150 metres
+	GEOHASH_PRECISION = 7
+
+	// Maximum number of characters in a geohash
+	GEOHASH_MAXPRECISION = 12
+
+	// Mean radius of the earth in metres
+	EARTH_RADIUS = 6371000
+)
+
+const (
+	geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+	metresPerDegree = math.Pi * EARTH_RADIUS / 180
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GEOHASH
+
+// Geohash returns the geohash of a location with the given number of
+// characters. Locations which share a geohash prefix are close together,
+// so the geohash can be indexed and queried by prefix.
+func Geohash(lat, lon float64, precision uint) string {
+	if precision == 0 {
+		precision = GEOHASH_PRECISION
+	} else if precision > GEOHASH_MAXPRECISION {
+		precision = GEOHASH_MAXPRECISION
+	}
+	lat_range := [2]float64{-90, 90}
+	lon_range := [2]float64{-180, 180}
+	hash := make([]byte, 0, precision)
+	for bit, ch, even := 0, 0, true; len(hash) < int(precision); even = !even {
+		value, r := lat, &lat_range
+		if even {
+			value, r = lon, &lon_range
+		}
+		ch <<= 1
+		if mid := (r[0] + r[1]) / 2; value >= mid {
+			ch, r[0] = ch|1, mid
+		} else {
+			r[1] = mid
+		}
+		if bit++; bit == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// GeohashBounds returns the south-west and north-east corners of the
+// cell for a geohash, or false if the geohash is not valid
+func GeohashBounds(hash string) (min_lat, min_lon, max_lat, max_lon float64, ok bool) {
+	lat_range := [2]float64{-90, 90}
+	lon_range := [2]float64{-180, 180}
+	even := true
+	for _, ch := range strings.ToLower(hash) {
+		value := strings.IndexRune(geohashAlphabet, ch)
+		if value < 0 {
+			return 0, 0, 0, 0, false
+		}
+		for mask := 16; mask != 0; mask >>= 1 {
+			r := &lat_range
+			if even {
+				r = &lon_range
+			}
+			if mid := (r[0] + r[1]) / 2; value&mask != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	return lat_range[0], lon_range[0], lat_range[1], lon_range[1], hash != ""
+}
+
+// GeohashesWithin returns the geohash prefixes of the cells which cover
+// a circle of radius metres around a location. Items whose geohash starts
+// with one of the prefixes may be within the circle, and should be checked
+// with Distance.
+func GeohashesWithin(lat, lon, radius float64) []string {
+	// Determine the size of the circle in degrees
+	lat_delta := radius / metresPerDegree
+	lon_delta := 360.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 0.01 {
+		lon_delta = lat_delta / cos
+	}
+
+	// Choose the longest geohash whose cells are larger than the circle
+	precision := uint(0)
+	for p := uint(1); p <= GEOHASH_MAXPRECISION; p++ {
+		lon_bits := (p*5 + 1) / 2
+		lat_bits := p * 5 / 2
+		if 360/math.Pow(2, float64(lon_bits)) < lon_delta || 180/math.Pow(2, float64(lat_bits)) < lat_delta {
+			break
+		}
+		precision = p
+	}
+	if precision == 0 {
+		// The circle covers the whole earth
+		return []string{""}
+	}
+
+	// Return the cell containing the location and its neighbours
+	min_lat, min_lon, max_lat, max_lon, _ := GeohashBounds(Geohash(lat, lon, precision))
+	height, width := max_lat-min_lat, max_lon-min_lon
+	hashes := make([]string, 0, 9)
+	seen := make(map[string]bool, 9)
+	for _, dy := range []float64{-1, 0, 1} {
+		y := (min_lat+max_lat)/2 + dy*height
+		if y < -90 || y > 90 {
+			continue
+		}
+		for _, dx := range []float64{-1, 0, 1} {
+			x := (min_lon+max_lon)/2 + dx*width
+			if x < -180 {
+				x += 360
+			} else if x > 180 {
+				x -= 360
+			}
+			if hash := Geohash(y, x, precision); seen[hash] == false {
+				hashes = append(hashes, hash)
+				seen[hash] = true
+			}
+		}
+	}
+	return hashes
+}
+
+// Distance returns the great-circle distance in metres between two locations
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dphi, dlambda := (lat2-lat1)*math.Pi/180, (lon2-lon1)*math.Pi/180
+	a := math.Sin(dphi/2)*math.Sin(dphi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dlambda/2)*math.Sin(dlambda/2)
+	return 2 * EARTH_RADIUS * math.Asin(math.Min(1, math.Sqrt(a)))
+}
@@ -0,0 +1,57 @@
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	// Frameworks
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST GEOHASH
+
+func Test_geohash_000(t *testing.T) {
+	t.Log("Test_geohash_000")
+}
+
+func Test_geohash_001(t *testing.T) {
+	// Known value from the geohash reference implementation
+	if hash := util.Geohash(57.64911, 10.40744, 11); hash != "u4pruydqqvj" {
+		t.Error("Unexpected geohash", hash)
+	}
+	if min_lat, min_lon, max_lat, max_lon, ok := util.GeohashBounds("u4pruydqqvj"); ok == false {
+		t.Error("Expected valid geohash")
+	} else if min_lat > 57.64911 || max_lat < 57.64911 || min_lon > 10.40744 || max_lon < 10.40744 {
+		t.Error("Unexpected bounds", min_lat, min_lon, max_lat, max_lon)
+	}
+	if _, _, _, _, ok := util.GeohashBounds("abc"); ok {
+		t.Error("Expected invalid geohash")
+	}
+}
+
+func Test_geohash_002(t *testing.T) {
+	// London to Paris is around 344km
+	if d := util.Distance(51.5074, -0.1278, 48.8566, 2.3522); d < 340000 || d > 345000 {
+		t.Error("Unexpected distance", d)
+	}
+}
+
+func Test_geohash_003(t *testing.T) {
+	// A location 4km away is covered by the cells within 5km
+	lat, lon := 51.5074, -0.1278
+	near := util.Geohash(51.5074, -0.0700, util.GEOHASH_MAXPRECISION)
+	hashes := util.GeohashesWithin(lat, lon, 5000)
+	if len(hashes) == 0 || len(hashes) > 9 {
+		t.Fatal("Unexpected hashes", hashes)
+	}
+	found := false
+	for _, hash := range hashes {
+		if strings.HasPrefix(near, hash) {
+			found = true
+		}
+	}
+	if found == false {
+		t.Error("Expected", near, "to be covered by", hashes)
+	}
+}
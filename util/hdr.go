@@ -0,0 +1,41 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// HDR
+
+// IsHDR returns true if a stream is video with a high dynamic range
+// transfer function, such as HDR10 or HLG
+func IsHDR(stream media.MediaStream) bool {
+	if video, ok := stream.(media.VideoStream); ok && stream.Type() == media.MEDIA_TYPE_VIDEO {
+		return video.ColorTransfer().HDR()
+	}
+	return false
+}
+
+// IsHDRFile returns true if a file is flagged as HDR when it was
+// probed, or has a HDR video stream
+func IsHDRFile(file media.MediaFile) bool {
+	if flag := file.StringForKey(media.METADATA_KEY_HDR); flag == "1" || flag == "true" {
+		return true
+	}
+	for _, stream := range file.Streams() {
+		if IsHDR(stream) {
+			return true
+		}
+	}
+	return false
+}
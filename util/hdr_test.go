@@ -0,0 +1,42 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST HDR
+
+func Test_hdr_000(t *testing.T) {
+	t.Log("Test_hdr_000")
+}
+
+func Test_hdr_001(t *testing.T) {
+	for _, test := range []struct {
+		stream   media.MediaStream
+		expected bool
+	}{
+		{&video{3840, 2160, media.FIELD_ORDER_PROGRESSIVE, media.COLOR_TRANSFER_PQ}, true},
+		{&video{1920, 1080, media.FIELD_ORDER_TOP_FIRST, media.COLOR_TRANSFER_HLG}, true},
+		{&video{1920, 1080, media.FIELD_ORDER_PROGRESSIVE, media.COLOR_TRANSFER_SDR}, false},
+		{&video{1920, 1080, media.FIELD_ORDER_PROGRESSIVE, media.COLOR_TRANSFER_UNKNOWN}, false},
+		{&audio{}, false},
+	} {
+		if hdr := util.IsHDR(test.stream); hdr != test.expected {
+			t.Error("Unexpected HDR for", test.stream, hdr)
+		}
+	}
+}
+
+func Test_hdr_002(t *testing.T) {
+	if util.IsHDRFile(&mock.File{Path: "film.mkv", Metadata: map[media.MetadataKey]string{media.METADATA_KEY_HDR: "1"}}) == false {
+		t.Error("Expected HDR")
+	} else if util.IsHDRFile(&mock.File{Path: "film.mkv"}) {
+		t.Error("Unexpected HDR")
+	}
+}
@@ -0,0 +1,265 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// IdStore assigns an identifier once to each item, so the identifier
+// stays the same across restarts and when the item is edited, and
+// differs between copies of the same file. The content identifier is
+// only used to recognise an item which has moved. The zero value keeps
+// identifiers in memory and is safe for concurrent use.
+type IdStore struct {
+	lock     sync.Mutex
+	filename string
+	items    map[string]*idItem
+	seen     map[string]bool
+}
+
+type idItem struct {
+	Id      string `json:"id"`
+	Content string `json:"content"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBAL VARIABLES
+
+var (
+	// DefaultIds is the store used by ItemId and ItemIdForReader
+	DefaultIds = new(IdStore)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Number of bytes read from the head and tail of a file
+	ID_SAMPLE_SIZE = 64 * 1024
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONTENT IDENTIFIER
+
+// ContentId returns an identifier for a file which is derived from the
+// file size and the data at the start and end of the file, so it does
+// not change when the file is renamed or moved. Copies of a file have
+// the same content identifier, and it changes when the file is edited,
+// so use ItemId to identify media items.
+func ContentId(filename string) (string, error) {
+	if fh, err := os.Open(filename); err != nil {
+		return "", err
+	} else {
+		defer fh.Close()
+		if stat, err := fh.Stat(); err != nil {
+			return "", err
+		} else if stat.Mode().IsRegular() == false {
+			return "", gopi.ErrBadParameter
+		} else {
+			return ContentIdForReader(fh, stat.Size())
+		}
+	}
+}
+
+// ContentIdForReader returns a content identifier for data of a
+// particular size
+func ContentIdForReader(r io.ReaderAt, size int64) (string, error) {
+	hash := sha1.New()
+	buf := make([]byte, ID_SAMPLE_SIZE)
+
+	// Include the size of the data
+	if err := binary.Write(hash, binary.BigEndian, size); err != nil {
+		return "", err
+	}
+
+	// Head of the data
+	if n, err := r.ReadAt(buf, 0); err != nil && err != io.EOF {
+		return "", err
+	} else {
+		hash.Write(buf[:n])
+	}
+
+	// Tail of the data, which may overlap the head
+	if offset := size - ID_SAMPLE_SIZE; offset > 0 {
+		if n, err := r.ReadAt(buf, offset); err != nil && err != io.EOF {
+			return "", err
+		} else {
+			hash.Write(buf[:n])
+		}
+	}
+
+	// Return the identifier
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ITEM IDENTIFIER
+
+// ItemId returns the identifier assigned to a file by DefaultIds, which
+// is kept when the file is edited, renamed or moved
+func ItemId(filename string) (string, error) {
+	if path, err := filepath.Abs(filename); err != nil {
+		return "", err
+	} else if content, err := ContentId(path); err != nil {
+		return "", err
+	} else {
+		return DefaultIds.Id(path, content)
+	}
+}
+
+// ItemIdForReader returns the identifier assigned by DefaultIds to data
+// of a particular size, such as a file opened from a source
+func ItemIdForReader(r io.ReaderAt, size int64, name string) (string, error) {
+	if content, err := ContentIdForReader(r, size); err != nil {
+		return "", err
+	} else {
+		return DefaultIds.Id(name, content)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ID STORE
+
+// Load reads identifiers from a file, which is written by Save. It is not
+// an error if the file does not exist yet.
+func (this *IdStore) Load(filename string) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	items := make(map[string]*idItem)
+	if data, err := ioutil.ReadFile(filename); os.IsNotExist(err) {
+		// Start with no identifiers
+	} else if err != nil {
+		return err
+	} else if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	// Set the store
+	this.filename = filename
+	this.items = items
+	this.seen = nil
+
+	// Success
+	return nil
+}
+
+// Save writes identifiers to the file which was loaded, replacing it so
+// a partly written file is never read. It does nothing if no file was
+// loaded.
+func (this *IdStore) Save() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.filename == "" {
+		return nil
+	} else if data, err := json.MarshalIndent(this.items, "", "  "); err != nil {
+		return err
+	} else if err := ioutil.WriteFile(this.filename+".tmp", data, 0644); err != nil {
+		return err
+	} else {
+		return os.Rename(this.filename+".tmp", this.filename)
+	}
+}
+
+// Id returns the identifier for an item with a name and content
+// identifier. An item keeps its identifier when its content changes. An
+// unknown name takes the identifier of an item with the same content
+// which has not been seen since loading and no longer exists, so a moved
+// file is recognised. Otherwise a new identifier is assigned.
+func (this *IdStore) Id(name, content string) (string, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.items == nil {
+		this.items = make(map[string]*idItem)
+	}
+	if this.seen == nil {
+		this.seen = make(map[string]bool)
+	}
+
+	// Known item, which may have been edited
+	if item, exists := this.items[name]; exists {
+		item.Content = content
+		this.seen[name] = true
+		return item.Id, nil
+	}
+
+	// Moved item, checking names in order so the result does not
+	// depend on map order
+	names := make([]string, 0, len(this.items))
+	for other := range this.items {
+		names = append(names, other)
+	}
+	sort.Strings(names)
+	for _, other := range names {
+		if item := this.items[other]; item.Content != content || this.seen[other] {
+			continue
+		} else if _, err := os.Lstat(other); os.IsNotExist(err) == false {
+			continue
+		} else {
+			delete(this.items, other)
+			this.items[name] = item
+			this.seen[name] = true
+			return item.Id, nil
+		}
+	}
+
+	// New item
+	if id, err := newId(); err != nil {
+		return "", err
+	} else {
+		this.items[name] = &idItem{Id: id, Content: content}
+		this.seen[name] = true
+		return id, nil
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *IdStore) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return fmt.Sprintf("<util.IdStore>{ filename=%v items=%v }", strconv.Quote(this.filename), len(this.items))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// newId returns a random version 4 UUID
+func newId() (string, error) {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return "", err
+	}
+	uuid[6] = (uuid[6] & 0x0F) | 0x40
+	uuid[8] = (uuid[8] & 0x3F) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
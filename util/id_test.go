@@ -0,0 +1,115 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// Frameworks
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CONTENT IDENTIFIER
+
+func Test_id_000(t *testing.T) {
+	t.Log("Test_id_000")
+}
+
+func Test_id_001(t *testing.T) {
+	if id, err := util.ContentId("../etc/sample.mp4"); err != nil {
+		t.Error(err)
+	} else if len(id) != 40 {
+		t.Error("Unexpected identifier", id)
+	} else if id2, err := util.ContentId("../etc/sample.mp4"); err != nil {
+		t.Error(err)
+	} else if id != id2 {
+		t.Error("Expected identifiers to be equal", id, id2)
+	}
+}
+
+func Test_id_002(t *testing.T) {
+	a := strings.NewReader("hello, world")
+	b := strings.NewReader("hello, world!")
+	if id1, err := util.ContentIdForReader(a, a.Size()); err != nil {
+		t.Error(err)
+	} else if id2, err := util.ContentIdForReader(b, b.Size()); err != nil {
+		t.Error(err)
+	} else if id1 == id2 {
+		t.Error("Expected identifiers to differ")
+	}
+}
+
+func Test_id_003(t *testing.T) {
+	if _, err := util.ContentId("../etc"); err == nil {
+		t.Error("Expected error for directory")
+	}
+}
+
+func Test_id_004(t *testing.T) {
+	// Data which differs after the head but is shorter than two samples
+	a := strings.Repeat("a", util.ID_SAMPLE_SIZE+100)
+	b := a[:util.ID_SAMPLE_SIZE+50] + strings.Repeat("b", 50)
+	if id1, err := util.ContentIdForReader(strings.NewReader(a), int64(len(a))); err != nil {
+		t.Error(err)
+	} else if id2, err := util.ContentIdForReader(strings.NewReader(b), int64(len(b))); err != nil {
+		t.Error(err)
+	} else if id1 == id2 {
+		t.Error("Expected identifiers to differ")
+	}
+}
+
+func Test_id_005(t *testing.T) {
+	store := new(util.IdStore)
+	if id1, err := store.Id("a.mp3", "content"); err != nil {
+		t.Error(err)
+	} else if len(id1) != 36 {
+		t.Error("Unexpected identifier", id1)
+	} else if id2, err := store.Id("b.mp3", "content"); err != nil {
+		t.Error(err)
+	} else if id1 == id2 {
+		t.Error("Expected copies to have different identifiers")
+	} else if id3, err := store.Id("a.mp3", "edited"); err != nil {
+		t.Error(err)
+	} else if id1 != id3 {
+		t.Error("Expected edited item to keep its identifier", id1, id3)
+	}
+}
+
+func Test_id_006(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	filename := filepath.Join(tmp, "ids.json")
+	old, moved := filepath.Join(tmp, "old.mp3"), filepath.Join(tmp, "new.mp3")
+
+	// Assign an identifier and save it
+	store := new(util.IdStore)
+	if err := store.Load(filename); err != nil {
+		t.Fatal(err)
+	}
+	id, err := store.Id(old, "content")
+	if err != nil {
+		t.Fatal(err)
+	} else if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The identifier is kept after a restart when the file is moved
+	store = new(util.IdStore)
+	if err := store.Load(filename); err != nil {
+		t.Error(err)
+	} else if id2, err := store.Id(moved, "content"); err != nil {
+		t.Error(err)
+	} else if id != id2 {
+		t.Error("Expected moved item to keep its identifier", id, id2)
+	} else if id3, err := store.Id(old, "content"); err != nil {
+		t.Error(err)
+	} else if id == id3 {
+		t.Error("Expected a new identifier once the moved item is claimed")
+	}
+}
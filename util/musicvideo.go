@@ -0,0 +1,146 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBALS
+
+var (
+	// Decorations which are removed from titles before matching
+	reDecoration = regexp.MustCompile(`(?i)[\(\[][^\)\]]*(official|video|audio|lyric|hd|4k|remaster)[^\)\]]*[\)\]]`)
+	reFeaturing  = regexp.MustCompile(`(?i)\s+[\(\[]?(feat\.?|ft\.?|featuring)\s+.*$`)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CLASSIFY
+
+// ClassifyType returns the type of an item, using the iTunes media kind
+// in METADATA_KEY_MEDIA_TYPE when it is set, or otherwise the type for
+// the filename. Videos with an artist and no show are music videos, and
+// audio with a narrator or an audiobook genre is an audiobook.
+func ClassifyType(item media.MediaItem, filetype media.MediaType) media.MediaType {
+	if kind, err := strconv.ParseUint(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_MEDIA_TYPE)), 10, 32); err == nil {
+		switch kind {
+		case 1:
+			return media.MEDIA_TYPE_MUSIC
+		case 2:
+			return media.MEDIA_TYPE_AUDIOBOOK
+		case 6:
+			return media.MEDIA_TYPE_MUSICVIDEO
+		case 0, 9:
+			return media.MEDIA_TYPE_MOVIE
+		case 10:
+			return media.MEDIA_TYPE_TVEPISODE
+		case 11:
+			return media.MEDIA_TYPE_BOOKLET
+		case 14:
+			return media.MEDIA_TYPE_RINGTONE
+		}
+	}
+	if filetype == media.MEDIA_TYPE_MUSIC || filetype == media.MEDIA_TYPE_AUDIO {
+		if IsAudiobook(item) {
+			return media.MEDIA_TYPE_AUDIOBOOK
+		}
+	}
+	if filetype == media.MEDIA_TYPE_MOVIE || filetype == media.MEDIA_TYPE_VIDEO {
+		if item.StringForKey(media.METADATA_KEY_SHOW) != "" {
+			return media.MEDIA_TYPE_TVEPISODE
+		} else if item.StringForKey(media.METADATA_KEY_ARTIST) != "" {
+			return media.MEDIA_TYPE_MUSICVIDEO
+		}
+	}
+	return filetype
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// MUSIC VIDEOS
+
+// MatchMusicVideo returns the tracks with the same artist and title as a
+// music video. Titles are compared without case, punctuation, featured
+// artists or decorations such as "(Official Video)". When the video has
+// no artist, the title is split on " - " into the artist and title.
+func MatchMusicVideo(video media.MediaItem, tracks []media.MediaItem) []media.MediaItem {
+	artist, title := artistTitle(video)
+	if artist == "" || title == "" {
+		return nil
+	}
+	matches := make([]media.MediaItem, 0, 1)
+	for _, track := range tracks {
+		if track.Type() == media.MEDIA_TYPE_MUSICVIDEO {
+			continue
+		}
+		if a, t := artistTitle(track); a == artist && t == title {
+			matches = append(matches, track)
+		}
+	}
+	return matches
+}
+
+// MusicVideosForArtist returns the music videos by an artist, which are
+// shown alongside the artist's albums and tracks
+func MusicVideosForArtist(artist string, items []media.MediaItem) []media.MediaItem {
+	artist = normalizeMatch(artist)
+	if artist == "" {
+		return nil
+	}
+	videos := make([]media.MediaItem, 0)
+	for _, item := range items {
+		if item.Type() != media.MEDIA_TYPE_MUSICVIDEO {
+			continue
+		}
+		if a, _ := artistTitle(item); a == artist {
+			videos = append(videos, item)
+		}
+	}
+	return videos
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// artistTitle returns the normalized artist and title of an item
+func artistTitle(item media.MediaItem) (string, string) {
+	artist := item.StringForKey(media.METADATA_KEY_ARTIST)
+	if artist == "" {
+		artist = item.StringForKey(media.METADATA_KEY_ALBUM_ARTIST)
+	}
+	title := item.Title()
+	if artist == "" {
+		if parts := strings.SplitN(title, " - ", 2); len(parts) == 2 {
+			artist, title = parts[0], parts[1]
+		}
+	}
+	return normalizeMatch(artist), normalizeMatch(title)
+}
+
+func normalizeMatch(value string) string {
+	value = reDecoration.ReplaceAllString(value, " ")
+	value = reFeaturing.ReplaceAllString(value, "")
+	value = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		} else if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return ' '
+		} else {
+			return -1
+		}
+	}, value)
+	return strings.Join(strings.Fields(value), " ")
+}
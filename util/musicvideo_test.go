@@ -0,0 +1,68 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST MUSIC VIDEOS
+
+func Test_musicvideo_000(t *testing.T) {
+	t.Log("Test_musicvideo_000")
+}
+
+func Test_musicvideo_001(t *testing.T) {
+	tests := []struct {
+		keys     map[media.MetadataKey]string
+		filetype media.MediaType
+		expected media.MediaType
+	}{
+		{map[media.MetadataKey]string{}, media.MEDIA_TYPE_MOVIE, media.MEDIA_TYPE_MOVIE},
+		{map[media.MetadataKey]string{media.METADATA_KEY_MEDIA_TYPE: "6"}, media.MEDIA_TYPE_MOVIE, media.MEDIA_TYPE_MUSICVIDEO},
+		{map[media.MetadataKey]string{media.METADATA_KEY_MEDIA_TYPE: "10"}, media.MEDIA_TYPE_MOVIE, media.MEDIA_TYPE_TVEPISODE},
+		{map[media.MetadataKey]string{media.METADATA_KEY_ARTIST: "Artist"}, media.MEDIA_TYPE_MOVIE, media.MEDIA_TYPE_MUSICVIDEO},
+		{map[media.MetadataKey]string{media.METADATA_KEY_ARTIST: "Artist"}, media.MEDIA_TYPE_MUSIC, media.MEDIA_TYPE_MUSIC},
+		{map[media.MetadataKey]string{media.METADATA_KEY_ARTIST: "Artist", media.METADATA_KEY_SHOW: "Show"}, media.MEDIA_TYPE_MOVIE, media.MEDIA_TYPE_TVEPISODE},
+		{map[media.MetadataKey]string{media.METADATA_KEY_MEDIA_TYPE: "99"}, media.MEDIA_TYPE_MOVIE, media.MEDIA_TYPE_MOVIE},
+	}
+	for _, test := range tests {
+		if mediatype := util.ClassifyType(&mock.File{Path: "video.mp4", Metadata: test.keys}, test.filetype); mediatype != test.expected {
+			t.Error("Unexpected type for", test.keys, mediatype)
+		}
+	}
+}
+
+func Test_musicvideo_002(t *testing.T) {
+	video := &mock.File{Path: "Artist & Friend - Song (feat. Other) [Official Video].mp4", MediaType: media.MEDIA_TYPE_MUSICVIDEO}
+	tracks := []media.MediaItem{
+		&mock.File{Path: "01 Song.mp3", MediaType: media.MEDIA_TYPE_MUSIC, Metadata: map[media.MetadataKey]string{
+			media.METADATA_KEY_ARTIST: "Artist and Friend",
+			media.METADATA_KEY_TITLE:  "SONG!",
+		}},
+		&mock.File{Path: "02 Other.mp3", MediaType: media.MEDIA_TYPE_MUSIC, Metadata: map[media.MetadataKey]string{
+			media.METADATA_KEY_ARTIST: "Artist & Friend",
+			media.METADATA_KEY_TITLE:  "Other Song",
+		}},
+		&mock.File{Path: "03 Song.mp3", MediaType: media.MEDIA_TYPE_MUSIC, Metadata: map[media.MetadataKey]string{
+			media.METADATA_KEY_ALBUM_ARTIST: "Artist & Friend",
+			media.METADATA_KEY_TITLE:        "Song",
+		}},
+		video,
+	}
+
+	// "and" is not the same as "&", so only the third track matches
+	if matches := util.MatchMusicVideo(video, tracks); len(matches) != 1 || matches[0] != tracks[2] {
+		t.Error("Unexpected matches", matches)
+	}
+	if videos := util.MusicVideosForArtist("artist & friend", tracks); len(videos) != 1 || videos[0] != video {
+		t.Error("Unexpected videos", videos)
+	}
+	if videos := util.MusicVideosForArtist("Other", tracks); len(videos) != 0 {
+		t.Error("Unexpected videos", videos)
+	}
+}
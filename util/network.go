@@ -0,0 +1,181 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Network is a switch for features which use the network, such as
+// scrapers, artwork and scrobbling, which can be turned off at runtime.
+// A NetworkEvent is emitted when the switch changes and whenever an
+// operation is skipped because the network is offline. Events are
+// dropped for subscribers which fall behind, so emitting never blocks
+// a request. The zero value is online and is safe for concurrent use.
+type Network struct {
+	lock     sync.Mutex
+	offline  bool
+	channels []chan gopi.Event
+}
+
+// NetworkEvent is emitted by a Network
+type NetworkEvent struct {
+	source    *Network
+	Offline   bool
+	Operation string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// GLOBAL VARIABLES
+
+var (
+	// ErrOffline is returned when a request is not made because the
+	// network is offline
+	ErrOffline = errors.New("Network is offline")
+
+	// DefaultNetwork is the switch used by BackgroundTransport and by
+	// background tasks such as podcast refresh and artwork fetch
+	DefaultNetwork = new(Network)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Number of events buffered for each subscriber
+	NETWORK_EVENT_BUFFER = 16
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// SWITCH
+
+// SetOffline turns network features off or on
+func (this *Network) SetOffline(offline bool) {
+	this.lock.Lock()
+	changed := this.offline != offline
+	this.offline = offline
+	this.lock.Unlock()
+
+	if changed {
+		this.emit(&NetworkEvent{this, offline, ""})
+	}
+}
+
+// Offline returns true if network features are off
+func (this *Network) Offline() bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.offline
+}
+
+// Skip returns true and emits an event if the network is offline, in
+// which case the operation should not be performed. The operation
+// describes what was skipped, for example "podcast refresh".
+func (this *Network) Skip(operation string) bool {
+	if this.Offline() == false {
+		return false
+	}
+	this.emit(&NetworkEvent{this, true, operation})
+	return true
+}
+
+// Close turns the network features back on and closes subscriber
+// channels
+func (this *Network) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.offline = false
+	for _, channel := range this.channels {
+		close(channel)
+	}
+	this.channels = nil
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLISHER INTERFACE IMPLEMENTATION
+
+// Subscribe returns a channel on which events are emitted
+func (this *Network) Subscribe() <-chan gopi.Event {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	channel := make(chan gopi.Event, NETWORK_EVENT_BUFFER)
+	this.channels = append(this.channels, channel)
+	return channel
+}
+
+// Unsubscribe closes a channel returned by Subscribe
+func (this *Network) Unsubscribe(subscriber <-chan gopi.Event) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for i, channel := range this.channels {
+		if channel == subscriber {
+			close(channel)
+			this.channels = append(this.channels[:i], this.channels[i+1:]...)
+			break
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// EVENT IMPLEMENTATION
+
+func (this *NetworkEvent) Source() gopi.Driver {
+	return this.source
+}
+
+func (this *NetworkEvent) Name() string {
+	return "NetworkEvent"
+}
+
+// Skipped returns true if the event is for an operation which was
+// not performed, rather than a change to the switch
+func (this *NetworkEvent) Skipped() bool {
+	return this.Operation != ""
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *Network) String() string {
+	return fmt.Sprintf("<util.Network>{ offline=%v }", this.Offline())
+}
+
+func (this *NetworkEvent) String() string {
+	if this.Skipped() {
+		return fmt.Sprintf("<util.NetworkEvent>{ skipped=%v }", strconv.Quote(this.Operation))
+	} else {
+		return fmt.Sprintf("<util.NetworkEvent>{ offline=%v }", this.Offline)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// emit sends an event to each subscriber which has room for it
+func (this *Network) emit(evt gopi.Event) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, channel := range this.channels {
+		select {
+		case channel <- evt:
+		default:
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package util_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	// Frameworks
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST NETWORK
+
+func Test_network_000(t *testing.T) {
+	t.Log("Test_network_000")
+}
+
+func Test_network_001(t *testing.T) {
+	network := new(util.Network)
+	defer network.Close()
+
+	if network.Offline() {
+		t.Error("Expected zero value to be online")
+	} else if network.Skip("test") {
+		t.Error("Expected operation not to be skipped")
+	}
+
+	// Events are emitted for the change and for skipped operations
+	events := network.Subscribe()
+	done := make(chan []*util.NetworkEvent)
+	go func() {
+		received := make([]*util.NetworkEvent, 0, 2)
+		for evt := range events {
+			received = append(received, evt.(*util.NetworkEvent))
+			if len(received) == 2 {
+				break
+			}
+		}
+		done <- received
+	}()
+	network.SetOffline(true)
+	network.SetOffline(true)
+	if network.Skip("test") == false {
+		t.Error("Expected operation to be skipped")
+	}
+	select {
+	case received := <-done:
+		if received[0].Offline == false || received[0].Skipped() {
+			t.Error("Unexpected event", received[0])
+		}
+		if received[1].Operation != "test" || received[1].Skipped() == false {
+			t.Error("Unexpected event", received[1])
+		}
+	case <-time.After(time.Second):
+		t.Error("Timeout waiting for events")
+	}
+}
+
+func Test_network_002(t *testing.T) {
+	// Requests fail when offline, but cached responses are returned
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	network := new(util.Network)
+	defer network.Close()
+	client := util.NewClient(&util.Transport{CacheTime: time.Minute, Network: network})
+	if resp, err := client.Get(server.URL + "/cached"); err != nil {
+		t.Fatal(err)
+	} else {
+		resp.Body.Close()
+	}
+
+	network.SetOffline(true)
+	if resp, err := client.Get(server.URL + "/cached"); err != nil {
+		t.Error(err)
+	} else {
+		resp.Body.Close()
+	}
+	if _, err := client.Get(server.URL + "/other"); err == nil {
+		t.Error("Expected error when offline")
+	} else if err, ok := err.(*url.Error); ok == false || err.Err != util.ErrOffline {
+		t.Error("Expected ErrOffline, got", err)
+	}
+
+	network.SetOffline(false)
+	if resp, err := client.Get(server.URL + "/other"); err != nil {
+		t.Error(err)
+	} else {
+		resp.Body.Close()
+	}
+}
+
+func Test_network_003(t *testing.T) {
+	network := new(util.Network)
+	defer network.Close()
+
+	// Subscribers which don't read events don't block the switch
+	network.Subscribe()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < util.NETWORK_EVENT_BUFFER*4; i++ {
+			network.SetOffline(i%2 == 0)
+			network.Skip("test")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout changing the switch")
+	}
+
+	// Subscribers can change the switch when handling an event
+	events := network.Subscribe()
+	go func() {
+		for range events {
+			network.SetOffline(false)
+		}
+	}()
+	network.SetOffline(true)
+	for i := 0; i < 100 && network.Offline(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if network.Offline() {
+		t.Error("Expected subscriber to turn the network back on")
+	}
+}
@@ -0,0 +1,35 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"context"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// OPEN
+
+// OpenContext opens a file with a driver, returning the context error
+// if the context is already cancelled. Drivers which implement
+// media.ContextMedia give up opening the file when the context is
+// cancelled or its deadline passes, and other drivers open the file
+// without interruption.
+func OpenContext(ctx context.Context, driver media.Media, filename string) (media.MediaFile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	} else if driver_, ok := driver.(media.ContextMedia); ok {
+		return driver_.OpenContext(ctx, filename)
+	} else {
+		return driver.Open(filename)
+	}
+}
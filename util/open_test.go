@@ -0,0 +1,75 @@
+package util_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+	logger "github.com/djthorpe/gopi/sys/logger"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST OPEN
+
+func Test_open_000(t *testing.T) {
+	t.Log("Test_open_000")
+}
+
+// interruptible opens files slowly unless the context is cancelled
+type interruptible struct {
+	media.Media
+}
+
+func (this *interruptible) OpenContext(ctx context.Context, filename string) (media.MediaFile, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Second):
+		return this.Media.Open(filename)
+	}
+}
+
+func Test_open_001(t *testing.T) {
+	log, err := gopi.Open(logger.Config{Level: logger.LOG_WARN}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened := make([]string, 0)
+	driver, err := gopi.Open(mock.Config{
+		OpenFunc: func(filename string) (media.MediaFile, error) {
+			opened = append(opened, filename)
+			return &mock.File{Path: filename}, nil
+		},
+	}, log.(gopi.Logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer driver.Close()
+
+	// A driver without context support opens the file
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if file, err := util.OpenContext(ctx, driver.(media.Media), "/fast.mp4"); err != nil {
+		t.Error(err)
+	} else if file.Title() != "fast" {
+		t.Error("Unexpected file", file)
+	}
+
+	// A driver with context support gives up when the deadline passes
+	if _, err := util.OpenContext(ctx, &interruptible{driver.(media.Media)}, "/slow.mp4"); err != context.DeadlineExceeded {
+		t.Error("Expected DeadlineExceeded, got", err)
+	}
+
+	// A cancelled context does not open the file
+	if _, err := util.OpenContext(ctx, driver.(media.Media), "/fast.mp4"); err != context.DeadlineExceeded {
+		t.Error("Expected DeadlineExceeded, got", err)
+	}
+	if len(opened) != 1 || opened[0] != "/fast.mp4" {
+		t.Error("Unexpected files opened", opened)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"strings"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+// Release types and statuses, as used by MusicBrainz
+const (
+	RELEASE_TYPE_ALBUM       = "album"
+	RELEASE_TYPE_SINGLE      = "single"
+	RELEASE_TYPE_EP          = "ep"
+	RELEASE_TYPE_BROADCAST   = "broadcast"
+	RELEASE_TYPE_COMPILATION = "compilation"
+	RELEASE_TYPE_SOUNDTRACK  = "soundtrack"
+	RELEASE_TYPE_LIVE        = "live"
+	RELEASE_TYPE_REMIX       = "remix"
+	RELEASE_TYPE_DEMO        = "demo"
+)
+
+const (
+	RELEASE_STATUS_OFFICIAL  = "official"
+	RELEASE_STATUS_PROMOTION = "promotion"
+	RELEASE_STATUS_BOOTLEG   = "bootleg"
+	RELEASE_STATUS_PSEUDO    = "pseudo-release"
+	RELEASE_STATUS_WITHDRAWN = "withdrawn"
+	RELEASE_STATUS_CANCELLED = "cancelled"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// RELEASE TYPES
+
+// ReleaseTypes returns the release types of an item in lower case. Tags
+// hold several types separated by semicolons, slashes or commas, for
+// example "Album; Live".
+func ReleaseTypes(item media.MediaItem) []string {
+	value := item.StringForKey(media.METADATA_KEY_RELEASE_TYPE)
+	fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return r == ';' || r == '/' || r == ','
+	})
+	types := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			types = append(types, field)
+		}
+	}
+	return types
+}
+
+// ReleaseStatus returns the release status of an item in lower case
+func ReleaseStatus(item media.MediaItem) string {
+	return strings.ToLower(strings.TrimSpace(item.StringForKey(media.METADATA_KEY_RELEASE_STATUS)))
+}
+
+// HasReleaseType returns true if an item has any of the release types
+func HasReleaseType(item media.MediaItem, types ...string) bool {
+	for _, t := range ReleaseTypes(item) {
+		for _, other := range types {
+			if t == strings.ToLower(other) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterReleaseType returns the items with any of the release types,
+// which is used to browse albums separately from singles, live
+// recordings and remixes
+func FilterReleaseType(items []media.MediaItem, types ...string) []media.MediaItem {
+	result := make([]media.MediaItem, 0, len(items))
+	for _, item := range items {
+		if HasReleaseType(item, types...) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST RELEASE TYPES
+
+func Test_release_000(t *testing.T) {
+	t.Log("Test_release_000")
+}
+
+func album(title, types, status string) media.MediaItem {
+	return &mock.File{Path: title, MediaType: media.MEDIA_TYPE_ALBUM, Metadata: map[media.MetadataKey]string{
+		media.METADATA_KEY_RELEASE_TYPE:   types,
+		media.METADATA_KEY_RELEASE_STATUS: status,
+	}}
+}
+
+func Test_release_001(t *testing.T) {
+	albums := []media.MediaItem{
+		album("A", "Album", "Official"),
+		album("B", "album; live", "official"),
+		album("C", "EP/Remix", "Promotion"),
+		album("D", "", ""),
+	}
+	if types := util.ReleaseTypes(albums[2]); len(types) != 2 || types[0] != util.RELEASE_TYPE_EP || types[1] != util.RELEASE_TYPE_REMIX {
+		t.Error("Unexpected types", types)
+	}
+	if status := util.ReleaseStatus(albums[0]); status != util.RELEASE_STATUS_OFFICIAL {
+		t.Error("Unexpected status", status)
+	}
+	if util.HasReleaseType(albums[3], util.RELEASE_TYPE_ALBUM) {
+		t.Error("Unexpected release type")
+	}
+	if live := util.FilterReleaseType(albums, util.RELEASE_TYPE_LIVE, util.RELEASE_TYPE_REMIX); len(live) != 2 || live[0] != albums[1] || live[1] != albums[2] {
+		t.Error("Unexpected filter", live)
+	}
+	if all := util.FilterReleaseType(albums, "ALBUM"); len(all) != 2 {
+		t.Error("Unexpected filter", all)
+	}
+}
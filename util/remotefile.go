@@ -0,0 +1,188 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// RequestFunc returns a new GET request for a remote file, which can
+// add authentication to each request
+type RequestFunc func() (*http.Request, error)
+
+// RemoteFile reads a file from an HTTP server with range requests, so
+// that media can be probed and streamed without downloading the whole
+// file. Sequential reads share a single response, and the response is
+// only replaced after a seek. It implements io.ReadSeeker, io.ReaderAt
+// and io.Closer.
+type RemoteFile struct {
+	client  *http.Client
+	request RequestFunc
+	size    int64
+
+	lock   sync.Mutex
+	offset int64
+	body   io.ReadCloser
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// NEW
+
+// NewRemoteFile returns a file of a known size which is read with
+// requests from a function, using DefaultClient when the client
+// is nil
+func NewRemoteFile(client *http.Client, size int64, request RequestFunc) *RemoteFile {
+	if client == nil {
+		client = DefaultClient
+	}
+	return &RemoteFile{client: client, request: request, size: size}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this *RemoteFile) String() string {
+	return fmt.Sprintf("<util.RemoteFile>{ size=%v offset=%v }", this.size, this.offset)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Size returns the size of the file in bytes
+func (this *RemoteFile) Size() int64 {
+	return this.size
+}
+
+func (this *RemoteFile) Read(data []byte) (int, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.offset >= this.size {
+		return 0, io.EOF
+	}
+	if this.body == nil {
+		if body, err := this.get(this.offset, this.size-1); err != nil {
+			return 0, err
+		} else {
+			this.body = body
+		}
+	}
+	n, err := this.body.Read(data)
+	this.offset += int64(n)
+	if err == io.EOF && this.offset < this.size {
+		// The response ended early, so request the remainder on the
+		// next read
+		this.body.Close()
+		this.body = nil
+		err = nil
+	}
+	return n, err
+}
+
+func (this *RemoteFile) ReadAt(data []byte, offset int64) (int, error) {
+	if offset < 0 {
+		return 0, gopi.ErrBadParameter
+	} else if offset >= this.size {
+		return 0, io.EOF
+	} else if len(data) == 0 {
+		return 0, nil
+	}
+	end := offset + int64(len(data)) - 1
+	if end >= this.size {
+		end = this.size - 1
+	}
+	body, err := this.get(offset, end)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	n, err := io.ReadFull(body, data[:end-offset+1])
+	if err == nil && n < len(data) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (this *RemoteFile) Seek(offset int64, whence int) (int64, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += this.offset
+	case io.SeekEnd:
+		offset += this.size
+	default:
+		return this.offset, gopi.ErrBadParameter
+	}
+	if offset < 0 {
+		return this.offset, gopi.ErrBadParameter
+	}
+	if offset != this.offset && this.body != nil {
+		this.body.Close()
+		this.body = nil
+	}
+	this.offset = offset
+	return offset, nil
+}
+
+func (this *RemoteFile) Close() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	if this.body != nil {
+		err := this.body.Close()
+		this.body = nil
+		return err
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// get returns the body of a response for an inclusive range of bytes
+func (this *RemoteFile) get(start, end int64) (io.ReadCloser, error) {
+	request, err := this.request()
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Range", "bytes="+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10))
+	response, err := this.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case http.StatusPartialContent:
+		return response.Body, nil
+	case http.StatusOK:
+		// The server ignored the range, which is only acceptable when
+		// reading from the start of the file
+		if start == 0 {
+			return response.Body, nil
+		}
+		response.Body.Close()
+		return nil, gopi.ErrUnexpectedResponse
+	default:
+		response.Body.Close()
+		return nil, fmt.Errorf("%v", response.Status)
+	}
+}
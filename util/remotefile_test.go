@@ -0,0 +1,62 @@
+package util_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// Frameworks
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST REMOTE FILES
+
+func Test_remotefile_000(t *testing.T) {
+	t.Log("Test_remotefile_000")
+}
+
+func Test_remotefile_001(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	file := util.NewRemoteFile(nil, int64(len(data)), func() (*http.Request, error) {
+		return http.NewRequest("GET", server.URL, nil)
+	})
+	defer file.Close()
+
+	// Sequential reads share one request
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(file, buf); err != nil || string(buf) != "0123" {
+		t.Error("Unexpected read", string(buf), err)
+	} else if _, err := io.ReadFull(file, buf); err != nil || string(buf) != "4567" {
+		t.Error("Unexpected read", string(buf), err)
+	} else if requests != 1 {
+		t.Error("Unexpected requests", requests)
+	}
+
+	// Seek and read the remainder
+	if offset, err := file.Seek(-4, io.SeekEnd); err != nil || offset != 16 {
+		t.Error("Unexpected seek", offset, err)
+	} else if rest, err := ioutil.ReadAll(file); err != nil || string(rest) != "ghij" {
+		t.Error("Unexpected read", string(rest), err)
+	}
+
+	// Read at an offset, and past the end
+	if n, err := file.ReadAt(buf, 10); err != nil || n != 4 || string(buf) != "abcd" {
+		t.Error("Unexpected read", n, string(buf), err)
+	} else if n, err := file.ReadAt(buf, 18); err != io.EOF || n != 2 || string(buf[:n]) != "ij" {
+		t.Error("Unexpected read", n, string(buf[:n]), err)
+	} else if _, err := file.ReadAt(buf, 20); err != io.EOF {
+		t.Error("Expected EOF, got", err)
+	}
+}
@@ -0,0 +1,144 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// ReplayGain is the gain in decibels to play a track or album at a
+// loudness of -18 LUFS, and the peak sample level where 1.0 is full
+// scale. Track and Album are true when the gain is known, and a peak
+// of zero is unknown.
+type ReplayGain struct {
+	TrackGain, TrackPeak float64
+	AlbumGain, AlbumPeak float64
+	Track, Album         bool
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// R128 gains in Opus files are relative to -23 LUFS
+	R128_OFFSET = 5.0
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// REPLAYGAIN
+
+// ReplayGainTag returns the metadata key and value for a ReplayGain tag
+// such as "REPLAYGAIN_TRACK_GAIN" with a value of "-6.20 dB", or an R128
+// tag such as "R128_TRACK_GAIN", which is converted from 1/256 dB
+// relative to -23 LUFS. It returns METADATA_KEY_NONE if the tag is not
+// for gain, or gopi.ErrBadParameter if the value cannot be parsed.
+func ReplayGainTag(tag, value string) (media.MetadataKey, string, error) {
+	var key media.MetadataKey
+	switch strings.ToLower(tag) {
+	case "replaygain_track_gain", "r128_track_gain":
+		key = media.METADATA_KEY_TRACK_GAIN
+	case "replaygain_track_peak":
+		key = media.METADATA_KEY_TRACK_PEAK
+	case "replaygain_album_gain", "r128_album_gain":
+		key = media.METADATA_KEY_ALBUM_GAIN
+	case "replaygain_album_peak":
+		key = media.METADATA_KEY_ALBUM_PEAK
+	default:
+		return media.METADATA_KEY_NONE, "", nil
+	}
+	if strings.HasPrefix(strings.ToLower(tag), "r128_") {
+		if q, err := strconv.ParseInt(strings.TrimSpace(value), 10, 16); err != nil {
+			return media.METADATA_KEY_NONE, "", gopi.ErrBadParameter
+		} else {
+			return key, formatGain(float64(q)/256 + R128_OFFSET), nil
+		}
+	}
+	if number, err := parseGain(value); err != nil {
+		return media.METADATA_KEY_NONE, "", err
+	} else {
+		return key, formatGain(number), nil
+	}
+}
+
+// ReplayGainFor returns the gain for an item, or false if the item has
+// neither a track or album gain
+func ReplayGainFor(item media.MediaItem) (ReplayGain, bool) {
+	g := ReplayGain{}
+	if value, err := parseGain(item.StringForKey(media.METADATA_KEY_TRACK_GAIN)); err == nil {
+		g.TrackGain, g.Track = value, true
+	}
+	if value, err := parseGain(item.StringForKey(media.METADATA_KEY_ALBUM_GAIN)); err == nil {
+		g.AlbumGain, g.Album = value, true
+	}
+	g.TrackPeak, _ = parseGain(item.StringForKey(media.METADATA_KEY_TRACK_PEAK))
+	g.AlbumPeak, _ = parseGain(item.StringForKey(media.METADATA_KEY_ALBUM_PEAK))
+	return g, g.Track || g.Album
+}
+
+// Keys returns the metadata for the known gains and peaks
+func (g ReplayGain) Keys() map[media.MetadataKey]string {
+	keys := make(map[media.MetadataKey]string, 4)
+	if g.Track {
+		keys[media.METADATA_KEY_TRACK_GAIN] = formatGain(g.TrackGain)
+	}
+	if g.Album {
+		keys[media.METADATA_KEY_ALBUM_GAIN] = formatGain(g.AlbumGain)
+	}
+	if g.TrackPeak > 0 {
+		keys[media.METADATA_KEY_TRACK_PEAK] = formatGain(g.TrackPeak)
+	}
+	if g.AlbumPeak > 0 {
+		keys[media.METADATA_KEY_ALBUM_PEAK] = formatGain(g.AlbumPeak)
+	}
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (g ReplayGain) String() string {
+	str := "<util.ReplayGain>{"
+	if g.Track {
+		str += fmt.Sprintf(" track_gain=%vdB track_peak=%v", formatGain(g.TrackGain), formatGain(g.TrackPeak))
+	}
+	if g.Album {
+		str += fmt.Sprintf(" album_gain=%vdB album_peak=%v", formatGain(g.AlbumGain), formatGain(g.AlbumPeak))
+	}
+	return str + " }"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// parseGain parses a number with an optional "dB" suffix
+func parseGain(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if len(value) > 2 && strings.EqualFold(value[len(value)-2:], "db") {
+		value = strings.TrimSpace(value[:len(value)-2])
+	}
+	if number, err := strconv.ParseFloat(value, 64); err != nil {
+		return 0, gopi.ErrBadParameter
+	} else {
+		return number, nil
+	}
+}
+
+func formatGain(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,59 @@
+package util_test
+
+import (
+	"testing"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	mock "github.com/djthorpe/gopi-media/mock"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST REPLAYGAIN
+
+func Test_replaygain_000(t *testing.T) {
+	t.Log("Test_replaygain_000")
+}
+
+func Test_replaygain_001(t *testing.T) {
+	for _, test := range []struct {
+		tag, value string
+		key        media.MetadataKey
+		expected   string
+	}{
+		{"REPLAYGAIN_TRACK_GAIN", "-6.20 dB", media.METADATA_KEY_TRACK_GAIN, "-6.2"},
+		{"replaygain_track_peak", "0.988525", media.METADATA_KEY_TRACK_PEAK, "0.988525"},
+		{"REPLAYGAIN_ALBUM_GAIN", "+1.5dB", media.METADATA_KEY_ALBUM_GAIN, "1.5"},
+		{"REPLAYGAIN_ALBUM_PEAK", "1.2", media.METADATA_KEY_ALBUM_PEAK, "1.2"},
+		{"R128_TRACK_GAIN", "-1536", media.METADATA_KEY_TRACK_GAIN, "-1"},
+		{"R128_ALBUM_GAIN", "128", media.METADATA_KEY_ALBUM_GAIN, "5.5"},
+		{"title", "-6.20 dB", media.METADATA_KEY_NONE, ""},
+	} {
+		if key, value, err := util.ReplayGainTag(test.tag, test.value); err != nil {
+			t.Error(test.tag, err)
+		} else if key != test.key || value != test.expected {
+			t.Error(test.tag, "Unexpected value", key, value)
+		}
+	}
+	for _, tag := range []string{"REPLAYGAIN_TRACK_GAIN", "R128_TRACK_GAIN"} {
+		if _, _, err := util.ReplayGainTag(tag, "loud"); err != gopi.ErrBadParameter {
+			t.Error("Expected ErrBadParameter for", tag, err)
+		}
+	}
+}
+
+func Test_replaygain_002(t *testing.T) {
+	keys := util.ReplayGain{TrackGain: -6.2, TrackPeak: 0.98, Track: true}.Keys()
+	if len(keys) != 2 {
+		t.Error("Unexpected keys", keys)
+	}
+	file := &mock.File{Path: "track.flac", Metadata: keys}
+	if g, ok := util.ReplayGainFor(file); ok == false || g.Track == false || g.Album || g.TrackGain != -6.2 || g.TrackPeak != 0.98 {
+		t.Error("Unexpected value", g, ok)
+	}
+	if _, ok := util.ReplayGainFor(&mock.File{Path: "track.mp3"}); ok {
+		t.Error("Expected no gain")
+	}
+}
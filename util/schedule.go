@@ -0,0 +1,190 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Window is a daily period in local time, from Start to End as the
+// time since midnight. A window where End is before Start spans
+// midnight, and a window where they are the same is the whole day.
+type Window struct {
+	Start, End time.Duration
+}
+
+// Limiter limits the total rate at which data is read by all the
+// readers it wraps. The zero value has no limit and is safe for
+// concurrent use.
+type Limiter struct {
+	lock sync.Mutex
+	rate uint
+	next time.Time
+}
+
+type limitReader struct {
+	io.ReadCloser
+	limiter *Limiter
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Reads are split so each takes at most this long at the rate limit
+	LIMITER_READ_INTERVAL = 250 * time.Millisecond
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// WINDOWS
+
+// ParseWindows parses a comma-separated list of windows in the form
+// HH:MM-HH:MM, for example "01:00-07:00,13:00-15:00"
+func ParseWindows(value string) ([]Window, error) {
+	windows := make([]Window, 0, 1)
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		var h1, m1, h2, m2 uint
+		if _, err := fmt.Sscanf(field, "%d:%d-%d:%d", &h1, &m1, &h2, &m2); err != nil {
+			return nil, gopi.ErrBadParameter
+		} else if h1 > 24 || h2 > 24 || m1 > 59 || m2 > 59 || (h1 == 24 && m1 > 0) || (h2 == 24 && m2 > 0) {
+			return nil, gopi.ErrBadParameter
+		}
+		windows = append(windows, Window{
+			Start: time.Duration(h1)*time.Hour + time.Duration(m1)*time.Minute,
+			End:   time.Duration(h2)*time.Hour + time.Duration(m2)*time.Minute,
+		})
+	}
+	return windows, nil
+}
+
+// Contains returns true if a time is within the window
+func (this Window) Contains(t time.Time) bool {
+	since := t.Sub(midnight(t, 0))
+	switch {
+	case this.Start == this.End:
+		return true
+	case this.Start < this.End:
+		return since >= this.Start && since < this.End
+	default:
+		return since >= this.Start || since < this.End
+	}
+}
+
+// Next returns the time the window next opens, which is t if the
+// window is open
+func (this Window) Next(t time.Time) time.Time {
+	if this.Contains(t) {
+		return t
+	} else if start := midnight(t, 0).Add(this.Start); start.After(t) {
+		return start
+	} else {
+		return midnight(t, 1).Add(this.Start)
+	}
+}
+
+// NextWindow returns the time the first of the windows next opens, or
+// t if one is open or there are no windows
+func NextWindow(windows []Window, t time.Time) time.Time {
+	next := time.Time{}
+	for _, window := range windows {
+		if when := window.Next(t); next.IsZero() || when.Before(next) {
+			next = when
+		}
+	}
+	if next.IsZero() {
+		return t
+	}
+	return next
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// LIMITER
+
+// SetRate sets the limit in bytes per second, or removes the limit
+// when zero
+func (this *Limiter) SetRate(rate uint) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.rate = rate
+}
+
+// Rate returns the limit in bytes per second, or zero
+func (this *Limiter) Rate() uint {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.rate
+}
+
+// Reader returns a reader which is limited to the rate. The rate is
+// shared with all other readers from the limiter.
+func (this *Limiter) Reader(r io.ReadCloser) io.ReadCloser {
+	return &limitReader{r, this}
+}
+
+func (this *limitReader) Read(data []byte) (int, error) {
+	if max := int(time.Duration(this.limiter.Rate()) * LIMITER_READ_INTERVAL / time.Second); max > 0 && len(data) > max {
+		data = data[:max]
+	}
+	n, err := this.ReadCloser.Read(data)
+	this.limiter.wait(n)
+	return n, err
+}
+
+// wait sleeps until n bytes can be read at the rate, reserving the
+// time for the next read
+func (this *Limiter) wait(n int) {
+	this.lock.Lock()
+	if this.rate == 0 {
+		this.lock.Unlock()
+		return
+	}
+	now := time.Now()
+	if this.next.Before(now) {
+		this.next = now
+	}
+	this.next = this.next.Add(time.Duration(n) * time.Second / time.Duration(this.rate))
+	d := this.next.Sub(now)
+	this.lock.Unlock()
+
+	time.Sleep(d)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", int(this.Start.Hours()), int(this.Start.Minutes())%60, int(this.End.Hours()), int(this.End.Minutes())%60)
+}
+
+func (this *Limiter) String() string {
+	return fmt.Sprintf("<util.Limiter>{ rate=%v }", this.Rate())
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// midnight returns the start of the day for a time, plus a number of days
+func midnight(t time.Time, days int) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+days, 0, 0, 0, 0, t.Location())
+}
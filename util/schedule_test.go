@@ -0,0 +1,125 @@
+package util_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// Frameworks
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST SCHEDULE
+
+func Test_schedule_000(t *testing.T) {
+	t.Log("Test_schedule_000")
+}
+
+func Test_schedule_001(t *testing.T) {
+	windows, err := util.ParseWindows("01:00-07:00, 23:30-00:30")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(windows) != 2 {
+		t.Fatal("Unexpected windows", windows)
+	} else if windows[0].String() != "01:00-07:00" || windows[1].String() != "23:30-00:30" {
+		t.Error("Unexpected windows", windows)
+	}
+	for _, value := range []string{"1:00", "25:00-01:00", "01:60-02:00", "24:01-01:00"} {
+		if _, err := util.ParseWindows(value); err == nil {
+			t.Error("Expected error for", value)
+		}
+	}
+}
+
+func Test_schedule_002(t *testing.T) {
+	windows, _ := util.ParseWindows("01:00-07:00,23:30-00:30")
+	at := func(hour, minute int) time.Time {
+		return time.Date(2019, 6, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	// Inside the windows, including the one which spans midnight
+	for _, t0 := range []time.Time{at(1, 0), at(6, 59), at(23, 45), at(0, 15)} {
+		if next := util.NextWindow(windows, t0); next.Equal(t0) == false {
+			t.Error("Expected window to be open at", t0, "next", next)
+		}
+	}
+
+	// Outside the windows
+	if next := util.NextWindow(windows, at(12, 0)); next.Equal(at(23, 30)) == false {
+		t.Error("Unexpected next window", next)
+	}
+	if next := util.NextWindow(windows, at(0, 45)); next.Equal(at(1, 0)) == false {
+		t.Error("Unexpected next window", next)
+	}
+	if next := windows[0].Next(at(8, 0)); next.Equal(at(1, 0).AddDate(0, 0, 1)) == false {
+		t.Error("Unexpected next window", next)
+	}
+
+	// No windows means any time
+	if next := util.NextWindow(nil, at(12, 0)); next.Equal(at(12, 0)) == false {
+		t.Error("Unexpected next window", next)
+	}
+}
+
+func Test_schedule_003(t *testing.T) {
+	// Two readers share a limit of 4000 bytes per second
+	limiter := new(util.Limiter)
+	limiter.SetRate(4000)
+	r1 := limiter.Reader(ioutil.NopCloser(bytes.NewReader(make([]byte, 1000))))
+	r2 := limiter.Reader(ioutil.NopCloser(bytes.NewReader(make([]byte, 1000))))
+	start := time.Now()
+	if _, err := io.Copy(ioutil.Discard, r1); err != nil {
+		t.Error(err)
+	} else if _, err := io.Copy(ioutil.Discard, r2); err != nil {
+		t.Error(err)
+	}
+	if d := time.Since(start); d < 400*time.Millisecond || d > 2*time.Second {
+		t.Error("Unexpected time for reads", d)
+	}
+
+	// Without a limit reads are not delayed
+	limiter.SetRate(0)
+	r3 := limiter.Reader(ioutil.NopCloser(bytes.NewReader(make([]byte, 100000))))
+	start = time.Now()
+	if _, err := io.Copy(ioutil.Discard, r3); err != nil {
+		t.Error(err)
+	} else if d := time.Since(start); d > 100*time.Millisecond {
+		t.Error("Unexpected time for reads", d)
+	}
+}
+
+func Test_schedule_004(t *testing.T) {
+	// Requests outside the windows wait until one opens
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	since := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+	transport := new(util.Transport)
+	transport.SetWindows([]util.Window{{Start: (since + time.Hour) % (24 * time.Hour), End: (since + 2*time.Hour) % (24 * time.Hour)}})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if req, err := http.NewRequest("GET", server.URL, nil); err != nil {
+		t.Fatal(err)
+	} else if _, err := transport.RoundTrip(req.WithContext(ctx)); err != context.DeadlineExceeded {
+		t.Error("Expected DeadlineExceeded, got", err)
+	}
+
+	// Requests are made at any time without windows
+	transport.SetWindows(nil)
+	if req, err := http.NewRequest("GET", server.URL, nil); err != nil {
+		t.Fatal(err)
+	} else if resp, err := transport.RoundTrip(req); err != nil {
+		t.Error(err)
+	} else {
+		resp.Body.Close()
+	}
+}
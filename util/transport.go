@@ -0,0 +1,395 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package util
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Transport is an http.RoundTripper for online services, which limits
+// the rate of requests to each host, retries failed requests with
+// backoff and caches successful responses. A single transport should
+// be shared by all the clients for a service so the limits apply to
+// all requests. The zero value passes requests to http.DefaultTransport
+// without limits.
+type Transport struct {
+	// Base is the transport used for requests, or http.DefaultTransport
+	// when nil
+	Base http.RoundTripper
+
+	// Interval is the minimum time between requests to a host, and
+	// Intervals overrides the interval for particular hosts
+	Interval  time.Duration
+	Intervals map[string]time.Duration
+
+	// Retries is the number of times a GET or HEAD request is retried
+	// after an error or a 429 or 5xx response, waiting Backoff before
+	// the first retry and doubling it for each subsequent retry
+	Retries uint
+	Backoff time.Duration
+
+	// CacheTime is how long successful GET responses are cached, with
+	// a max-age in the response taking precedence. Responses are not
+	// cached when zero, or for range and authenticated requests.
+	CacheTime time.Duration
+
+	// Network is the switch for network access. When it is offline,
+	// cached responses are still returned but other requests fail with
+	// ErrOffline, which http.Client wraps in a *url.Error.
+	Network *Network
+
+	// Windows are the times of day when requests can be made, where
+	// requests outside the windows wait until one opens. Requests can
+	// be made at any time when there are no windows. Use SetWindows to
+	// change the windows once requests are being made. Limiter limits
+	// the rate at which response bodies are read.
+	Windows []Window
+	Limiter *Limiter
+
+	lock  sync.Mutex
+	next  map[string]time.Time
+	cache map[string]*cachedResponse
+}
+
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	// Maximum size of a cached response body, and number of cached
+	// responses
+	TRANSPORT_CACHE_MAXSIZE    = 1024 * 1024
+	TRANSPORT_CACHE_MAXENTRIES = 1024
+
+	// Maximum time to wait when a server responds with Retry-After
+	TRANSPORT_MAX_RETRY_AFTER = time.Minute
+
+	// Retries and backoff for DefaultTransport and BackgroundTransport
+	TRANSPORT_DEFAULT_RETRIES = 2
+	TRANSPORT_DEFAULT_BACKOFF = time.Second
+)
+
+var (
+	// DefaultTransport is shared by the packages in this module when
+	// they are not passed a client, so all their requests are retried
+	// in the same way. It is used for sources and streams, so requests
+	// are made even when DefaultNetwork is offline.
+	DefaultTransport = &Transport{
+		Retries: TRANSPORT_DEFAULT_RETRIES,
+		Backoff: TRANSPORT_DEFAULT_BACKOFF,
+	}
+
+	// BackgroundTransport is used instead for background tasks such as
+	// scrapers, podcast refresh and artwork fetch, where requests fail
+	// when DefaultNetwork is offline. The windows and rate limit are
+	// set so background tasks don't compete with streaming.
+	BackgroundTransport = &Transport{
+		Retries: TRANSPORT_DEFAULT_RETRIES,
+		Backoff: TRANSPORT_DEFAULT_BACKOFF,
+		Network: DefaultNetwork,
+		Limiter: new(Limiter),
+	}
+
+	// DefaultClient uses DefaultTransport and BackgroundClient uses
+	// BackgroundTransport
+	DefaultClient    = NewClient(DefaultTransport)
+	BackgroundClient = NewClient(BackgroundTransport)
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// CLIENT
+
+// NewClient returns an HTTP client which uses the transport
+func NewClient(transport *Transport) *http.Client {
+	return &http.Client{Transport: transport}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// ROUNDTRIPPER INTERFACE IMPLEMENTATION
+
+// RoundTrip makes a request, returning a cached response when there
+// is one and otherwise waiting for the rate limit for the host
+func (this *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := ""
+	if req.Method == http.MethodGet && this.CacheTime > 0 && cacheable(req) {
+		key = req.URL.String()
+		if resp := this.cached(key, req); resp != nil {
+			return resp, nil
+		}
+	}
+	if this.Network != nil && this.Network.Skip(req.Method+" "+req.URL.Host) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrOffline
+	}
+
+	// Only requests without a body can be retried
+	retries := this.Retries
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || (req.Body != nil && req.Body != http.NoBody) {
+		retries = 0
+	}
+
+	backoff := this.Backoff
+	if err := this.waitWindow(req); err != nil {
+		return nil, err
+	}
+	for attempt := uint(0); ; attempt++ {
+		if err := this.wait(req); err != nil {
+			return nil, err
+		}
+		resp, err := this.base().RoundTrip(req)
+		if err == nil && this.Limiter != nil {
+			resp.Body = this.Limiter.Reader(resp.Body)
+		}
+		if attempt >= retries || retryable(resp, err) == false {
+			if err == nil && key != "" {
+				resp = this.store(key, resp)
+			}
+			return resp, err
+		}
+
+		// Wait before retrying, using Retry-After when provided
+		delay := backoff
+		if resp != nil {
+			if after := retryAfter(resp); after > delay {
+				delay = after
+			}
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		backoff *= 2
+	}
+}
+
+// SetWindows changes the times of day when requests can be made, or
+// removes the restriction when there are no windows
+func (this *Transport) SetWindows(windows []Window) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.Windows = windows
+}
+
+// Purge removes all cached responses
+func (this *Transport) Purge() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.cache = nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+func (this *Transport) base() http.RoundTripper {
+	if this.Base != nil {
+		return this.Base
+	} else {
+		return http.DefaultTransport
+	}
+}
+
+// wait reserves the next slot for the host and waits until it is due
+func (this *Transport) wait(req *http.Request) error {
+	host := req.URL.Host
+	interval := this.Interval
+	if value, exists := this.Intervals[host]; exists {
+		interval = value
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	this.lock.Lock()
+	if this.next == nil {
+		this.next = make(map[string]time.Time)
+	}
+	now := time.Now()
+	due := this.next[host]
+	if due.Before(now) {
+		due = now
+	}
+	this.next[host] = due.Add(interval)
+	this.lock.Unlock()
+
+	if d := due.Sub(now); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+	return nil
+}
+
+// waitWindow waits until one of the windows is open
+func (this *Transport) waitWindow(req *http.Request) error {
+	this.lock.Lock()
+	windows := this.Windows
+	this.lock.Unlock()
+	if d := time.Until(NextWindow(windows, time.Now())); d > 0 {
+		select {
+		case <-time.After(d):
+		case <-req.Context().Done():
+			return req.Context().Err()
+		}
+	}
+	return nil
+}
+
+// cached returns a cached response for a request, or nil
+func (this *Transport) cached(key string, req *http.Request) *http.Response {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	entry, exists := this.cache[key]
+	if exists == false {
+		return nil
+	} else if time.Now().After(entry.expires) {
+		delete(this.cache, key)
+		return nil
+	}
+	header := cloneHeader(entry.header)
+	return &http.Response{
+		Status:        strconv.Itoa(entry.status) + " " + http.StatusText(entry.status),
+		StatusCode:    entry.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+		Request:       req,
+	}
+}
+
+// store caches a successful response which is small enough to keep
+// in memory, and returns a response with the body which was read
+func (this *Transport) store(key string, resp *http.Response) *http.Response {
+	if resp.StatusCode != http.StatusOK {
+		return resp
+	}
+	expires := cacheExpires(resp.Header, this.CacheTime)
+	if expires.IsZero() {
+		return resp
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, TRANSPORT_CACHE_MAXSIZE+1))
+	if err != nil || len(data) > TRANSPORT_CACHE_MAXSIZE {
+		// Return the data already read followed by the rest of the body
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		return resp
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.cache == nil {
+		this.cache = make(map[string]*cachedResponse)
+	}
+	if len(this.cache) >= TRANSPORT_CACHE_MAXENTRIES {
+		this.expireLocked()
+	}
+	// Keep a copy of the header, which the caller may change
+	this.cache[key] = &cachedResponse{resp.StatusCode, cloneHeader(resp.Header), data, expires}
+	return resp
+}
+
+// cloneHeader returns a copy of a header which shares no values
+func cloneHeader(header http.Header) http.Header {
+	clone := make(http.Header, len(header))
+	for k, v := range header {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
+
+// expireLocked removes expired entries, or all entries if none have
+// expired
+func (this *Transport) expireLocked() {
+	now := time.Now()
+	for key, entry := range this.cache {
+		if now.After(entry.expires) {
+			delete(this.cache, key)
+		}
+	}
+	if len(this.cache) >= TRANSPORT_CACHE_MAXENTRIES {
+		this.cache = make(map[string]*cachedResponse)
+	}
+}
+
+// cacheable returns false for requests where the response depends on
+// more than the URL, which are range and authenticated requests
+func cacheable(req *http.Request) bool {
+	return req.Header.Get("Range") == "" && req.Header.Get("Authorization") == ""
+}
+
+// cacheExpires returns the expiry time for a response, or zero if the
+// response should not be cached
+func cacheExpires(header http.Header, ttl time.Duration) time.Time {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if directive == "no-store" || directive == "no-cache" || directive == "private" {
+			return time.Time{}
+		} else if strings.HasPrefix(directive, "max-age=") {
+			if seconds, err := strconv.ParseUint(strings.TrimPrefix(directive, "max-age="), 10, 32); err == nil {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// retryable returns true if a request should be retried
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryAfter returns the delay from a Retry-After header in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	if seconds, err := strconv.ParseUint(strings.TrimSpace(resp.Header.Get("Retry-After")), 10, 32); err != nil {
+		return 0
+	} else if delay := time.Duration(seconds) * time.Second; delay > TRANSPORT_MAX_RETRY_AFTER {
+		return TRANSPORT_MAX_RETRY_AFTER
+	} else {
+		return delay
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
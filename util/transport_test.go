@@ -0,0 +1,187 @@
+package util_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// Frameworks
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST TRANSPORT
+
+func Test_transport_000(t *testing.T) {
+	t.Log("Test_transport_000")
+}
+
+func Test_transport_001(t *testing.T) {
+	// Requests to the same host are spaced by the interval
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&count, 1)
+	}))
+	defer server.Close()
+
+	client := util.NewClient(&util.Transport{Interval: 50 * time.Millisecond})
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if resp, err := client.Get(server.URL); err != nil {
+			t.Fatal(err)
+		} else {
+			resp.Body.Close()
+		}
+	}
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Error("Expected requests to be rate limited", d)
+	}
+	if count != 3 {
+		t.Error("Unexpected request count", count)
+	}
+}
+
+func Test_transport_002(t *testing.T) {
+	// Requests are retried after 429 and 5xx responses
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch atomic.AddInt32(&count, 1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	client := util.NewClient(&util.Transport{Retries: 3, Backoff: time.Millisecond})
+	if resp, err := client.Get(server.URL); err != nil {
+		t.Fatal(err)
+	} else if resp.StatusCode != http.StatusOK {
+		t.Error("Unexpected status", resp.Status)
+	} else {
+		resp.Body.Close()
+	}
+	if count != 3 {
+		t.Error("Unexpected request count", count)
+	}
+
+	// Without retries the first error is returned
+	atomic.StoreInt32(&count, 0)
+	client = util.NewClient(&util.Transport{})
+	if resp, err := client.Get(server.URL); err != nil {
+		t.Fatal(err)
+	} else if resp.StatusCode != http.StatusTooManyRequests {
+		t.Error("Unexpected status", resp.Status)
+	} else {
+		resp.Body.Close()
+	}
+}
+
+func Test_transport_003(t *testing.T) {
+	// Successful responses are cached unless the server says otherwise
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&count, 1)
+		if req.URL.Path == "/nostore" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	transport := &util.Transport{CacheTime: time.Minute}
+	client := util.NewClient(transport)
+	for i := 0; i < 3; i++ {
+		if resp, err := client.Get(server.URL + "/cached"); err != nil {
+			t.Fatal(err)
+		} else if data, err := ioutil.ReadAll(resp.Body); err != nil {
+			t.Error(err)
+		} else if string(data) != "hello" {
+			t.Error("Unexpected body", string(data))
+		} else {
+			resp.Body.Close()
+		}
+	}
+	if count != 1 {
+		t.Error("Expected cached response, requests =", count)
+	}
+	for i := 0; i < 2; i++ {
+		if resp, err := client.Get(server.URL + "/nostore"); err != nil {
+			t.Fatal(err)
+		} else {
+			resp.Body.Close()
+		}
+	}
+	if count != 3 {
+		t.Error("Expected uncached response, requests =", count)
+	}
+	transport.Purge()
+	if resp, err := client.Get(server.URL + "/cached"); err != nil {
+		t.Fatal(err)
+	} else {
+		resp.Body.Close()
+	}
+	if count != 4 {
+		t.Error("Expected purged cache, requests =", count)
+	}
+
+	// Range and authenticated requests are not cached
+	for _, header := range []string{"Range", "Authorization"} {
+		req, _ := http.NewRequest("GET", server.URL+"/cached", nil)
+		req.Header.Set(header, "value")
+		if resp, err := client.Do(req); err != nil {
+			t.Fatal(err)
+		} else {
+			resp.Body.Close()
+		}
+	}
+	if count != 6 {
+		t.Error("Expected uncached response, requests =", count)
+	}
+}
+
+func Test_transport_004(t *testing.T) {
+	// Requests with an empty body are retried
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.Header().Set("X-Value", "server")
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	transport := &util.Transport{Retries: 1, Backoff: time.Millisecond, CacheTime: time.Minute}
+	client := util.NewClient(transport)
+	req, _ := http.NewRequest("GET", server.URL, strings.NewReader(""))
+	if req.Body != http.NoBody {
+		t.Fatal("Expected empty body")
+	} else if resp, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	} else if resp.StatusCode != http.StatusOK {
+		t.Error("Unexpected status", resp.Status)
+	} else {
+		// Changing the header does not change the cached response
+		resp.Header.Set("X-Value", "client")
+		resp.Body.Close()
+	}
+	if resp, err := client.Get(server.URL); err != nil {
+		t.Fatal(err)
+	} else if value := resp.Header.Get("X-Value"); value != "server" {
+		t.Error("Unexpected cached header", value)
+	} else {
+		resp.Body.Close()
+	}
+	if count != 2 {
+		t.Error("Unexpected request count", count)
+	}
+}
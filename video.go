@@ -0,0 +1,167 @@
+/*
+	Go Language Raspberry Pi Interface
+	(c) Copyright David Thorpe 2019
+	All Rights Reserved
+	For Licensing and Usage information, please see LICENSE.md
+*/
+
+package media
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// FieldOrder is the order in which the fields of interlaced video are
+// displayed
+type FieldOrder uint
+
+// ColorPrimaries is the colour gamut of video
+type ColorPrimaries uint
+
+// ColorTransfer is the transfer function of video, which is how pixel
+// values relate to the brightness displayed
+type ColorTransfer uint
+
+// DeinterlaceMode selects how interlaced video is deinterlaced before
+// it is rendered
+type DeinterlaceMode uint
+
+////////////////////////////////////////////////////////////////////////////////
+// INTERFACES
+
+// VideoStream is a MediaStream of video, which is returned by drivers
+// which can probe the video format
+type VideoStream interface {
+	MediaStream
+
+	// Return the size of each frame in pixels
+	Width() uint
+	Height() uint
+
+	// Return the field order, which is FIELD_ORDER_UNKNOWN when the
+	// container and codec don't signal whether the video is interlaced
+	FieldOrder() FieldOrder
+
+	// Return the colour primaries and transfer function, which are
+	// unknown when not signalled, and the bits per colour component,
+	// or zero if unknown
+	ColorPrimaries() ColorPrimaries
+	ColorTransfer() ColorTransfer
+	BitDepth() uint
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	FIELD_ORDER_UNKNOWN      FieldOrder = iota
+	FIELD_ORDER_PROGRESSIVE             // Not interlaced
+	FIELD_ORDER_TOP_FIRST               // Interlaced, top field displayed first
+	FIELD_ORDER_BOTTOM_FIRST            // Interlaced, bottom field displayed first
+	FIELD_ORDER_MAX          = FIELD_ORDER_BOTTOM_FIRST
+)
+
+const (
+	COLOR_PRIMARIES_UNKNOWN ColorPrimaries = iota
+	COLOR_PRIMARIES_BT601                  // Standard definition
+	COLOR_PRIMARIES_BT709                  // High definition
+	COLOR_PRIMARIES_BT2020                 // Ultra high definition and HDR
+	COLOR_PRIMARIES_P3                     // Digital cinema
+	COLOR_PRIMARIES_MAX     = COLOR_PRIMARIES_P3
+)
+
+const (
+	COLOR_TRANSFER_UNKNOWN ColorTransfer = iota
+	COLOR_TRANSFER_SDR                   // Gamma, including BT.709 and sRGB
+	COLOR_TRANSFER_PQ                    // SMPTE ST 2084, for HDR10 and Dolby Vision
+	COLOR_TRANSFER_HLG                   // Hybrid log-gamma, for broadcast HDR
+	COLOR_TRANSFER_MAX     = COLOR_TRANSFER_HLG
+)
+
+const (
+	DEINTERLACE_AUTO  DeinterlaceMode = iota // Deinterlace when the stream is interlaced
+	DEINTERLACE_NONE                         // Never deinterlace
+	DEINTERLACE_WEAVE                        // Combine both fields into each frame
+	DEINTERLACE_BOB                          // Display each field as a frame, doubling the lines
+	DEINTERLACE_MMAL                         // Use the MMAL image_fx component on the GPU
+	DEINTERLACE_MAX   = DEINTERLACE_MMAL
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// FIELD ORDER
+
+// Interlaced returns true if the field order is for interlaced video
+func (f FieldOrder) Interlaced() bool {
+	return f == FIELD_ORDER_TOP_FIRST || f == FIELD_ORDER_BOTTOM_FIRST
+}
+
+// HDR returns true if the transfer function is for high dynamic range
+func (t ColorTransfer) HDR() bool {
+	return t == COLOR_TRANSFER_PQ || t == COLOR_TRANSFER_HLG
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (p ColorPrimaries) String() string {
+	switch p {
+	case COLOR_PRIMARIES_UNKNOWN:
+		return "COLOR_PRIMARIES_UNKNOWN"
+	case COLOR_PRIMARIES_BT601:
+		return "COLOR_PRIMARIES_BT601"
+	case COLOR_PRIMARIES_BT709:
+		return "COLOR_PRIMARIES_BT709"
+	case COLOR_PRIMARIES_BT2020:
+		return "COLOR_PRIMARIES_BT2020"
+	case COLOR_PRIMARIES_P3:
+		return "COLOR_PRIMARIES_P3"
+	default:
+		return "[?? Invalid ColorPrimaries value]"
+	}
+}
+
+func (t ColorTransfer) String() string {
+	switch t {
+	case COLOR_TRANSFER_UNKNOWN:
+		return "COLOR_TRANSFER_UNKNOWN"
+	case COLOR_TRANSFER_SDR:
+		return "COLOR_TRANSFER_SDR"
+	case COLOR_TRANSFER_PQ:
+		return "COLOR_TRANSFER_PQ"
+	case COLOR_TRANSFER_HLG:
+		return "COLOR_TRANSFER_HLG"
+	default:
+		return "[?? Invalid ColorTransfer value]"
+	}
+}
+
+func (f FieldOrder) String() string {
+	switch f {
+	case FIELD_ORDER_UNKNOWN:
+		return "FIELD_ORDER_UNKNOWN"
+	case FIELD_ORDER_PROGRESSIVE:
+		return "FIELD_ORDER_PROGRESSIVE"
+	case FIELD_ORDER_TOP_FIRST:
+		return "FIELD_ORDER_TOP_FIRST"
+	case FIELD_ORDER_BOTTOM_FIRST:
+		return "FIELD_ORDER_BOTTOM_FIRST"
+	default:
+		return "[?? Invalid FieldOrder value]"
+	}
+}
+
+func (m DeinterlaceMode) String() string {
+	switch m {
+	case DEINTERLACE_AUTO:
+		return "DEINTERLACE_AUTO"
+	case DEINTERLACE_NONE:
+		return "DEINTERLACE_NONE"
+	case DEINTERLACE_WEAVE:
+		return "DEINTERLACE_WEAVE"
+	case DEINTERLACE_BOB:
+		return "DEINTERLACE_BOB"
+	case DEINTERLACE_MMAL:
+		return "DEINTERLACE_MMAL"
+	default:
+		return "[?? Invalid DeinterlaceMode value]"
+	}
+}
@@ -0,0 +1,245 @@
+/*
+  Go Language Raspberry Pi Interface
+  (c) Copyright David Thorpe 2019
+  All Rights Reserved
+
+  Documentation http://djthorpe.github.io/gopi/
+  For Licensing and Usage information, please see LICENSE.md
+*/
+
+package webdav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	media "github.com/djthorpe/gopi-media"
+	util "github.com/djthorpe/gopi-media/util"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TYPES
+
+// Client reads files from a WebDAV share, such as a Nextcloud folder.
+// Paths are relative to the endpoint and use forward slashes.
+type Client struct {
+	// Endpoint is the URL of the share, for example
+	// "https://cloud.example.com/remote.php/dav/files/user"
+	Endpoint string
+
+	// User and Password are used for basic authentication when set
+	User, Password string
+
+	// Client makes the requests, or util.DefaultClient when nil
+	Client *http.Client
+}
+
+// fileInfo implements os.FileInfo for files and collections
+type fileInfo struct {
+	name    string
+	size    int64
+	modtime time.Time
+	dir     bool
+}
+
+type multistatus struct {
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				Length       string `xml:"DAV: getcontentlength"`
+				LastModified string `xml:"DAV: getlastmodified"`
+				ResourceType struct {
+					Collection *struct{} `xml:"DAV: collection"`
+				} `xml:"DAV: resourcetype"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// CONSTANTS
+
+const (
+	propfind = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getcontentlength/><d:getlastmodified/><d:resourcetype/></d:prop></d:propfind>`
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// PUBLIC METHODS
+
+// Stat returns information about a file or collection, or
+// gopi.ErrNotFound if it does not exist
+func (this Client) Stat(name string) (os.FileInfo, error) {
+	if infos, err := this.propfind(name, "0"); err != nil {
+		return nil, err
+	} else if len(infos) == 0 {
+		return nil, gopi.ErrUnexpectedResponse
+	} else {
+		info := infos[0]
+		info.name = path.Base(path.Clean("/" + name))
+		return info, nil
+	}
+}
+
+// ReadDir returns the files and collections within a collection sorted
+// by name, or gopi.ErrNotFound if it does not exist
+func (this Client) ReadDir(name string) ([]os.FileInfo, error) {
+	infos, err := this.propfind(name, "1")
+	if err != nil {
+		return nil, err
+	}
+
+	// The first response is usually the collection itself
+	self := strings.TrimSuffix(this.path(name), "/")
+	result := make([]os.FileInfo, 0, len(infos))
+	for _, info := range infos {
+		if info.name != self {
+			info.name = path.Base(info.name)
+			result = append(result, info)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name() < result[j].Name() })
+	return result, nil
+}
+
+// Open returns a file which is read with range requests, or
+// gopi.ErrNotFound if it does not exist
+func (this Client) Open(name string) (media.SourceFile, error) {
+	info, err := this.Stat(name)
+	if err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		return nil, gopi.ErrBadParameter
+	}
+	target := this.url(name)
+	return util.NewRemoteFile(this.Client, info.Size(), func() (*http.Request, error) {
+		return this.request("GET", target, "")
+	}), nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// STRINGIFY
+
+func (this Client) String() string {
+	return fmt.Sprintf("<webdav.Client>{ endpoint=%v user=%v }", strconv.Quote(this.Endpoint), strconv.Quote(this.User))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// FILEINFO IMPLEMENTATION
+
+func (this *fileInfo) Name() string       { return this.name }
+func (this *fileInfo) Size() int64        { return this.size }
+func (this *fileInfo) ModTime() time.Time { return this.modtime }
+func (this *fileInfo) IsDir() bool        { return this.dir }
+func (this *fileInfo) Sys() interface{}   { return nil }
+
+func (this *fileInfo) Mode() os.FileMode {
+	if this.dir {
+		return os.ModeDir | 0555
+	} else {
+		return 0444
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// PRIVATE METHODS
+
+// url returns the URL for a path within the share
+func (this Client) url(name string) string {
+	escaped := (&url.URL{Path: path.Clean("/" + name)}).EscapedPath()
+	return strings.TrimSuffix(this.Endpoint, "/") + escaped
+}
+
+// path returns the unescaped absolute path on the server for a path
+// within the share
+func (this Client) path(name string) string {
+	if u, err := url.Parse(this.url(name)); err != nil {
+		return ""
+	} else {
+		return u.Path
+	}
+}
+
+func (this Client) request(method, target, body string) (*http.Request, error) {
+	// Requests without a body have a nil body so they can be retried
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	request, err := http.NewRequest(method, target, r)
+	if err != nil {
+		return nil, err
+	}
+	if this.User != "" || this.Password != "" {
+		request.SetBasicAuth(this.User, this.Password)
+	}
+	return request, nil
+}
+
+// propfind returns the properties of a path with a depth of "0" or "1",
+// where names are the unescaped paths on the server
+func (this Client) propfind(name, depth string) ([]*fileInfo, error) {
+	client := this.Client
+	if client == nil {
+		client = util.DefaultClient
+	}
+	request, err := this.request("PROPFIND", this.url(name), propfind)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Depth", depth)
+	request.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, gopi.ErrNotFound
+	} else if response.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("%v", response.Status)
+	}
+
+	var data multistatus
+	if err := xml.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	infos := make([]*fileInfo, 0, len(data.Responses))
+	for _, r := range data.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			continue
+		}
+		info := &fileInfo{name: strings.TrimSuffix(href.Path, "/")}
+		for _, propstat := range r.Propstat {
+			if strings.Contains(propstat.Status, " 200 ") == false {
+				continue
+			}
+			prop := propstat.Prop
+			if prop.ResourceType.Collection != nil {
+				info.dir = true
+			}
+			if size, err := strconv.ParseInt(strings.TrimSpace(prop.Length), 10, 64); err == nil {
+				info.size = size
+			}
+			if modtime, err := http.ParseTime(prop.LastModified); err == nil {
+				info.modtime = modtime
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,84 @@
+package webdav_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// Frameworks
+	gopi "github.com/djthorpe/gopi"
+	webdav "github.com/djthorpe/gopi-media/webdav"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// TEST CLIENT
+
+func Test_webdav_000(t *testing.T) {
+	t.Log("Test_webdav_000")
+}
+
+const (
+	folder = `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:">
+ <d:response><d:href>/dav/Music/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+ <d:response><d:href>/dav/Music/Track%20One.mp3</d:href><d:propstat><d:prop><d:getcontentlength>10</d:getcontentlength><d:getlastmodified>Mon, 02 Jan 2006 15:04:05 GMT</d:getlastmodified><d:resourcetype/></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+ <d:response><d:href>/dav/Music/Album/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+</d:multistatus>`
+	file = `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:">
+ <d:response><d:href>/dav/Music/Track%20One.mp3</d:href><d:propstat><d:prop><d:getcontentlength>10</d:getcontentlength><d:resourcetype/></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+</d:multistatus>`
+)
+
+func Test_webdav_001(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == "PROPFIND" && r.URL.Path == "/dav/Music" && r.Header.Get("Depth") == "1":
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(folder))
+		case r.Method == "PROPFIND" && r.URL.Path == "/dav/Music/Track One.mp3":
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(file))
+		case r.Method == "GET" && r.URL.Path == "/dav/Music/Track One.mp3":
+			http.ServeContent(w, r, "track.mp3", time.Time{}, bytes.NewReader([]byte("0123456789")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := webdav.Client{Endpoint: server.URL + "/dav/", User: "user", Password: "secret"}
+	if infos, err := client.ReadDir("Music"); err != nil {
+		t.Error(err)
+	} else if len(infos) != 2 || infos[0].Name() != "Album" || infos[0].IsDir() == false {
+		t.Error("Unexpected entries", infos)
+	} else if infos[1].Name() != "Track One.mp3" || infos[1].Size() != 10 || infos[1].ModTime().Year() != 2006 {
+		t.Error("Unexpected entry", infos[1])
+	}
+	if info, err := client.Stat("/Music/Track One.mp3"); err != nil {
+		t.Error(err)
+	} else if info.Name() != "Track One.mp3" || info.IsDir() {
+		t.Error("Unexpected info", info)
+	}
+	if f, err := client.Open("Music/Track One.mp3"); err != nil {
+		t.Error(err)
+	} else if data, err := ioutil.ReadAll(f); err != nil || string(data) != "0123456789" {
+		t.Error("Unexpected data", string(data), err)
+	} else {
+		f.Close()
+	}
+	if _, err := client.Stat("Missing"); err != gopi.ErrNotFound {
+		t.Error("Expected ErrNotFound, got", err)
+	}
+	client.Password = "wrong"
+	if _, err := client.ReadDir("Music"); err == nil {
+		t.Error("Expected error")
+	}
+}